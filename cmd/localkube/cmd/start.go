@@ -27,6 +27,7 @@ import (
 	"k8s.io/kubernetes/pkg/capabilities"
 	"k8s.io/kubernetes/pkg/kubelet/types"
 	"k8s.io/minikube/pkg/localkube"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/version"
 )
 
@@ -69,11 +70,10 @@ func SetupServer(s *localkube.LocalkubeServer) {
 	}
 	// localkube flags can handle `--container-runtime=remote --remote-runtime-endpoint=/var/run/crio/crio.sock --remote-image-endpoint=/var/run/crio/crio.sock`,
 	// but this allows for a convenience of just e.g.`--container-runtime=crio` and the same for minikube
-	switch s.ContainerRuntime {
-	case "crio", "cri-o":
+	if r, err := cruntime.New(s.ContainerRuntime); err == nil && r.SocketPath() != "" {
 		s.ContainerRuntime = "remote"
-		s.RemoteRuntimeEndpoint = "unix:///var/run/crio/crio.sock"
-		s.RemoteImageEndpoint = "unix:///var/run/crio/crio.sock"
+		s.RemoteRuntimeEndpoint = "unix://" + r.SocketPath()
+		s.RemoteImageEndpoint = "unix://" + r.SocketPath()
 	}
 
 	if s.ShouldGenerateCerts {
@@ -39,8 +39,13 @@ var addCacheCmd = &cobra.Command{
 	Short: "Add an image to local cache.",
 	Long:  "Add an image to local cache.",
 	Run: func(cmd *cobra.Command, args []string) {
-		// Cache and load images into docker daemon
-		if err := machine.CacheAndLoadImages(args); err != nil {
+		r, err := GetContainerRuntime()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting container runtime: %s\n", err)
+			os.Exit(1)
+		}
+		// Cache and load images into the container runtime
+		if err := machine.CacheAndLoadImages(r, args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error caching and loading images: %s\n", err)
 			os.Exit(1)
 		}
@@ -49,6 +54,7 @@ var addCacheCmd = &cobra.Command{
 			fmt.Fprintf(os.Stderr, "Error adding cached images to config file: %s\n", err)
 			os.Exit(1)
 		}
+		maybePruneImageCache()
 	},
 }
 
@@ -82,7 +88,11 @@ func LoadCachedImagesInConfigFile() error {
 		for key := range values.(map[string]interface{}) {
 			images = append(images, key)
 		}
-		return machine.CacheAndLoadImages(images)
+		r, err := GetContainerRuntime()
+		if err != nil {
+			return err
+		}
+		return machine.CacheAndLoadImages(r, images)
 	}
 	return nil
 }
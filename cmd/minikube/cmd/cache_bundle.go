@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/bundle"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	pkgutil "k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
+)
+
+var (
+	bundleISOURL            string
+	bundleKubernetesVersion string
+)
+
+// bundleCmd represents the cache bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Package the local cache for offline use.",
+	Long:  "Package the local cache into a bundle that minikube start --offline --bundle can use without network access.",
+}
+
+// exportBundleCmd represents the cache bundle export command
+var exportBundleCmd = &cobra.Command{
+	Use:   "export FILE",
+	Short: "Write the ISO, kubernetes binaries and images needed to start a cluster to a bundle.",
+	Long: `Write the ISO, kubernetes binaries and images needed to start a cluster to a bundle.
+Artifacts missing from the cache are downloaded first. The images are those of the bootstrapper, of the enabled addons and those added with minikube cache add.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube cache bundle export FILE")
+			os.Exit(1)
+		}
+		k8sVersion := bundleKubernetesVersion
+		if !strings.HasPrefix(k8sVersion, version.VersionPrefix) {
+			k8sVersion = version.VersionPrefix + k8sVersion
+		}
+		clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
+
+		images, err := bundle.Images(clusterBootstrapper, k8sVersion)
+		if err != nil {
+			glog.Errorln("Error listing images: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		// Fail before downloading anything for unsupported bootstrappers
+		if _, err := bundle.Artifacts(bundleISOURL, clusterBootstrapper, k8sVersion, images); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		if err := (pkgutil.DefaultDownloader{}).CacheMinikubeISOFromURL(bundleISOURL); err != nil {
+			glog.Errorln("Error caching ISO: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		if err := kubeadm.CacheBinaries(k8sVersion); err != nil {
+			glog.Errorln("Error caching kubernetes binaries: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Caching %d images...\n", len(images))
+		if err := machine.CacheImages(images, constants.ImageCacheDir); err != nil {
+			glog.Errorln("Error caching images: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+
+		m := bundle.Manifest{
+			MinikubeVersion:   version.GetVersion(),
+			Created:           time.Now(),
+			Bootstrapper:      clusterBootstrapper,
+			KubernetesVersion: k8sVersion,
+			ISOURL:            bundleISOURL,
+			Images:            images,
+		}
+		fmt.Printf("Writing bundle %s...\n", args[0])
+		if err := bundle.Export(args[0], m); err != nil {
+			glog.Errorln("Error writing bundle: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Bundle %s is ready, start a cluster from it with: minikube start --offline --bundle %s\n", args[0], args[0])
+	},
+}
+
+func init() {
+	exportBundleCmd.Flags().StringVar(&bundleISOURL, isoURL, constants.DefaultIsoUrl, "Location of the minikube iso to bundle")
+	exportBundleCmd.Flags().StringVar(&bundleKubernetesVersion, kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version to bundle (ex: v1.9.4)")
+	bundleCmd.AddCommand(exportBundleCmd)
+	cacheCmd.AddCommand(bundleCmd)
+}
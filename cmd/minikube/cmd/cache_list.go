@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"text/template"
 
 	"github.com/spf13/cobra"
@@ -32,6 +33,12 @@ type CacheListTemplate struct {
 	CacheImage string
 }
 
+// CacheList is the machine-readable output of cache list
+type CacheList struct {
+	cmdConfig.TypeMeta
+	Images []string `json:"images"`
+}
+
 // listCacheCmd represents the cache list command
 var listCacheCmd = &cobra.Command{
 	Use:   "list",
@@ -59,6 +66,11 @@ For the list of accessible variables for the template, see the struct values her
 }
 
 func cacheList(images []string) error {
+	if output := cmdConfig.GetOutputFormatOrExit(); output != cmdConfig.OutputText {
+		list := CacheList{TypeMeta: cmdConfig.NewTypeMeta("CacheList"), Images: append([]string{}, images...)}
+		sort.Strings(list.Images)
+		return cmdConfig.PrintStructured(os.Stdout, output, list)
+	}
 	for _, image := range images {
 		tmpl, err := template.New("list").Parse(cacheListFormat)
 		if err != nil {
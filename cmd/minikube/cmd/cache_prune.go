@@ -0,0 +1,134 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+var (
+	pruneOlderThan time.Duration
+	pruneMaxSize   string
+)
+
+// pruneCacheCmd represents the cache prune command
+var pruneCacheCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unused images from the local cache.",
+	Long: `Remove unused images from the local cache.
+Images not used for longer than --older-than are removed, then the least recently used images are removed until the cache fits in --max-size.
+Without flags, the cache is pruned to the cache-max-size setting.
+Images added with "minikube cache add" and the images of the Kubernetes version of the cluster are kept.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		maxSize := pruneMaxSize
+		if maxSize == "" && pruneOlderThan == 0 {
+			maxSize = viper.GetString(cmdConfig.CacheMaxSize)
+		}
+		if maxSize == "" && pruneOlderThan == 0 {
+			fmt.Fprintln(os.Stderr, "Nothing to prune: specify --older-than or --max-size, or set cache-max-size with minikube config set.")
+			os.Exit(1)
+		}
+		opts, err := imageCachePruneOptions(maxSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading prune options: %s\n", err)
+			os.Exit(1)
+		}
+		opts.OlderThan = pruneOlderThan
+		pruned, err := machine.PruneImageCache(constants.ImageCacheDir, opts)
+		var freed int64
+		for _, i := range pruned {
+			fmt.Printf("Removed %s (%s)\n", i.Name(), units.HumanSize(float64(i.Size)))
+			freed += i.Size
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning image cache: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Pruned %d images, freed %s.\n", len(pruned), units.HumanSize(float64(freed)))
+	},
+}
+
+// imageCachePruneOptions returns the options pruning the image cache to
+// maxSize, if not empty, while keeping the images still in use.
+func imageCachePruneOptions(maxSize string) (machine.PruneOptions, error) {
+	var opts machine.PruneOptions
+	if maxSize != "" {
+		size, err := units.FromHumanSize(maxSize)
+		if err != nil {
+			return opts, errors.Wrapf(err, "parsing size %s", maxSize)
+		}
+		opts.MaxSize = size
+	}
+	keep, err := cmdConfig.ListConfigMap(constants.Cache)
+	if err != nil {
+		return opts, errors.Wrap(err, "listing images from config")
+	}
+	opts.Keep = keep
+	cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+	if err != nil && !os.IsNotExist(err) {
+		return opts, errors.Wrap(err, "loading profile config")
+	}
+	if v := cc.KubernetesConfig.KubernetesVersion; v != "" {
+		opts.KeepVersions = []string{v}
+	}
+	return opts, nil
+}
+
+// maybePruneImageCache prunes the image cache to the cache-max-size setting,
+// if set, keeping the given images.
+func maybePruneImageCache(keep ...string) {
+	maxSize := viper.GetString(cmdConfig.CacheMaxSize)
+	if maxSize == "" {
+		return
+	}
+	opts, err := imageCachePruneOptions(maxSize)
+	if err != nil {
+		glog.Errorf("Error reading image cache limit: %s", err)
+		return
+	}
+	opts.Keep = append(opts.Keep, keep...)
+	pruned, err := machine.PruneImageCache(constants.ImageCacheDir, opts)
+	if err != nil {
+		glog.Errorf("Error pruning image cache: %s", err)
+	}
+	if len(pruned) > 0 {
+		var names []string
+		for _, i := range pruned {
+			names = append(names, i.Name())
+		}
+		glog.Infof("Pruned images over the %s cache limit: %s", maxSize, strings.Join(names, ", "))
+	}
+}
+
+func init() {
+	pruneCacheCmd.Flags().DurationVar(&pruneOlderThan, "older-than", 0, "Remove images not used for longer than this duration (e.g. 720h)")
+	pruneCacheCmd.Flags().StringVar(&pruneMaxSize, "max-size", "", "Remove the least recently used images until the cache is no larger than this size (e.g. 5g)")
+	cacheCmd.AddCommand(pruneCacheCmd)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// verifyCacheCmd represents the cache verify command
+var verifyCacheCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the checksums of the ISOs and binaries in the local cache.",
+	Long: `Verify the checksums of the ISOs and binaries in the local cache.
+Corrupted files are evicted from the cache, so that they are downloaded again when needed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := util.VerifyCacheDir(constants.MakeMiniPath("cache"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error verifying cache: %s\n", err)
+			os.Exit(1)
+		}
+		evicted := 0
+		for _, e := range entries {
+			if e.Err == nil {
+				continue
+			}
+			fmt.Printf("Evicting %s: %s\n", e.Path, e.Err)
+			if err := util.EvictCached(e.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error evicting %s: %s\n", e.Path, err)
+				os.Exit(1)
+			}
+			evicted++
+		}
+		fmt.Printf("Verified %d cached files, evicted %d.\n", len(entries), evicted)
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(verifyCacheCmd)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/util/kubeconfig"
+)
+
+var (
+	certsExpiryWarning time.Duration
+	certsRotateCA      bool
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Check and rotate the certificates of the local cluster",
+	Long:  "Check and rotate the certificates of the local cluster.",
+}
+
+// certsCheckCmd represents the certs check command
+var certsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Prints the subject, SANs and expiry of every certificate in the minikube directory and the VMs",
+	Long: `Prints the subject, SANs and expiry of every certificate in the minikube directory.
+With the kubeadm bootstrapper, the certificates kubeadm and the kubelet generated in the running VMs,
+and the client certificates of the kubeconfigs of the components, are checked too.
+Exits with status 1 if a certificate has expired, or expires within --expiry-warning.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		infos, err := bootstrapper.CheckCerts(constants.GetMinipath())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking certificates: %s\n", err)
+			os.Exit(1)
+		}
+		if viper.GetString(cmdcfg.Bootstrapper) == bootstrapper.BootstrapperTypeKubeadm {
+			vmInfos, err := checkVMCerts()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking the certificates of the VM: %s\n", err)
+				os.Exit(1)
+			}
+			infos = append(infos, vmInfos...)
+		}
+		expiring := printCertInfos(infos, time.Now())
+		if expiring > 0 {
+			fmt.Fprintf(os.Stderr, "%d certificate(s) expired or expire within %s, run: minikube certs rotate\n", expiring, certsExpiryWarning)
+			os.Exit(1)
+		}
+	},
+}
+
+// certsRotateCmd represents the certs rotate command
+var certsRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Reissues the certificates of the local cluster",
+	Long: `Reissues the apiserver, client and proxy-client certificates, or the CAs signing them too with --ca,
+copies them into the VM, restarts the control plane and updates kubeconfig.
+A CA given to minikube start with --ca-cert is imported again by --ca instead of being regenerated.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+		k8sBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
+		if err != nil {
+			glog.Exitf("Error getting cluster bootstrapper: %s", err)
+		}
+
+		if certsRotateCA {
+			fmt.Println("Rotating CAs and certificates...")
+		} else {
+			fmt.Println("Rotating certificates...")
+		}
+		if err := k8sBootstrapper.RotateCerts(cc.KubernetesConfig, certsRotateCA); err != nil {
+			glog.Errorln("Error rotating certificates: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+
+		fmt.Println("Updating kubeconfig...")
+		h, err := api.Load(cfg.GetMachineName())
+		if err != nil {
+			glog.Errorln("Error loading host: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		kubeHost, err := getKubeHost(h)
+		if err != nil {
+			glog.Errorln("Error getting apiserver URL: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		if err := kubeconfig.SetupKubeConfig(newKubeConfigSetup(kubeHost, true)); err != nil {
+			glog.Errorln("Error setting up kubeconfig: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+
+		fmt.Println("Certificates rotated, the control plane may take a minute to restart.")
+		if certsRotateCA {
+			fmt.Println("Pods using service account tokens have to be recreated to trust the new CA.")
+			if len(cc.Workers()) > 0 {
+				fmt.Println("Additional nodes have to be deleted and added again to join with the new CA.")
+			}
+		}
+	},
+}
+
+// checkVMCerts returns the certificates of the running machines of the
+// cluster, the control plane first. Stopped machines are skipped.
+func checkVMCerts() ([]*util.CertInfo, error) {
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting client")
+	}
+	defer api.Close()
+	names := []string{cfg.GetMachineName()}
+	if cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile)); err == nil {
+		for _, n := range cc.Workers() {
+			names = append(names, n.Name)
+		}
+	}
+
+	var infos []*util.CertInfo
+	for _, name := range names {
+		s, err := cluster.GetNamedHostStatus(api, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting status of %s", name)
+		}
+		if s != state.Running.String() {
+			fmt.Fprintf(os.Stderr, "Skipping the certificates of %s, it is not running\n", name)
+			continue
+		}
+		k, err := kubeadm.NewKubeadmBootstrapperForMachine(api, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting bootstrapper for %s", name)
+		}
+		vmInfos, err := k.CheckCerts(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking certificates of %s", name)
+		}
+		infos = append(infos, vmInfos...)
+	}
+	return infos, nil
+}
+
+// printCertInfos prints the certificates and returns how many of them expired
+// or expire within certsExpiryWarning of now
+func printCertInfos(infos []*util.CertInfo, now time.Time) int {
+	expiring := 0
+	for _, info := range infos {
+		fmt.Println(info.Path)
+		fmt.Printf("  Subject: %s\n", info.Subject)
+		fmt.Printf("  Issuer:  %s\n", info.Issuer)
+		if sans := info.SANs(); len(sans) > 0 {
+			fmt.Printf("  SANs:    %s\n", strings.Join(sans, ", "))
+		}
+		status := fmt.Sprintf("in %d days", int(info.NotAfter.Sub(now).Hours()/24))
+		if info.ExpiresWithin(certsExpiryWarning, now) {
+			expiring++
+			status += ", EXPIRING SOON"
+		}
+		if info.ExpiresWithin(0, now) {
+			status = "EXPIRED"
+		}
+		fmt.Printf("  Expires: %s (%s)\n", info.NotAfter.Format(time.RFC3339), status)
+	}
+	return expiring
+}
+
+func init() {
+	certsCheckCmd.Flags().DurationVar(&certsExpiryWarning, "expiry-warning", 30*24*time.Hour, "Warn about certificates expiring within this duration")
+	certsRotateCmd.Flags().BoolVar(&certsRotateCA, "ca", false, "Reissue the CAs as well as the certificates they sign")
+	certsCmd.AddCommand(certsCheckCmd)
+	certsCmd.AddCommand(certsRotateCmd)
+	RootCmd.AddCommand(certsCmd)
+}
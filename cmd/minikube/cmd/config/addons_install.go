@@ -0,0 +1,88 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+var addonInstallFrom string
+
+var addonsInstallCmd = &cobra.Command{
+	Use:   "install ADDON_NAME --from DIR|URL",
+	Short: "Installs an addon from a directory or URL of manifests (example: minikube addons install my-team --from ./addons/my-team)",
+	Long: `Installs an addon from a directory of manifests, a single manifest file, or the http(s) URL of a manifest or of a .tar.gz of manifests.
+Installed addons are enabled and disabled like the bundled ones, with minikube addons enable and disable.
+Installing an addon again replaces its manifests; enable it again to update the cluster.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 || addonInstallFrom == "" {
+			fmt.Fprintln(os.Stderr, "usage: minikube addons install ADDON_NAME --from DIR|URL")
+			os.Exit(1)
+		}
+		name := args[0]
+		addon, err := assets.InstallUserAddon(constants.UserAddonsDir, name, addonInstallFrom)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stdout, "%s was successfully installed from %s with %d manifests\n", name, addonInstallFrom, len(addon.Assets))
+		if enabled, _ := addon.IsEnabled(); enabled {
+			fmt.Fprintf(os.Stdout, "Run minikube addons enable %s to update it in the cluster\n", name)
+		} else {
+			fmt.Fprintf(os.Stdout, "Run minikube addons enable %s to add it to the cluster\n", name)
+		}
+	},
+}
+
+var addonsUninstallCmd = &cobra.Command{
+	Use:   "uninstall ADDON_NAME",
+	Short: "Uninstalls an addon installed with minikube addons install",
+	Long:  "Uninstalls an addon installed with minikube addons install. The addon has to be disabled first.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube addons uninstall ADDON_NAME")
+			os.Exit(1)
+		}
+		name := args[0]
+		if addon, ok := assets.Addons[name]; ok {
+			if enabled, _ := addon.IsEnabled(); enabled {
+				fmt.Fprintf(os.Stderr, "%s is enabled, disable it first with minikube addons disable %s\n", name, name)
+				os.Exit(1)
+			}
+		}
+		if err := assets.UninstallUserAddon(constants.UserAddonsDir, name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := unset(name); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stdout, "%s was successfully uninstalled\n", name)
+	},
+}
+
+func init() {
+	addonsInstallCmd.Flags().StringVar(&addonInstallFrom, "from", "", "The directory, manifest file or URL to install the addon from")
+	AddonsCmd.AddCommand(addonsInstallCmd)
+	AddonsCmd.AddCommand(addonsUninstallCmd)
+}
@@ -33,6 +33,21 @@ var addonListFormat string
 type AddonListTemplate struct {
 	AddonName   string
 	AddonStatus string
+	// AddonSource is where a user addon was installed from, empty for bundled addons
+	AddonSource string
+}
+
+// AddonList is the machine-readable output of addons list
+type AddonList struct {
+	TypeMeta
+	Addons []AddonStatus `json:"addons"`
+}
+
+// AddonStatus is the state of a single addon
+type AddonStatus struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Source  string `json:"source"`
 }
 
 var addonsListCmd = &cobra.Command{
@@ -73,6 +88,18 @@ func addonList() error {
 	}
 	sort.Strings(addonNames)
 
+	if output := GetOutputFormatOrExit(); output != OutputText {
+		list := AddonList{TypeMeta: NewTypeMeta("AddonList"), Addons: []AddonStatus{}}
+		for _, addonName := range addonNames {
+			addonStatus, err := assets.Addons[addonName].IsEnabled()
+			if err != nil {
+				return err
+			}
+			list.Addons = append(list.Addons, AddonStatus{Name: addonName, Enabled: addonStatus, Source: assets.Addons[addonName].Source()})
+		}
+		return PrintStructured(os.Stdout, output, list)
+	}
+
 	for _, addonName := range addonNames {
 		addonBundle := assets.Addons[addonName]
 		addonStatus, err := addonBundle.IsEnabled()
@@ -84,7 +111,10 @@ func addonList() error {
 			glog.Errorln("Error creating list template:", err)
 			os.Exit(1)
 		}
-		listTmplt := AddonListTemplate{addonName, stringFromStatus(addonStatus)}
+		listTmplt := AddonListTemplate{addonName, stringFromStatus(addonStatus), ""}
+		if addonBundle.IsUserAddon() {
+			listTmplt.AddonSource = addonBundle.Source()
+		}
 		err = tmpl.Execute(os.Stdout, listTmplt)
 		if err != nil {
 			glog.Errorln("Error executing list template:", err)
@@ -25,12 +25,22 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
 const Bootstrapper = "bootstrapper"
 
+// CacheMaxSize caps the size of the image cache, see minikube cache prune
+const CacheMaxSize = "cache-max-size"
+
+// IngressDomain is the domain resolved to the VM, see minikube dns
+const IngressDomain = "ingress-domain"
+
+// DNSListenAddress is the address of the DNS responder, see minikube dns
+const DNSListenAddress = "dns-listen-address"
+
 type setFn func(string, string) error
 
 type Setting struct {
@@ -108,6 +118,15 @@ var settings = []Setting{
 		name: config.WantReportErrorPrompt,
 		set:  SetBool,
 	},
+	{
+		name:        config.ReportErrorSink,
+		set:         SetString,
+		validations: []setFn{IsValidReportErrorSink},
+	},
+	{
+		name: config.ReportErrorHeaders,
+		set:  SetString,
+	},
 	{
 		name: config.WantKubectlDownloadMsg,
 		set:  SetBool,
@@ -121,8 +140,9 @@ var settings = []Setting{
 		set:  SetString,
 	},
 	{
-		name: Bootstrapper,
-		set:  SetString, //TODO(r2d4): more validation here?
+		name:        Bootstrapper,
+		set:         SetString,
+		validations: []setFn{IsValidBootstrapper},
 	},
 	{
 		name:        "dashboard",
@@ -215,6 +235,21 @@ var settings = []Setting{
 		set:    SetConfigMap,
 		setMap: SetMap,
 	},
+	{
+		name:        CacheMaxSize,
+		set:         SetString,
+		validations: []setFn{IsValidDiskSize},
+	},
+	{
+		name:        IngressDomain,
+		set:         SetString,
+		validations: []setFn{IsValidDomain},
+	},
+	{
+		name:        DNSListenAddress,
+		set:         SetString,
+		validations: []setFn{IsValidListenAddress},
+	},
 }
 
 var ConfigCmd = &cobra.Command{
@@ -227,6 +262,13 @@ Configurable fields: ` + "\n\n" + configurableFields(),
 	},
 }
 
+func init() {
+	// user addons can not shadow the settings, see findSetting
+	for _, s := range settings {
+		assets.ReservedAddonNames[s.name] = true
+	}
+}
+
 func configurableFields() string {
 	var fields []string
 	for _, s := range settings {
@@ -18,18 +18,30 @@ package config
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
+	"strconv"
+	"strings"
 
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/minikube/pkg/minikube/assets"
+	pkgConfig "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/service"
+)
 
-	"github.com/spf13/cobra"
+var (
+	addonConfigureValues     []string
+	addonConfigureValuesFile string
+	addonConfigurePrompt     bool
 )
 
 var addonsConfigureCmd = &cobra.Command{
 	Use:   "configure ADDON_NAME",
 	Short: "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list ",
-	Long:  "Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list ",
+	Long: `Configures the addon w/ADDON_NAME within minikube (example: minikube addons configure registry-creds). For a list of available addons use: minikube addons list
+
+Values are read from --set, then from the environment variables MINIKUBE_ADDON_<ADDON>_<KEY> (such as MINIKUBE_ADDON_REGISTRY_CREDS_AWS_REGION), then from --values-file. Values none of them set are prompted for when run in a terminal, and have their default otherwise.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			fmt.Fprintln(os.Stderr, "usage: minikube addons configure ADDON_NAME")
@@ -37,130 +49,162 @@ var addonsConfigureCmd = &cobra.Command{
 		}
 
 		addon := args[0]
-		// allows for additional prompting of information when enabling addons
-		switch addon {
-		case "registry-creds":
-			posResponses := []string{"yes", "y"}
-			negResponses := []string{"no", "n"}
-
-			// Default values
-			awsAccessID := "changeme"
-			awsAccessKey := "changeme"
-			awsSessionToken := ""
-			awsRegion := "changeme"
-			awsAccount := "changeme"
-			awsRole := "changeme"
-			gcrApplicationDefaultCredentials := "changeme"
-			dockerServer := "changeme"
-			dockerUser := "changeme"
-			dockerPass := "changeme"
-			gcrURL := "https://gcr.io"
-
-			enableAWSECR := AskForYesNoConfirmation("\nDo you want to enable AWS Elastic Container Registry?", posResponses, negResponses)
-			if enableAWSECR {
-				awsAccessID = AskForStaticValue("-- Enter AWS Access Key ID: ")
-				awsAccessKey = AskForStaticValue("-- Enter AWS Secret Access Key: ")
-				awsSessionToken = AskForStaticValueOptional("-- (Optional) Enter AWS Session Token: ")
-				awsRegion = AskForStaticValue("-- Enter AWS Region: ")
-				awsAccount = AskForStaticValue("-- Enter 12 digit AWS Account ID (Comma seperated list): ")
-				awsRole = AskForStaticValueOptional("-- (Optional) Enter ARN of AWS role to assume: ")
-			}
-
-			enableGCR := AskForYesNoConfirmation("\nDo you want to enable Google Container Registry?", posResponses, negResponses)
-			if enableGCR {
-				gcrPath := AskForStaticValue("-- Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json):")
-				gcrchangeURL := AskForYesNoConfirmation("-- Do you want to change the GCR URL (Default https://gcr.io)?", posResponses, negResponses)
-
-				if gcrchangeURL {
-					gcrURL = AskForStaticValue("-- Enter GCR URL (e.g. https://asia.gcr.io):")
-				}
-
-				// Read file from disk
-				dat, err := ioutil.ReadFile(gcrPath)
+		if err := configureAddon(addon); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
 
-				if err != nil {
-					fmt.Println("Could not read file for application_default_credentials.json")
-				} else {
-					gcrApplicationDefaultCredentials = string(dat)
-				}
-			}
+func configureAddon(name string) error {
+	addon, ok := assets.Addons[name]
+	if !ok {
+		return fmt.Errorf("%s is not a valid addon", name)
+	}
+	schema := addon.Config()
+	if schema == nil {
+		fmt.Fprintf(os.Stdout, "%s has no available configuration options\n", name)
+		return nil
+	}
+	set, file, err := addonConfigureInput()
+	if err != nil {
+		return err
+	}
+	for _, values := range []map[string]string{set, file} {
+		if err := schema.ValidateValues(values); err != nil {
+			return errors.Wrapf(err, "configuring %s", name)
+		}
+	}
+	sources := []assets.ValueSource{
+		assets.MapSource(set),
+		assets.EnvSource(name),
+		assets.MapSource(file),
+	}
+
+	rendered, err := schema.ResolveRendered(sources)
+	if err != nil {
+		return errors.Wrapf(err, "configuring %s", name)
+	}
+	if len(rendered) > 0 {
+		if err := setAddonValues(name, rendered); err != nil {
+			return err
+		}
+	}
+	// only updating the rendered values leaves the secrets as they are
+	if schema.HasSecrets() && (len(rendered) == 0 || hasSecretFields(schema, set, file)) {
+		if err := configureAddonSecrets(name, schema, sources); err != nil {
+			return err
+		}
+	} else if len(rendered) == 0 {
+		printAddonFields(name, schema)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stdout, "%s was successfully configured\n", name)
+	if enabled, err := addon.IsEnabled(); err == nil && enabled && len(rendered) > 0 {
+		fmt.Fprintf(os.Stdout, "Run \"minikube addons enable %s\" to apply the new values\n", name)
+	}
+	return nil
+}
 
-			enableDR := AskForYesNoConfirmation("\nDo you want to enable Docker Registry?", posResponses, negResponses)
-			if enableDR {
-				dockerServer = AskForStaticValue("-- Enter docker registry server url: ")
-				dockerUser = AskForStaticValue("-- Enter docker registry username: ")
-				dockerPass = AskForPasswordValue("-- Enter docker registry password: ")
+// hasSecretFields returns whether any of values sets a field used for the
+// secrets of the addon
+func hasSecretFields(schema *assets.AddonConfig, values ...map[string]string) bool {
+	for _, m := range values {
+		for k := range m {
+			if f, ok := schema.Field(k); ok && !f.Rendered {
+				return true
 			}
+		}
+	}
+	return false
+}
 
-			// Create ECR Secret
-			err := service.CreateSecret(
-				"kube-system",
-				"registry-creds-ecr",
-				map[string]string{
-					"AWS_ACCESS_KEY_ID":     awsAccessID,
-					"AWS_SECRET_ACCESS_KEY": awsAccessKey,
-					"AWS_SESSION_TOKEN":     awsSessionToken,
-					"aws-account":           awsAccount,
-					"aws-region":            awsRegion,
-					"aws-assume-role":       awsRole,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "ecr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-ecr` secret")
-			}
+// addonConfigureInput returns the values of --set and of --values-file
+func addonConfigureInput() (map[string]string, map[string]string, error) {
+	file := map[string]string{}
+	if addonConfigureValuesFile != "" {
+		f, err := os.Open(addonConfigureValuesFile)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "opening values file")
+		}
+		defer f.Close()
+		if file, err = assets.ReadValuesFile(f); err != nil {
+			return nil, nil, errors.Wrapf(err, "reading %s", addonConfigureValuesFile)
+		}
+	}
+	set, err := parseKeyValues(addonConfigureValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set, file, nil
+}
 
-			// Create GCR Secret
-			err = service.CreateSecret(
-				"kube-system",
-				"registry-creds-gcr",
-				map[string]string{
-					"application_default_credentials.json": gcrApplicationDefaultCredentials,
-					"gcrurl": gcrURL,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "gcr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-gcr` secret")
-			}
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid value %q, expected key=value", pair)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
 
-			// Create Docker Secret
-			err = service.CreateSecret(
-				"kube-system",
-				"registry-creds-dpr",
-				map[string]string{
-					"DOCKER_PRIVATE_REGISTRY_SERVER":   dockerServer,
-					"DOCKER_PRIVATE_REGISTRY_USER":     dockerUser,
-					"DOCKER_PRIVATE_REGISTRY_PASSWORD": dockerPass,
-				},
-				map[string]string{
-					"app":   "registry-creds",
-					"cloud": "dpr",
-					"kubernetes.io/minikube-addons": "registry-creds",
-				})
-
-			if err != nil {
-				fmt.Println("ERROR creating `registry-creds-dpr` secret")
-			}
+// setAddonValues stores the values the manifests of the addon are rendered
+// with when it is enabled
+func setAddonValues(name string, values map[string]string) error {
+	m, err := pkgConfig.ReadConfig()
+	if err != nil {
+		return errors.Wrap(err, "reading config")
+	}
+	assets.SetAddonValues(m, name, values)
+	return errors.Wrap(WriteConfig(m), "writing config")
+}
 
-			break
-		default:
-			fmt.Fprintln(os.Stdout, fmt.Sprintf("%s has no available configuration options", addon))
-			return
+// configureAddonSecrets resolves the configuration fields of the addon and
+// creates its secrets with them
+func configureAddonSecrets(name string, schema *assets.AddonConfig, sources []assets.ValueSource) error {
+	var prompt func(assets.ConfigField) (string, error)
+	if addonConfigurePrompt && terminal.IsTerminal(int(os.Stdin.Fd())) {
+		prompt = promptForField
+	}
+	values, err := schema.Resolve(sources, prompt)
+	if err != nil {
+		return errors.Wrapf(err, "configuring %s", name)
+	}
+	for _, secret := range schema.SecretsFor(values) {
+		if err := service.CreateSecret(secret.Namespace, secret.Name, secret.Data, secret.Labels); err != nil {
+			return errors.Wrapf(err, "creating %s secret", secret.Name)
 		}
+	}
+	return nil
+}
 
-		fmt.Fprintln(os.Stdout, fmt.Sprintf("%s was successfully configured", addon))
-	},
+func promptForField(f assets.ConfigField) (string, error) {
+	switch {
+	case f.Type == assets.FieldBool:
+		return strconv.FormatBool(AskForYesNoConfirmation("\n"+f.Description, []string{"yes", "y"}, []string{"no", "n"})), nil
+	case f.Optional:
+		return AskForStaticValueOptional("-- " + f.Description + ": "), nil
+	case f.Secret && f.Type != assets.FieldFile:
+		return AskForPasswordValue("-- " + f.Description + ": "), nil
+	default:
+		return AskForStaticValue("-- " + f.Description + ": "), nil
+	}
+}
+
+func printAddonFields(name string, schema *assets.AddonConfig) {
+	fmt.Fprintf(os.Stdout, "%s can be configured with --set key=value, or the environment variables listed, using the fields:\n", name)
+	for _, f := range schema.RenderedFields() {
+		fmt.Fprintf(os.Stdout, "  %s (%s): %s (default %q, %s)\n", f.Name, f.Type, f.Description, f.Default, f.EnvName(name))
+	}
 }
 
 func init() {
+	addonsConfigureCmd.Flags().StringArrayVar(&addonConfigureValues, "set", nil, "Set a value of the addon, as key=value. Values the addon manifests are rendered with are stored, and an empty value restores their default.")
+	addonsConfigureCmd.Flags().StringVar(&addonConfigureValuesFile, "values-file", "", "File of key=value lines to configure the addon with")
+	addonsConfigureCmd.Flags().BoolVar(&addonConfigurePrompt, "prompt", true, "Prompt for the values that are not set, when run in a terminal")
 	AddonsCmd.AddCommand(addonsConfigureCmd)
 }
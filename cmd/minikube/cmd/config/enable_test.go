@@ -16,10 +16,40 @@ limitations under the License.
 
 package config
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
 
 func TestEnableUnknownAddon(t *testing.T) {
 	if err := Set("InvalidAddon", "false"); err == nil {
 		t.Fatalf("Enable did not return error for unknown addon")
 	}
 }
+
+func TestInstallAddonWithSettingName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addons")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	os.MkdirAll(src, 0755)
+	if err := ioutil.WriteFile(filepath.Join(src, "pod.yaml"), []byte("kind: Pod\n"), 0644); err != nil {
+		t.Fatalf("Error writing manifest: %s", err)
+	}
+
+	if _, err := assets.InstallUserAddon(dir, "team", src); err != nil {
+		t.Fatalf("Error installing addon: %s", err)
+	}
+	delete(assets.Addons, "team")
+	for _, name := range []string{"memory", "cpus", "vm-driver"} {
+		if _, err := assets.InstallUserAddon(dir, name, src); err == nil {
+			t.Errorf("Expected an error installing an addon named after the %s setting", name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+// Output is the name of the global flag selecting the output format
+const Output = "output"
+
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+	OutputYAML = "yaml"
+)
+
+// OutputAPIVersion identifies the schema of machine-readable output.
+// Fields may be added within a version, but are never removed or changed.
+const OutputAPIVersion = "minikube.k8s.io/v1"
+
+// TypeMeta prefixes every machine-readable document so consumers can tell
+// which schema they are reading.
+type TypeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// NewTypeMeta returns the TypeMeta of a document of the given kind.
+func NewTypeMeta(kind string) TypeMeta {
+	return TypeMeta{APIVersion: OutputAPIVersion, Kind: kind}
+}
+
+// GetOutputFormat returns the format requested with --output.
+func GetOutputFormat() (string, error) {
+	switch o := viper.GetString(Output); o {
+	case "", OutputText:
+		return OutputText, nil
+	case OutputJSON, OutputYAML:
+		return o, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, must be one of: %s, %s, %s", o, OutputText, OutputJSON, OutputYAML)
+	}
+}
+
+// GetOutputFormatOrExit returns the format requested with --output, exiting
+// if it is not supported.
+func GetOutputFormatOrExit() string {
+	o, err := GetOutputFormat()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return o
+}
+
+// PrintStructured writes v to w as a JSON or YAML document.
+func PrintStructured(w io.Writer, format string, v interface{}) error {
+	var out []byte
+	var err error
+	switch format {
+	case OutputJSON:
+		out, err = json.MarshalIndent(v, "", "    ")
+		out = append(out, '\n')
+	case OutputYAML:
+		out, err = yaml.Marshal(v)
+	default:
+		return fmt.Errorf("%s is not a structured output format", format)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "encoding %s output", format)
+	}
+	_, err = w.Write(out)
+	return err
+}
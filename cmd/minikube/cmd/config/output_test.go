@@ -0,0 +1,102 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestGetOutputFormat(t *testing.T) {
+	defer viper.Reset()
+	var tests = []struct {
+		value     string
+		expected  string
+		shouldErr bool
+	}{
+		{value: "", expected: OutputText},
+		{value: "text", expected: OutputText},
+		{value: "json", expected: OutputJSON},
+		{value: "yaml", expected: OutputYAML},
+		{value: "xml", shouldErr: true},
+	}
+	for _, test := range tests {
+		viper.Set(Output, test.value)
+		o, err := GetOutputFormat()
+		if err != nil && !test.shouldErr {
+			t.Errorf("Unexpected error for %q: %s", test.value, err)
+		}
+		if err == nil && test.shouldErr {
+			t.Errorf("Expected error for %q but got none", test.value)
+		}
+		if o != test.expected {
+			t.Errorf("Output format for %q: expected %q, got %q", test.value, test.expected, o)
+		}
+	}
+}
+
+func TestPrintStructured(t *testing.T) {
+	list := AddonList{
+		TypeMeta: NewTypeMeta("AddonList"),
+		Addons:   []AddonStatus{{Name: "dashboard", Enabled: true, Source: "minikube"}},
+	}
+	var tests = []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: OutputJSON,
+			expected: `{
+    "apiVersion": "minikube.k8s.io/v1",
+    "kind": "AddonList",
+    "addons": [
+        {
+            "name": "dashboard",
+            "enabled": true,
+            "source": "minikube"
+        }
+    ]
+}
+`,
+		},
+		{
+			format: OutputYAML,
+			expected: `addons:
+- enabled: true
+  name: dashboard
+  source: minikube
+apiVersion: minikube.k8s.io/v1
+kind: AddonList
+`,
+		},
+	}
+	for _, test := range tests {
+		var b bytes.Buffer
+		if err := PrintStructured(&b, test.format, list); err != nil {
+			t.Fatalf("Error printing %s: %s", test.format, err)
+		}
+		if b.String() != test.expected {
+			t.Errorf("Unexpected %s output.\nExpected:\n%s\nGot:\n%s", test.format, test.expected, b.String())
+		}
+	}
+
+	if err := PrintStructured(&bytes.Buffer{}, OutputText, list); err == nil {
+		t.Errorf("Expected an error printing text as a structured format")
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ClusterSpecKind is the kind of the documents read by minikube start --config
+const ClusterSpecKind = "Cluster"
+
+// ClusterSpec is a declarative description of a cluster. Its fields map onto
+// the flags of minikube start, which take precedence when both are given.
+type ClusterSpec struct {
+	TypeMeta
+	VMDriver         string   `json:"vmDriver,omitempty"`
+	ISOURL           string   `json:"isoURL,omitempty"`
+	Memory           int      `json:"memory,omitempty"`
+	CPUs             int      `json:"cpus,omitempty"`
+	DiskSize         string   `json:"diskSize,omitempty"`
+	HostOnlyCIDR     string   `json:"hostOnlyCIDR,omitempty"`
+	DockerEnv        []string `json:"dockerEnv,omitempty"`
+	InsecureRegistry []string `json:"insecureRegistry,omitempty"`
+	RegistryMirror   []string `json:"registryMirror,omitempty"`
+
+	Bootstrapper      string          `json:"bootstrapper,omitempty"`
+	KubernetesVersion string          `json:"kubernetesVersion,omitempty"`
+	ContainerRuntime  string          `json:"containerRuntime,omitempty"`
+	NetworkPlugin     string          `json:"networkPlugin,omitempty"`
+	FeatureGates      map[string]bool `json:"featureGates,omitempty"`
+	// ExtraConfig entries use the format of --extra-config: component.key=value
+	ExtraConfig []string `json:"extraConfig,omitempty"`
+
+	Addons      []string `json:"addons,omitempty"`
+	CacheImages []string `json:"cacheImages,omitempty"`
+	// Mounts use the format of minikube mount: HOST_MOUNT_DIRECTORY:VM_MOUNT_DIRECTORY
+	Mounts []string `json:"mounts,omitempty"`
+}
+
+// LoadClusterSpec reads and validates a cluster spec file.
+func LoadClusterSpec(path string) (*ClusterSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading cluster spec")
+	}
+	return ParseClusterSpec(data)
+}
+
+// ParseClusterSpec decodes and validates a YAML or JSON cluster spec.
+func ParseClusterSpec(data []byte) (*ClusterSpec, error) {
+	j, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing cluster spec")
+	}
+	var spec ClusterSpec
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&spec); err != nil {
+		return nil, errors.Wrap(err, "decoding cluster spec")
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks the spec with the validations used by minikube config set.
+func (s *ClusterSpec) Validate() error {
+	if s.APIVersion != OutputAPIVersion {
+		return fmt.Errorf("unsupported apiVersion %q, expected %q", s.APIVersion, OutputAPIVersion)
+	}
+	if s.Kind != ClusterSpecKind {
+		return fmt.Errorf("unsupported kind %q, expected %q", s.Kind, ClusterSpecKind)
+	}
+
+	var errs []error
+	check := func(name, value string) {
+		st, err := findSetting(name)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		if err := run(name, value, st.validations); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if s.VMDriver != "" {
+		check("vm-driver", s.VMDriver)
+	}
+	if s.ISOURL != "" {
+		check("iso-url", s.ISOURL)
+	}
+	if s.Memory != 0 {
+		check("memory", strconv.Itoa(s.Memory))
+	}
+	if s.CPUs != 0 {
+		check("cpus", strconv.Itoa(s.CPUs))
+	}
+	if s.DiskSize != "" {
+		check("disk-size", s.DiskSize)
+	}
+	if s.HostOnlyCIDR != "" {
+		check("host-only-cidr", s.HostOnlyCIDR)
+	}
+	if s.Bootstrapper != "" {
+		check(Bootstrapper, s.Bootstrapper)
+	}
+	if _, err := cruntime.New(s.ContainerRuntime); err != nil {
+		errs = append(errs, err)
+	}
+	for _, addon := range s.Addons {
+		if err := IsValidAddon(addon, "true"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	var extraOptions util.ExtraOptionSlice
+	for _, e := range s.ExtraConfig {
+		if err := extraOptions.Set(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, m := range s.Mounts {
+		if err := IsValidMountString("mounts", m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid cluster spec: %v", errs)
+	}
+	return nil
+}
+
+// FeatureGatesString returns the feature gates in the key=value format of
+// the --feature-gates flag.
+func (s *ClusterSpec) FeatureGatesString() string {
+	var gates []string
+	for k, v := range s.FeatureGates {
+		gates = append(gates, fmt.Sprintf("%s=%t", k, v))
+	}
+	sort.Strings(gates)
+	return strings.Join(gates, ",")
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+)
+
+func TestParseClusterSpec(t *testing.T) {
+	spec, err := ParseClusterSpec([]byte(`
+apiVersion: minikube.k8s.io/v1
+kind: Cluster
+vmDriver: kvm
+memory: 4096
+cpus: 4
+diskSize: 40g
+bootstrapper: kubeadm
+kubernetesVersion: v1.9.4
+featureGates:
+  PodPriority: true
+  CustomResourceValidation: false
+extraConfig:
+- kubelet.max-pods=100
+addons:
+- ingress
+cacheImages:
+- redis:4
+mounts:
+- /home:/hosthome
+`))
+	if err != nil {
+		t.Fatalf("Error parsing cluster spec: %s", err)
+	}
+	if spec.VMDriver != "kvm" || spec.Memory != 4096 || spec.CPUs != 4 {
+		t.Errorf("Unexpected machine settings: %+v", spec)
+	}
+	if gates := spec.FeatureGatesString(); gates != "CustomResourceValidation=false,PodPriority=true" {
+		t.Errorf("Unexpected feature gates: %s", gates)
+	}
+}
+
+func TestParseClusterSpecInvalid(t *testing.T) {
+	var tests = []struct {
+		description string
+		spec        string
+	}{
+		{
+			description: "wrong apiVersion",
+			spec:        "apiVersion: v2\nkind: Cluster\n",
+		},
+		{
+			description: "wrong kind",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Pod\n",
+		},
+		{
+			description: "unknown field",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nmemmory: 2048\n",
+		},
+		{
+			description: "invalid driver",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nvmDriver: vkasdhfasjdf\n",
+		},
+		{
+			description: "negative memory",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nmemory: -1\n",
+		},
+		{
+			description: "invalid disk size",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\ndiskSize: big\n",
+		},
+		{
+			description: "invalid bootstrapper",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nbootstrapper: kubespray\n",
+		},
+		{
+			description: "unknown addon",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\naddons: [notanaddon]\n",
+		},
+		{
+			description: "invalid extra config",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nextraConfig: [kubelet]\n",
+		},
+		{
+			description: "invalid mount",
+			spec:        "apiVersion: minikube.k8s.io/v1\nkind: Cluster\nmounts: [/home]\n",
+		},
+	}
+	for _, test := range tests {
+		if _, err := ParseClusterSpec([]byte(test.spec)); err == nil {
+			t.Errorf("%s: expected an error but got none", test.description)
+		}
+	}
+}
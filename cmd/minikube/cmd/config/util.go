@@ -51,6 +51,16 @@ func findSetting(name string) (Setting, error) {
 			return s, nil
 		}
 	}
+	// addons installed with minikube addons install are enabled like the
+	// bundled ones
+	if addon, ok := assets.Addons[name]; ok && addon.IsUserAddon() {
+		return Setting{
+			name:        name,
+			set:         SetBool,
+			validations: []setFn{IsValidAddon},
+			callbacks:   []setFn{EnableOrDisableAddon},
+		}, nil
+	}
 	return Setting{}, fmt.Errorf("Property name %s not found", name)
 }
 
@@ -123,10 +133,12 @@ func EnableOrDisableAddon(name string, val string) error {
 		return errors.Wrap(err, "getting command runner")
 	}
 	if enable {
-		for _, addon := range addon.Assets {
-			if err := cmd.Copy(addon); err != nil {
-				return errors.Wrapf(err, "error enabling addon %s: %s", addon.AssetName)
-			}
+		files, err := addon.RenderedAssets()
+		if err != nil {
+			return errors.Wrapf(err, "error rendering addon %s", name)
+		}
+		if err := cmd.CopyAll(files); err != nil {
+			return errors.Wrapf(err, "error enabling addon %s", name)
 		}
 	} else {
 		for _, addon := range addon.Assets {
@@ -22,10 +22,12 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 
 	units "github.com/docker/go-units"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -51,6 +53,40 @@ func IsValidDiskSize(name string, disksize string) error {
 	return nil
 }
 
+// IsValidDomain checks that domain is a DNS domain name, such as minikube.test
+func IsValidDomain(name string, domain string) error {
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" {
+		return fmt.Errorf("%s must not be empty", name)
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return fmt.Errorf("%s is not a valid domain name: %s", domain, name)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return fmt.Errorf("%s is not a valid domain name: %s", domain, name)
+			}
+		}
+	}
+	return nil
+}
+
+// IsValidListenAddress checks that addr is an IP:port address
+func IsValidListenAddress(name string, addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("%s must be IP:port: %v", name, err)
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%s is not a valid IP: %s", host, name)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("%s is not a valid port: %s", port, name)
+	}
+	return nil
+}
+
 func IsValidURL(name string, location string) error {
 	_, err := url.Parse(location)
 	if err != nil {
@@ -59,6 +95,19 @@ func IsValidURL(name string, location string) error {
 	return nil
 }
 
+// IsValidReportErrorSink checks that sink is an http(s) URL, dir:DIRECTORY
+// or exec:COMMAND
+func IsValidReportErrorSink(name string, sink string) error {
+	switch {
+	case strings.HasPrefix(sink, "dir:") && len(sink) > len("dir:"),
+		strings.HasPrefix(sink, "exec:") && strings.TrimSpace(sink[len("exec:"):]) != "":
+		return nil
+	case strings.HasPrefix(sink, "http://") || strings.HasPrefix(sink, "https://"):
+		return IsValidURL(name, sink)
+	}
+	return fmt.Errorf("%s must be an http(s) URL, dir:DIRECTORY or exec:COMMAND", name)
+}
+
 func IsPositive(name string, val string) error {
 	i, err := strconv.Atoi(val)
 	if err != nil {
@@ -92,3 +141,19 @@ func IsValidAddon(name string, val string) error {
 	}
 	return errors.Errorf("Cannot enable/disable invalid addon %s", name)
 }
+
+func IsValidBootstrapper(name string, b string) error {
+	switch b {
+	case bootstrapper.BootstrapperTypeKubeadm, bootstrapper.BootstrapperTypeLocalkube:
+		return nil
+	}
+	return fmt.Errorf("Bootstrapper %s is not supported", b)
+}
+
+func IsValidMountString(name string, mountString string) error {
+	idx := strings.LastIndex(mountString, ":")
+	if idx <= 0 || idx == len(mountString)-1 {
+		return fmt.Errorf("%s must be in the form HOST_MOUNT_DIRECTORY:VM_MOUNT_DIRECTORY", mountString)
+	}
+	return nil
+}
@@ -94,3 +94,66 @@ func TestValidCIDR(t *testing.T) {
 
 	runValidations(t, tests, "cidr", IsValidCIDR)
 }
+
+func TestValidBootstrapper(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "kubeadm",
+			shouldErr: false,
+		},
+		{
+			value:     "localkube",
+			shouldErr: false,
+		},
+		{
+			value:     "kubespray",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "bootstrapper", IsValidBootstrapper)
+}
+
+func TestValidMountString(t *testing.T) {
+	var tests = []validationTest{
+		{
+			value:     "/home:/hosthome",
+			shouldErr: false,
+		},
+		{
+			value:     "/home",
+			shouldErr: true,
+		},
+		{
+			value:     "/home:",
+			shouldErr: true,
+		},
+	}
+
+	runValidations(t, tests, "mounts", IsValidMountString)
+}
+
+func TestValidDomain(t *testing.T) {
+	var tests = []validationTest{
+		{value: "minikube.test", shouldErr: false},
+		{value: "dev.example.com.", shouldErr: false},
+		{value: "", shouldErr: true},
+		{value: "minikube..test", shouldErr: true},
+		{value: "-minikube.test", shouldErr: true},
+		{value: "mini_kube.test", shouldErr: true},
+	}
+
+	runValidations(t, tests, "ingress-domain", IsValidDomain)
+}
+
+func TestValidListenAddress(t *testing.T) {
+	var tests = []validationTest{
+		{value: "127.0.0.1:10053", shouldErr: false},
+		{value: "[::1]:53", shouldErr: false},
+		{value: "127.0.0.1", shouldErr: true},
+		{value: "localhost:53", shouldErr: true},
+		{value: "127.0.0.1:0", shouldErr: true},
+	}
+
+	runValidations(t, tests, "dns-listen-address", IsValidListenAddress)
+}
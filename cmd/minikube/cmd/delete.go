@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdUtil "k8s.io/minikube/cmd/util"
@@ -49,6 +50,16 @@ associated files.`,
 		}
 		defer api.Close()
 
+		cc, err := loadConfigFromFile(viper.GetString(pkg_config.MachineProfile))
+		if err != nil && !os.IsNotExist(err) {
+			glog.Errorln("Error loading profile config: ", err)
+		}
+		for _, n := range cc.Workers() {
+			if err := cluster.DeleteNamedHost(api, n.Name); err != nil {
+				fmt.Printf("Errors occurred deleting node %s: %s\n", n.Name, err)
+			}
+		}
+
 		if err = cluster.DeleteHost(api); err != nil {
 			fmt.Println("Errors occurred deleting machine: ", err)
 			os.Exit(1)
@@ -0,0 +1,153 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/diagnose"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/version"
+)
+
+// diagnoseLogLines is the number of log entries of each component collected
+const diagnoseLogLines = 2000
+
+var diagnoseOut string
+
+// diagnoseCmd represents the diagnose command
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Collects the state of the local cluster into an archive for bug reports",
+	Long: `Collects the state of the local cluster into a gzipped tarball that can be attached to bug reports:
+the minikube and cluster configuration, the machine configuration and state, the kubeadm configuration,
+the cluster status and component logs, the pods and events of all namespaces, and the disk and memory usage of the VM.
+Passwords, tokens and the credentials of URLs are redacted from the configuration files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		fmt.Println("Collecting diagnostics...")
+		c := diagnose.NewCollector()
+		collectDiagnostics(c, api)
+		if err := c.Write(diagnoseOut, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing diagnostic bundle: %s\n", err)
+			os.Exit(1)
+		}
+		for _, e := range c.Errors() {
+			fmt.Printf("Could not collect %s\n", e)
+		}
+		fmt.Printf("Wrote diagnostic bundle to %s\n", diagnoseOut)
+	},
+}
+
+func collectDiagnostics(c *diagnose.Collector, api libmachine.API) {
+	profile := viper.GetString(config.MachineProfile)
+	bootstrapperName := viper.GetString(cmdcfg.Bootstrapper)
+	c.Add("version.txt", []byte(fmt.Sprintf("minikube: %s\nos: %s/%s\nprofile: %s\nbootstrapper: %s\n",
+		version.GetVersion(), runtime.GOOS, runtime.GOARCH, profile, bootstrapperName)))
+	c.AddJSONFile("host/config.json", constants.ConfigFile)
+	c.AddJSONFile("profile/config.json", constants.GetProfileFile(profile))
+	c.AddJSONFile("machine/config.json", constants.MakeMiniPath("machines", config.GetMachineName(), "config.json"))
+
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		c.Fail("machine/state.txt", err)
+		return
+	}
+	s, err := h.Driver.GetState()
+	if err != nil {
+		c.Fail("machine/state.txt", err)
+		return
+	}
+	ip := ""
+	if s == state.Running {
+		if addr, err := h.Driver.GetIP(); err == nil {
+			ip = addr
+		}
+	}
+	c.Add("machine/state.txt", []byte(fmt.Sprintf("driver: %s\nstate: %s\nip: %s\n", h.DriverName, s, ip)))
+	if s != state.Running {
+		return
+	}
+
+	runner, err := machine.GetCommandRunner(h)
+	if err != nil {
+		c.Fail("vm", err)
+		return
+	}
+	c.AddCommand("vm/df.txt", runner, "df -h")
+	c.AddCommand("vm/free.txt", runner, "free -m")
+	if bootstrapperName == bootstrapper.BootstrapperTypeKubeadm {
+		c.AddCommand("cluster/kubeadm.yaml", runner, "sudo cat "+constants.KubeadmConfigFile)
+	}
+
+	b, err := GetClusterBootstrapper(api, bootstrapperName)
+	if err != nil {
+		c.Fail("cluster", err)
+		return
+	}
+	c.AddFunc("cluster/status.json", func() ([]byte, error) {
+		cs, err := b.GetClusterStatus()
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(cs, "", "    ")
+	})
+	c.AddFunc("cluster/logs.txt", func() ([]byte, error) {
+		r, err := GetContainerRuntime()
+		if err != nil {
+			return nil, err
+		}
+		var logs bytes.Buffer
+		err = b.GetClusterLogsTo(bootstrapper.LogOptions{Tail: diagnoseLogLines, ContainerRuntime: r.Name()}, &logs)
+		return logs.Bytes(), err
+	})
+
+	client, err := service.K8s.GetCoreClient()
+	if err != nil {
+		c.Fail("kubernetes", err)
+		return
+	}
+	now := time.Now()
+	c.AddFunc("kubernetes/pods.txt", func() ([]byte, error) { return diagnose.Pods(client, now) })
+	c.AddFunc("kubernetes/events.txt", func() ([]byte, error) { return diagnose.Events(client, now) })
+}
+
+func init() {
+	diagnoseCmd.Flags().StringVar(&diagnoseOut, "out", "minikube-diagnose.tar.gz", "The file to write the diagnostic bundle to")
+	RootCmd.AddCommand(diagnoseCmd)
+}
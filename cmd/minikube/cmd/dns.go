@@ -0,0 +1,187 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/dns"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+// dnsIPCacheTTL is how long the VM IP is cached by the DNS responder
+const dnsIPCacheTTL = 30 * time.Second
+
+// dnsCmd represents the dns command
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolves the hostnames of Ingresses and services from the host",
+	Long: `Resolves the hostnames of Ingresses and services from the host.
+
+minikube dns serve runs a DNS responder which resolves every name in the ingress
+domain (minikube.test by default) to the IP of the VM, and forwards the queries
+for the service domain of the cluster (svc.cluster.local by default) to the
+cluster DNS. minikube dns install configures the resolver of the host to use it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var dnsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs the DNS responder until interrupted",
+	Long:  `Runs the DNS responder until interrupted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		dnsIP, err := pkgutil.GetDNSIP(pkgutil.DefaultServiceCIDR)
+		if err != nil {
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		// the machine is loaded on every dial, so that the client of its
+		// new IP is used after a restart
+		dial := func(network, addr string) (net.Conn, error) {
+			h, err := cluster.CheckIfApiExistsAndLoad(api)
+			if err != nil {
+				return nil, err
+			}
+			client, err := sshutil.GetClient(h.Driver)
+			if err != nil {
+				return nil, err
+			}
+			return client.Dial(network, addr)
+		}
+		server := &dns.Server{
+			Domain:        ingressDomain(),
+			HostIP:        dns.CachedIP(func() (net.IP, error) { return cluster.GetHostDriverIP(api) }, dnsIPCacheTTL),
+			ClusterDomain: clusterServiceDomain(),
+			ClusterDNS:    net.JoinHostPort(dnsIP.String(), "53"),
+			Dial:          dial,
+			TTL:           uint32(dnsIPCacheTTL.Seconds()),
+		}
+
+		done := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(done)
+		}()
+
+		addr := dnsListenAddress()
+		fmt.Printf("Resolving *.%s to %s and forwarding %s to the cluster DNS on %s, press Ctrl-C to stop.\n",
+			server.Domain, cfg.GetMachineName(), server.ClusterDomain, addr)
+		if err := server.ListenAndServe(addr, done); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running the DNS responder: %s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var dnsInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Configures the resolver of the host to use the DNS responder",
+	Long: `Configures the resolver of the host to send the queries for the ingress and service
+domains to the DNS responder of minikube dns serve. Supported on Linux with
+systemd-resolved, or NetworkManager running dnsmasq. Needs sudo.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		resolver, err := dns.DetectResolver()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error detecting the resolver of the host: %s\n", err)
+			os.Exit(1)
+		}
+		domains := []string{ingressDomain(), clusterServiceDomain()}
+		if err := dns.InstallResolverConfig(resolver, cfg.GetMachineName(), dnsListenAddress(), domains); err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring %s: %s\n", resolver, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Configured %s to resolve %s through %s.\n", resolver, strings.Join(domains, " and "), dnsListenAddress())
+		fmt.Println("Run minikube dns serve to start the DNS responder.")
+	},
+}
+
+var dnsUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Removes the resolver configuration written by minikube dns install",
+	Long:  `Removes the resolver configuration written by minikube dns install.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		resolver, err := dns.DetectResolver()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error detecting the resolver of the host: %s\n", err)
+			os.Exit(1)
+		}
+		if err := dns.UninstallResolverConfig(resolver, cfg.GetMachineName()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring %s: %s\n", resolver, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Removed the minikube configuration of %s.\n", resolver)
+	},
+}
+
+// ingressDomain returns the domain resolved to the VM
+func ingressDomain() string {
+	if d := viper.GetString(cmdConfig.IngressDomain); d != "" {
+		return strings.TrimSuffix(d, ".")
+	}
+	return constants.DefaultIngressDomain
+}
+
+// dnsListenAddress returns the address of the DNS responder
+func dnsListenAddress() string {
+	if a := viper.GetString(cmdConfig.DNSListenAddress); a != "" {
+		return a
+	}
+	return constants.DefaultDNSListenAddress
+}
+
+// clusterServiceDomain returns the domain of the services of the cluster,
+// svc. followed by its DNSDomain
+func clusterServiceDomain() string {
+	domain := constants.ClusterDNSDomain
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err == nil && cc.KubernetesConfig.DNSDomain != "" {
+		domain = cc.KubernetesConfig.DNSDomain
+	}
+	return "svc." + domain
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsServeCmd)
+	dnsCmd.AddCommand(dnsInstallCmd)
+	dnsCmd.AddCommand(dnsUninstallCmd)
+	RootCmd.AddCommand(dnsCmd)
+}
@@ -315,6 +315,15 @@ var dockerEnvCmd = &cobra.Command{
 			fmt.Println(`'none' driver does not support 'minikube docker-env' command`)
 			os.Exit(0)
 		}
+		r, err := GetContainerRuntime()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting container runtime: %s\n", err)
+			os.Exit(1)
+		}
+		if r.Name() != "docker" && !unset {
+			fmt.Fprintf(os.Stderr, "The %s container runtime does not use the docker daemon, images built with 'minikube docker-env' would not be available to Kubernetes\n", r.Name())
+			os.Exit(1)
+		}
 
 		var shellCfg *ShellConfig
 
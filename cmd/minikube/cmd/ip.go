@@ -22,10 +22,17 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
+// IP is the machine-readable output of ip
+type IP struct {
+	cmdConfig.TypeMeta
+	IP string `json:"ip"`
+}
+
 // ipCmd represents the ip command
 var ipCmd = &cobra.Command{
 	Use:   "ip",
@@ -38,6 +45,7 @@ var ipCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
+		output := cmdConfig.GetOutputFormatOrExit()
 		host, err := api.Load(config.GetMachineName())
 		if err != nil {
 			glog.Errorln("Error getting IP: ", err)
@@ -48,6 +56,13 @@ var ipCmd = &cobra.Command{
 			glog.Errorln("Error getting IP: ", err)
 			os.Exit(1)
 		}
+		if output != cmdConfig.OutputText {
+			if err := cmdConfig.PrintStructured(os.Stdout, output, IP{cmdConfig.NewTypeMeta("IP"), ip}); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
 		fmt.Println(ip)
 	},
 }
@@ -20,25 +20,55 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
 var (
-	follow bool
+	follow        bool
+	logComponents []string
+	logSince      time.Duration
+	logTail       int
+	logGrep       string
 )
 
 // logsCmd represents the logs command
 var logsCmd = &cobra.Command{
 	Use:   "logs",
-	Short: "Gets the logs of the running localkube instance, used for debugging minikube, not user code",
-	Long:  `Gets the logs of the running localkube instance, used for debugging minikube, not user code.`,
+	Short: "Gets the logs of the running cluster components, used for debugging minikube, not user code",
+	Long: `Gets the logs of the running cluster components, used for debugging minikube, not user code.
+The logs of all the components are interleaved by timestamp, and prefixed with the component name.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		opts := bootstrapper.LogOptions{
+			Components: logComponents,
+			Follow:     follow,
+			Since:      logSince,
+			Tail:       logTail,
+		}
+		if logGrep != "" {
+			re, err := regexp.Compile(logGrep)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Invalid --grep pattern: %s\n", err)
+				os.Exit(1)
+			}
+			opts.Grep = re
+		}
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+		opts.ContainerRuntime = cc.KubernetesConfig.ContainerRuntime
+
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
@@ -50,7 +80,7 @@ var logsCmd = &cobra.Command{
 			glog.Exitf("Error getting cluster bootstrapper: %s", err)
 		}
 
-		err = clusterBootstrapper.GetClusterLogsTo(follow, os.Stdout)
+		err = clusterBootstrapper.GetClusterLogsTo(opts, os.Stdout)
 		if err != nil {
 			log.Println("Error getting machine logs:", err)
 			cmdUtil.MaybeReportErrorAndExit(err)
@@ -60,5 +90,9 @@ var logsCmd = &cobra.Command{
 
 func init() {
 	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
+	logsCmd.Flags().StringSliceVar(&logComponents, "component", []string{bootstrapper.LogComponentAll}, "The components to show the logs of: apiserver, etcd, scheduler, controller-manager, kubelet, docker or all. The localkube bootstrapper has localkube and docker.")
+	logsCmd.Flags().DurationVar(&logSince, "since", 0, "Show only the log entries newer than this duration (e.g. 10m)")
+	logsCmd.Flags().IntVar(&logTail, "tail", 0, "Show only this many of the most recent log entries matching --grep. With --follow, this many of the most recent entries of each component are filtered by --grep")
+	logsCmd.Flags().StringVar(&logGrep, "grep", "", "Show only the log entries matching this regular expression")
 	RootCmd.AddCommand(logsCmd)
 }
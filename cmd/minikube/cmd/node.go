@@ -0,0 +1,285 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+// nodeCmd represents the node command
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Add, remove or list additional nodes of the local cluster.",
+	Long: `Add, remove or list additional nodes of the local cluster.
+Additional nodes are separate VMs that join the control plane as workers. Only the kubeadm bootstrapper supports them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// nodeAddCmd represents the node add command
+var nodeAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Adds a worker node to the local cluster.",
+	Long:  "Creates a new VM with the same settings as the control plane and joins it to the cluster with kubeadm join.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "usage: minikube node add")
+			os.Exit(1)
+		}
+		api, cc := loadMultiNodeClusterOrExit()
+		defer api.Close()
+
+		name := nextNodeName(cc)
+		fmt.Printf("Starting node %s...\n", name)
+		h, err := startWorkerHost(api, name, cc.MachineConfig)
+		if err != nil {
+			glog.Errorln("Error starting node: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Joining node to the cluster...")
+		if err := addNode(api, h, &cc); err != nil {
+			glog.Errorln("Error joining node: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		if err := saveConfig(cc); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Node %s was successfully added.\n", name)
+	},
+}
+
+// nodeDeleteCmd represents the node delete command
+var nodeDeleteCmd = &cobra.Command{
+	Use:   "delete NODE_NAME",
+	Short: "Removes a worker node from the local cluster.",
+	Long:  "Removes a worker node from the cluster and deletes its VM. The control plane cannot be removed this way, use minikube delete instead.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube node delete NODE_NAME")
+			os.Exit(1)
+		}
+		name := args[0]
+		api, cc := loadMultiNodeClusterOrExit()
+		defer api.Close()
+
+		idx := -1
+		for i, n := range cc.Nodes {
+			if n.Name == name {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			fmt.Fprintf(os.Stderr, "Node %s is not part of the cluster\n", name)
+			os.Exit(1)
+		}
+		if cc.Nodes[idx].ControlPlane {
+			fmt.Fprintf(os.Stderr, "Node %s is the control plane and cannot be deleted, use minikube delete instead\n", name)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Removing node %s from the cluster...\n", name)
+		if err := kubeadm.RemoveNode(name); err != nil {
+			glog.Warningf("Error removing node %s from the cluster: %s", name, err)
+		}
+		if err := cluster.DeleteNamedHost(api, name); err != nil {
+			fmt.Println("Errors occurred deleting node machine: ", err)
+			os.Exit(1)
+		}
+
+		cc.Nodes = append(cc.Nodes[:idx], cc.Nodes[idx+1:]...)
+		if err := saveConfig(cc); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Node %s deleted.\n", name)
+	},
+}
+
+// loadMultiNodeClusterOrExit loads the profile of a running kubeadm cluster,
+// exiting if the cluster cannot have additional nodes.
+func loadMultiNodeClusterOrExit() (libmachine.API, cluster.Config) {
+	if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+		fmt.Fprintln(os.Stderr, "Additional nodes are only supported by the kubeadm bootstrapper")
+		os.Exit(1)
+	}
+	api, err := machine.NewAPIClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+		os.Exit(1)
+	}
+	cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+	cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+		os.Exit(1)
+	}
+	if cc.MachineConfig.VMDriver == constants.DriverNone {
+		fmt.Fprintln(os.Stderr, "The none driver does not support additional nodes")
+		os.Exit(1)
+	}
+	return api, cc
+}
+
+// nextNodeName returns the first free node name for the cluster.
+func nextNodeName(cc cluster.Config) string {
+	used := map[string]bool{}
+	for _, n := range cc.Nodes {
+		used[n.Name] = true
+	}
+	for i := 2; ; i++ {
+		name := cluster.GetNodeName(cfg.GetMachineName(), i)
+		if !used[name] {
+			return name
+		}
+	}
+}
+
+// startWorkerHost starts the VM backing a worker node, retrying like the
+// control plane start does.
+func startWorkerHost(api libmachine.API, name string, config cluster.MachineConfig) (*host.Host, error) {
+	// Each machine needs its own identity
+	config.UUID = ""
+	config.Downloader = pkgutil.DefaultDownloader{}
+
+	var h *host.Host
+	start := func() (err error) {
+		h, err = cluster.StartNamedHost(api, name, config)
+		if err != nil {
+			glog.Errorf("Error starting node %s: %s.\n\n Retrying.\n", name, err)
+		}
+		return err
+	}
+	if err := pkgutil.RetryAfter(5, start, 2*time.Second); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// addNode joins the started worker VM h to the cluster and records it in cc.
+// If it can not join, the VM is deleted, so that it is not left running
+// unknown to minikube stop and delete.
+func addNode(api libmachine.API, h *host.Host, cc *cluster.Config) error {
+	ip, err := h.Driver.GetIP()
+	if err == nil {
+		err = joinNode(api, h.Name, cc.KubernetesConfig)
+	}
+	if err != nil {
+		if delErr := cluster.DeleteNamedHost(api, h.Name); delErr != nil {
+			return errors.Wrapf(err, "adding node, and deleting its VM %s failed: %v", h.Name, delErr)
+		}
+		return errors.Wrap(err, "adding node")
+	}
+	cc.Nodes = append(cc.Nodes, cluster.Node{Name: h.Name, IP: ip})
+	return nil
+}
+
+// joinNode configures the machine name as a worker and joins it to the
+// cluster running on the control plane.
+var joinNode = func(api libmachine.API, name string, k8s bootstrapper.KubernetesConfig) error {
+	controlPlane, err := kubeadm.NewKubeadmBootstrapper(api)
+	if err != nil {
+		return errors.Wrap(err, "getting control plane bootstrapper")
+	}
+	worker, err := kubeadm.NewKubeadmBootstrapperForMachine(api, name)
+	if err != nil {
+		return errors.Wrapf(err, "getting bootstrapper for %s", name)
+	}
+
+	if err := worker.UpdateNode(k8s, name); err != nil {
+		return errors.Wrap(err, "updating node")
+	}
+	joinCmd, err := controlPlane.GetJoinCommand()
+	if err != nil {
+		return errors.Wrap(err, "getting join command")
+	}
+	return worker.JoinCluster(joinCmd)
+}
+
+// startWorkerNodes starts the VMs of all worker nodes of the cluster and
+// records their current IPs. If the control plane was just created, the
+// workers are reset and joined to it again.
+func startWorkerNodes(api libmachine.API, cc *cluster.Config, rejoin bool) error {
+	for i, n := range cc.Nodes {
+		if n.ControlPlane {
+			continue
+		}
+		h, err := startWorkerHost(api, n.Name, cc.MachineConfig)
+		if err != nil {
+			return errors.Wrapf(err, "starting node %s", n.Name)
+		}
+		ip, err := h.Driver.GetIP()
+		if err != nil {
+			return errors.Wrapf(err, "getting IP of node %s", n.Name)
+		}
+		cc.Nodes[i].IP = ip
+
+		if rejoin {
+			worker, err := kubeadm.NewKubeadmBootstrapperForMachine(api, n.Name)
+			if err != nil {
+				return errors.Wrapf(err, "getting bootstrapper for %s", n.Name)
+			}
+			if err := worker.ResetNode(); err != nil {
+				glog.Warningf("Error resetting node %s: %s", n.Name, err)
+			}
+			if err := joinNode(api, n.Name, cc.KubernetesConfig); err != nil {
+				return errors.Wrapf(err, "joining node %s", n.Name)
+			}
+		}
+	}
+	return saveConfig(*cc)
+}
+
+// withControlPlaneNode returns nodes with the control plane entry set to the
+// given name and IP, adding it first if it is not recorded yet.
+func withControlPlaneNode(nodes []cluster.Node, name, ip string) []cluster.Node {
+	controlPlane := cluster.Node{Name: name, IP: ip, ControlPlane: true}
+	for i, n := range nodes {
+		if n.ControlPlane {
+			nodes[i] = controlPlane
+			return nodes
+		}
+	}
+	return append([]cluster.Node{controlPlane}, nodes...)
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeAddCmd)
+	nodeCmd.AddCommand(nodeDeleteCmd)
+	RootCmd.AddCommand(nodeCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+)
+
+// nodeListCmd represents the node list command
+var nodeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the nodes of the local cluster.",
+	Long:  "Lists the nodes recorded in the profile of the local cluster along with the state of their VMs.",
+	Run: func(cmd *cobra.Command, args []string) {
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+
+		var data [][]string
+		for _, n := range cc.Nodes {
+			role := "worker"
+			if n.ControlPlane {
+				role = "control-plane"
+			}
+			s, err := cluster.GetNamedHostStatus(api, n.Name)
+			if err != nil {
+				s = "Unknown"
+			}
+			data = append(data, []string{n.Name, n.IP, role, s})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "IP", "Role", "Status"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.AppendBulk(data)
+		table.Render()
+	},
+}
+
+func init() {
+	nodeCmd.AddCommand(nodeListCmd)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestAddNode(t *testing.T) {
+	orig := joinNode
+	defer func() { joinNode = orig }()
+
+	var testCases = []struct {
+		description string
+		joinErr     error
+	}{
+		{description: "joined", joinErr: nil},
+		{description: "join fails", joinErr: errors.New("kubeadm join failed")},
+	}
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			joinNode = func(libmachine.API, string, bootstrapper.KubernetesConfig) error {
+				return test.joinErr
+			}
+			api := tests.NewMockAPI()
+			h := &host.Host{Name: "minikube-m02", Driver: &tests.MockDriver{}}
+			api.Hosts[h.Name] = h
+			cc := cluster.Config{Nodes: []cluster.Node{{Name: "minikube", ControlPlane: true}}}
+
+			err := addNode(api, h, &cc)
+			exists, _ := api.Exists(h.Name)
+			if test.joinErr != nil {
+				if err == nil {
+					t.Fatal("Expected an error adding the node")
+				}
+				if exists {
+					t.Error("Expected the VM of the node to be deleted")
+				}
+				if len(cc.Nodes) != 1 {
+					t.Errorf("Expected the node not to be recorded, got %v", cc.Nodes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error adding node: %s", err)
+			}
+			if !exists || len(cc.Nodes) != 2 || cc.Nodes[1].Name != h.Name {
+				t.Errorf("Expected the node to be kept and recorded, got %v", cc.Nodes)
+			}
+		})
+	}
+}
@@ -33,11 +33,13 @@ import (
 	"github.com/spf13/viper"
 	configCmd "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/localkube"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/notify"
 )
 
@@ -92,7 +94,7 @@ var RootCmd = &cobra.Command{
 			logDir.Value.Set(constants.MakeMiniPath("logs"))
 		}
 
-		if enableUpdateNotification {
+		if enableUpdateNotification && !viper.GetBool(offline) {
 			notify.MaybePrintUpdateTextFromGithub(os.Stderr)
 		}
 		util.MaybePrintKubectlDownloadMsg(runtime.GOOS, os.Stderr)
@@ -128,6 +130,7 @@ func init() {
 	RootCmd.PersistentFlags().StringP(config.MachineProfile, "p", constants.DefaultMachineName, `The name of the minikube VM being used.  
 	This can be modified to allow for multiple minikube instances to be run independently`)
 	RootCmd.PersistentFlags().StringP(configCmd.Bootstrapper, "b", constants.DefaultClusterBootstrapper, "The name of the cluster bootstrapper that will set up the kubernetes cluster.")
+	RootCmd.PersistentFlags().StringP(configCmd.Output, "o", configCmd.OutputText, "Output format of commands that support it. One of: text, json, yaml.")
 	RootCmd.AddCommand(configCmd.ConfigCmd)
 	RootCmd.AddCommand(configCmd.AddonsCmd)
 	RootCmd.AddCommand(configCmd.ProfileCmd)
@@ -147,6 +150,9 @@ func initConfig() {
 	if err != nil {
 		glog.Warningf("Error reading config file at %s: %s", configPath, err)
 	}
+	if err := assets.LoadUserAddons(constants.UserAddonsDir); err != nil {
+		glog.Warningf("Error loading user addons: %s", err)
+	}
 	setupViper()
 }
 
@@ -188,3 +194,12 @@ func GetClusterBootstrapper(api libmachine.API, bootstrapperName string) (bootst
 
 	return b, nil
 }
+
+// GetContainerRuntime returns the container runtime of the current profile
+func GetContainerRuntime() (cruntime.Manager, error) {
+	cc, err := loadConfigFromFile(viper.GetString(config.MachineProfile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "loading profile config")
+	}
+	return cruntime.New(cc.KubernetesConfig.ContainerRuntime)
+}
@@ -19,9 +19,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 	"text/template"
 
 	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -38,6 +41,8 @@ var (
 	serviceURLTemplate *template.Template
 	wait               int
 	interval           int
+	portForward        bool
+	localPorts         []int
 )
 
 // serviceCmd represents the service command
@@ -71,6 +76,22 @@ var serviceCmd = &cobra.Command{
 		defer api.Close()
 
 		cluster.EnsureMinikubeRunningOrExit(api, 1)
+		if portForward {
+			done := make(chan struct{})
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				close(done)
+			}()
+			err = service.WaitAndForwardService(api, namespace, svc, localPorts, cmdUtil.GetPort,
+				serviceURLTemplate, serviceURLMode, https, wait, interval, done)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error forwarding service: %s\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 		err = service.WaitAndMaybeOpenService(api, namespace, svc,
 			serviceURLTemplate, serviceURLMode, https, wait, interval)
 		if err != nil {
@@ -85,6 +106,8 @@ func init() {
 	serviceCmd.Flags().BoolVar(&serviceURLMode, "url", false, "Display the kubernetes service URL in the CLI instead of opening it in the default browser")
 	serviceCmd.Flags().BoolVar(&https, "https", false, "Open the service URL with https instead of http")
 	serviceCmd.Flags().IntVar(&wait, "wait", constants.DefaultWait, "Amount of time to wait for a service in seconds")
+	serviceCmd.Flags().BoolVar(&portForward, "port-forward", false, "Forward the ports of the service from local ports through SSH to the VM, instead of using its node ports. Works for ClusterIP services too.")
+	serviceCmd.Flags().IntSliceVar(&localPorts, "local-port", []int{}, "The local ports to forward the service ports from with --port-forward, in order. Free ports are picked for the rest.")
 	serviceCmd.Flags().IntVar(&interval, "interval", constants.DefaultWait, "The time interval for each check that wait performs in seconds")
 
 	serviceCmd.PersistentFlags().StringVar(&serviceURLFormat, "format", defaultServiceFormatTemplate, "Format to output service URL in. This format will be applied to each url individually and they will be printed one at a time.")
@@ -25,12 +25,26 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/api/core/v1"
 
+	cmdConfig "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/service"
 )
 
 var serviceListNamespace string
 
+// ServiceList is the machine-readable output of service list
+type ServiceList struct {
+	cmdConfig.TypeMeta
+	Services []ServiceListEntry `json:"services"`
+}
+
+// ServiceListEntry describes the node port URLs of a single service
+type ServiceListEntry struct {
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	URLs      []string `json:"urls"`
+}
+
 // serviceListCmd represents the service list command
 var serviceListCmd = &cobra.Command{
 	Use:   "list [flags]",
@@ -43,6 +57,7 @@ var serviceListCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		defer api.Close()
+		output := cmdConfig.GetOutputFormatOrExit()
 		serviceURLs, err := service.GetServiceURLs(api, serviceListNamespace, serviceURLTemplate)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
@@ -50,6 +65,22 @@ var serviceListCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if output != cmdConfig.OutputText {
+			list := ServiceList{TypeMeta: cmdConfig.NewTypeMeta("ServiceList"), Services: []ServiceListEntry{}}
+			for _, serviceURL := range serviceURLs {
+				urls := serviceURL.URLs
+				if urls == nil {
+					urls = []string{}
+				}
+				list.Services = append(list.Services, ServiceListEntry{serviceURL.Namespace, serviceURL.Name, urls})
+			}
+			if err := cmdConfig.PrintStructured(os.Stdout, output, list); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		var data [][]string
 		for _, serviceURL := range serviceURLs {
 			if len(serviceURL.URLs) == 0 {
@@ -0,0 +1,228 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/golang/glog"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore the state of the local cluster.",
+	Long: `Save and restore the state of the local cluster.
+A snapshot holds the etcd data, the cluster certificates and the profile configuration, and can be restored into a freshly started VM.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// snapshotSaveCmd represents the snapshot save command
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save NAME",
+	Short: "Saves the state of the local cluster as a snapshot.",
+	Long:  "Saves the state of the local cluster as a snapshot. The cluster components are briefly stopped while etcd is archived.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube snapshot save NAME")
+			os.Exit(1)
+		}
+		profile := viper.GetString(cfg.MachineProfile)
+		cc, err := loadConfigFromFile(profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		runner := snapshotRunnerOrExit(api)
+		r, err := cruntime.New(cc.KubernetesConfig.ContainerRuntime)
+		if err != nil {
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		fmt.Printf("Saving snapshot %s...\n", args[0])
+		s := snapshot.Snapshot{
+			Name:              args[0],
+			Profile:           profile,
+			Created:           time.Now(),
+			Bootstrapper:      viper.GetString(cmdcfg.Bootstrapper),
+			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+		}
+		if err := snapshot.Save(runner, r, s); err != nil {
+			glog.Errorln("Error saving snapshot: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Snapshot %s saved to %s\n", s.Name, snapshot.Dir(profile, s.Name))
+	},
+}
+
+// snapshotRestoreCmd represents the snapshot restore command
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restores a snapshot into the running local cluster.",
+	Long: `Restores a snapshot into the running local cluster, replacing its state.
+Start a fresh cluster with minikube start first. Certificates are regenerated for the current VM from the restored certificate authority.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "usage: minikube snapshot restore NAME")
+			os.Exit(1)
+		}
+		name := args[0]
+		profile := viper.GetString(cfg.MachineProfile)
+		bootstrapperName := viper.GetString(cmdcfg.Bootstrapper)
+
+		current, err := loadConfigFromFile(profile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+		s, err := snapshot.Load(profile, name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if s.KubernetesVersion != current.KubernetesConfig.KubernetesVersion {
+			fmt.Fprintf(os.Stderr, "Snapshot %s was taken from Kubernetes %s, but the cluster runs %s\n",
+				name, s.KubernetesVersion, current.KubernetesConfig.KubernetesVersion)
+			os.Exit(1)
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		runner := snapshotRunnerOrExit(api)
+		r, err := cruntime.New(current.KubernetesConfig.ContainerRuntime)
+		if err != nil {
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		fmt.Printf("Restoring snapshot %s...\n", name)
+		if _, err := snapshot.Restore(runner, r, profile, name, bootstrapperName); err != nil {
+			glog.Errorln("Error restoring snapshot: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		// The snapshot may come from another VM, keep what describes this one
+		cc, err := loadConfigFromFile(profile)
+		if err != nil {
+			glog.Errorln("Error loading restored profile config: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		cc.MachineConfig = current.MachineConfig
+		cc.Nodes = current.Nodes
+		cc.KubernetesConfig.NodeIP = current.KubernetesConfig.NodeIP
+		if err := saveConfig(cc); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+
+		k8sBootstrapper, err := GetClusterBootstrapper(api, bootstrapperName)
+		if err != nil {
+			glog.Errorln("Error getting cluster bootstrapper: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Moving files into cluster...")
+		if err := k8sBootstrapper.UpdateCluster(cc.KubernetesConfig); err != nil {
+			glog.Errorln("Error updating cluster: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Setting up certs...")
+		if err := k8sBootstrapper.SetupCerts(cc.KubernetesConfig); err != nil {
+			glog.Errorln("Error configuring authentication: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Println("Restarting cluster components...")
+		if err := k8sBootstrapper.RestartCluster(cc.KubernetesConfig); err != nil {
+			glog.Errorln("Error restarting cluster: ", err)
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Snapshot %s restored.\n", name)
+	},
+}
+
+// snapshotListCmd represents the snapshot list command
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the snapshots of the current profile.",
+	Long:  "Lists the snapshots of the current profile.",
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshots, err := snapshot.List(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing snapshots: %s\n", err)
+			os.Exit(1)
+		}
+
+		var data [][]string
+		for _, s := range snapshots {
+			data = append(data, []string{s.Name, s.Created.Format(time.RFC3339), s.Bootstrapper, s.KubernetesVersion})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Created", "Bootstrapper", "Kubernetes Version"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.AppendBulk(data)
+		table.Render()
+	},
+}
+
+// snapshotRunnerOrExit returns a command runner for the running cluster VM.
+func snapshotRunnerOrExit(api libmachine.API) bootstrapper.CommandRunner {
+	cluster.EnsureMinikubeRunningOrExit(api, 1)
+	h, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		glog.Errorln("Error getting host: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	runner, err := machine.GetCommandRunner(h)
+	if err != nil {
+		glog.Errorln("Error getting command runner: ", err)
+		cmdUtil.MaybeReportErrorAndExit(err)
+	}
+	return runner
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	RootCmd.AddCommand(snapshotCmd)
+}
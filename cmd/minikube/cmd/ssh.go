@@ -23,6 +23,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
@@ -49,6 +50,10 @@ var sshCmd = &cobra.Command{
 			os.Exit(0)
 		}
 		err = cluster.CreateSSHShell(api, args)
+		if exitErr, ok := errors.Cause(err).(*ssh.ExitError); ok {
+			// The command ran and failed: exit with its status, like ssh does
+			os.Exit(exitErr.ExitStatus())
+		}
 		if err != nil {
 			glog.Errorln(errors.Wrap(err, "Error attempting to ssh/run-ssh-command"))
 			os.Exit(1)
@@ -32,13 +32,16 @@ import (
 	"github.com/docker/machine/libmachine/host"
 	"github.com/golang/glog"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdutil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bundle"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/kubernetes_versions"
 	"k8s.io/minikube/pkg/minikube/machine"
 	pkgutil "k8s.io/minikube/pkg/util"
@@ -70,6 +73,11 @@ const (
 	disableDriverMounts   = "disable-driver-mounts"
 	cacheImages           = "cache-images"
 	uuid                  = "uuid"
+	clusterSpecFile       = "config"
+	offline               = "offline"
+	bundleFile            = "bundle"
+	caCertFile            = "ca-cert"
+	caKeyFile             = "ca-key"
 )
 
 var (
@@ -96,12 +104,46 @@ func runStart(cmd *cobra.Command, args []string) {
 		glog.Infoln("Viper configuration:")
 		viper.Debug()
 	}
-	shouldCacheImages := viper.GetBool(cacheImages)
+	var spec *cmdcfg.ClusterSpec
+	if specFile := viper.GetString(clusterSpecFile); specFile != "" {
+		var err error
+		spec, err = cmdcfg.LoadClusterSpec(specFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading cluster spec %s: %s\n", specFile, err)
+			os.Exit(1)
+		}
+		applyClusterSpec(cmd.Flags(), spec)
+	}
+	if _, err := cruntime.New(viper.GetString(containerRuntime)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	caCertPath, caKeyPath := caFilesOrExit()
+	if path := viper.GetString(bundleFile); path != "" {
+		fmt.Printf("Importing bundle %s...\n", path)
+		m, err := bundle.Import(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing bundle %s: %s\n", path, err)
+			os.Exit(1)
+		}
+		applyBundleManifest(cmd.Flags(), m)
+	}
+	// A bundle has to provide everything the cluster needs, or it would be
+	// downloaded anyway
+	if viper.GetBool(offline) || viper.GetString(bundleFile) != "" {
+		checkCachedArtifactsOrExit()
+	}
+	// Without network access, images can only come from the cache
+	shouldCacheImages := viper.GetBool(cacheImages) || viper.GetBool(offline)
 	k8sVersion := viper.GetString(kubernetesVersion)
 	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
 
 	if shouldCacheImages {
-		go machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper)
+		go func() {
+			if err := machine.CacheImagesForBootstrapper(k8sVersion, clusterBootstrapper); err == nil {
+				maybePruneImageCache(bootstrapper.GetCachedImageList(k8sVersion, clusterBootstrapper)...)
+			}
+		}()
 	}
 	api, err := machine.NewAPIClient()
 	if err != nil {
@@ -196,6 +238,20 @@ func runStart(cmd *cobra.Command, args []string) {
 			selectedKubernetesVersion = version.VersionPrefix + oldKubernetesVersion.String()
 			fmt.Println("Kubernetes version downgrade is not supported. Using version:", selectedKubernetesVersion)
 		}
+
+		// The CA given to an earlier start keeps signing the cluster certs
+		if caCertPath == "" {
+			caCertPath, caKeyPath = cc.KubernetesConfig.CACertFile, cc.KubernetesConfig.CAKeyFile
+		} else if exists && caCertPath != cc.KubernetesConfig.CACertFile {
+			fmt.Printf("Replacing the cluster CA with %s. Certificates generated inside the VM still use the previous CA. To reissue every cluster certificate with the new one, run: minikube certs rotate --ca\n", caCertPath)
+		}
+
+		// kubeadm clusters are upgraded explicitly with kubeadm upgrade
+		if exists && clusterBootstrapper == bootstrapper.BootstrapperTypeKubeadm && newKubernetesVersion.GT(oldKubernetesVersion) {
+			selectedKubernetesVersion = version.VersionPrefix + oldKubernetesVersion.String()
+			fmt.Printf("Using version %s. To upgrade the cluster, run: minikube upgrade --kubernetes-version %s\n",
+				selectedKubernetesVersion, viper.GetString(kubernetesVersion))
+		}
 	}
 
 	kubernetesConfig := bootstrapper.KubernetesConfig{
@@ -212,6 +268,8 @@ func runStart(cmd *cobra.Command, args []string) {
 		ServiceCIDR:            pkgutil.DefaultServiceCIDR,
 		ExtraOptions:           extraOptions,
 		ShouldLoadCachedImages: shouldCacheImages,
+		CACertFile:             caCertPath,
+		CAKeyFile:              caKeyPath,
 	}
 
 	k8sBootstrapper, err := GetClusterBootstrapper(api, clusterBootstrapper)
@@ -223,6 +281,7 @@ func runStart(cmd *cobra.Command, args []string) {
 	clusterConfig := cluster.Config{
 		MachineConfig:    config,
 		KubernetesConfig: kubernetesConfig,
+		Nodes:            withControlPlaneNode(cc.Nodes, cfg.GetMachineName(), ip),
 	}
 
 	if err := saveConfig(clusterConfig); err != nil {
@@ -242,28 +301,13 @@ func runStart(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Println("Connecting to cluster...")
-	kubeHost, err := host.Driver.GetURL()
+	kubeHost, err := getKubeHost(host)
 	if err != nil {
 		glog.Errorln("Error connecting to cluster: ", err)
 	}
-	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
-	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(pkgutil.APIServerPort), -1)
 
 	fmt.Println("Setting up kubeconfig...")
-	// setup kubeconfig
-
-	kubeConfigFile := cmdutil.GetKubeConfigPath()
-
-	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
-		ClusterName:          cfg.GetMachineName(),
-		ClusterServerAddress: kubeHost,
-		ClientCertificate:    constants.MakeMiniPath("client.crt"),
-		ClientKey:            constants.MakeMiniPath("client.key"),
-		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
-		KeepContext:          viper.GetBool(keepContext),
-	}
-	kubeCfgSetup.SetKubeConfigFile(kubeConfigFile)
-
+	kubeCfgSetup := newKubeConfigSetup(kubeHost, viper.GetBool(keepContext))
 	if err := kubeconfig.SetupKubeConfig(kubeCfgSetup); err != nil {
 		glog.Errorln("Error setting up kubeconfig: ", err)
 		cmdutil.MaybeReportErrorAndExit(err)
@@ -283,16 +327,41 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	// start 9p server mount
+	if len(clusterConfig.Workers()) > 0 {
+		fmt.Println("Starting additional nodes...")
+		if err := startWorkerNodes(api, &clusterConfig, !exists); err != nil {
+			glog.Errorln("Error starting additional nodes: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+	}
+
+	if spec != nil {
+		for _, addon := range spec.Addons {
+			fmt.Printf("Enabling addon %s...\n", addon)
+			if err := cmdcfg.Set(addon, "true"); err != nil {
+				glog.Errorf("Error enabling addon %s: %s", addon, err)
+				cmdutil.MaybeReportErrorAndExit(err)
+			}
+		}
+	}
+
+	// start 9p server mounts
+	var mounts []string
 	if viper.GetBool(createMount) {
-		fmt.Printf("Setting up hostmount on %s...\n", viper.GetString(mountString))
+		mounts = []string{viper.GetString(mountString)}
+	} else if spec != nil {
+		mounts = spec.Mounts
+	}
+	var mountPids []string
+	for _, m := range mounts {
+		fmt.Printf("Setting up hostmount on %s...\n", m)
 
 		path := os.Args[0]
 		mountDebugVal := 0
 		if glog.V(8) {
 			mountDebugVal = 1
 		}
-		mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), viper.GetString(mountString))
+		mountCmd := exec.Command(path, "mount", fmt.Sprintf("--v=%d", mountDebugVal), m)
 		mountCmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
 		if glog.V(8) {
 			mountCmd.Stdout = os.Stdout
@@ -303,7 +372,10 @@ func runStart(cmd *cobra.Command, args []string) {
 			glog.Errorf("Error running command minikube mount %s", err)
 			cmdutil.MaybeReportErrorAndExit(err)
 		}
-		err = ioutil.WriteFile(filepath.Join(constants.GetMinipath(), constants.MountProcessFileName), []byte(strconv.Itoa(mountCmd.Process.Pid)), 0644)
+		mountPids = append(mountPids, strconv.Itoa(mountCmd.Process.Pid))
+	}
+	if len(mountPids) > 0 {
+		err = ioutil.WriteFile(filepath.Join(constants.GetMinipath(), constants.MountProcessFileName), []byte(strings.Join(mountPids, "\n")), 0644)
 		if err != nil {
 			glog.Errorf("Error writing mount process pid to file: %s", err)
 			cmdutil.MaybeReportErrorAndExit(err)
@@ -346,11 +418,151 @@ This can also be done automatically by setting the env var CHANGE_MINIKUBE_NONE_
 		}
 	}
 
+	if spec != nil && len(spec.CacheImages) > 0 {
+		if err := cmdcfg.AddToConfigMap(constants.Cache, spec.CacheImages); err != nil {
+			glog.Errorln("Error adding cached images to config file: ", err)
+		}
+	}
+
 	fmt.Println("Loading cached images from config file.")
 	err = LoadCachedImagesInConfigFile()
 	if err != nil {
 		fmt.Println("Unable to load cached images from config file.")
 	}
+
+	if viper.GetBool(offline) {
+		fmt.Println("Loading addon images from the cache.")
+		if err := loadAddonImages(); err != nil {
+			glog.Errorln("Error loading addon images: ", err)
+			fmt.Println("Unable to load addon images from the cache.")
+		}
+	}
+}
+
+// applyBundleManifest uses the versions of the artifacts in a bundle for
+// every start flag that was not given on the command line.
+func applyBundleManifest(flags *pflag.FlagSet, m *bundle.Manifest) {
+	for name, value := range map[string]string{
+		isoURL:              m.ISOURL,
+		kubernetesVersion:   m.KubernetesVersion,
+		cmdcfg.Bootstrapper: m.Bootstrapper,
+	} {
+		if value != "" && !flags.Changed(name) {
+			viper.Set(name, value)
+		}
+	}
+}
+
+// checkCachedArtifactsOrExit exits listing the artifacts missing from the
+// cache for the cluster to start without network access.
+func checkCachedArtifactsOrExit() {
+	k8sVersion := viper.GetString(kubernetesVersion)
+	clusterBootstrapper := viper.GetString(cmdcfg.Bootstrapper)
+	images, err := bundle.Images(clusterBootstrapper, k8sVersion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing images: %s\n", err)
+		os.Exit(1)
+	}
+	artifacts, err := bundle.Artifacts(viper.GetString(isoURL), clusterBootstrapper, k8sVersion, images)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	missing := bundle.Missing(artifacts)
+	if len(missing) == 0 {
+		return
+	}
+	if path := viper.GetString(bundleFile); path != "" {
+		fmt.Fprintf(os.Stderr, "The bundle %s is incomplete, these artifacts are missing from the cache:\n", path)
+	} else {
+		fmt.Fprintln(os.Stderr, "Cannot start offline, these artifacts are missing from the cache:")
+	}
+	for _, a := range missing {
+		fmt.Fprintf(os.Stderr, "  - %s (%s)\n", a.Description, a.Path)
+	}
+	fmt.Fprintln(os.Stderr, "Create a bundle with 'minikube cache bundle export' on a machine with network access and pass it with --bundle.")
+	os.Exit(1)
+}
+
+// loadAddonImages loads the images of the enabled addons from the cache.
+func loadAddonImages() error {
+	images, err := bundle.AddonImages()
+	if err != nil {
+		return err
+	}
+	r, err := GetContainerRuntime()
+	if err != nil {
+		return err
+	}
+	return machine.CacheAndLoadImages(r, images)
+}
+
+// applyClusterSpec uses the values of a cluster spec for every start flag
+// that was not given on the command line.
+func applyClusterSpec(flags *pflag.FlagSet, spec *cmdcfg.ClusterSpec) {
+	setString := func(name, value string) {
+		if value != "" && !flags.Changed(name) {
+			viper.Set(name, value)
+		}
+	}
+	setInt := func(name string, value int) {
+		if value != 0 && !flags.Changed(name) {
+			viper.Set(name, value)
+		}
+	}
+	setString(vmDriver, spec.VMDriver)
+	setString(isoURL, spec.ISOURL)
+	setInt(memory, spec.Memory)
+	setInt(cpus, spec.CPUs)
+	setString(humanReadableDiskSize, spec.DiskSize)
+	setString(hostOnlyCIDR, spec.HostOnlyCIDR)
+	setString(cmdcfg.Bootstrapper, spec.Bootstrapper)
+	setString(kubernetesVersion, spec.KubernetesVersion)
+	setString(containerRuntime, spec.ContainerRuntime)
+	setString(networkPlugin, spec.NetworkPlugin)
+	setString(featureGates, spec.FeatureGatesString())
+
+	if !flags.Changed("docker-env") {
+		dockerEnv = append(dockerEnv, spec.DockerEnv...)
+	}
+	if !flags.Changed("insecure-registry") {
+		insecureRegistry = append(insecureRegistry, spec.InsecureRegistry...)
+	}
+	if !flags.Changed("registry-mirror") {
+		registryMirror = append(registryMirror, spec.RegistryMirror...)
+	}
+	if !flags.Changed("extra-config") {
+		for _, e := range spec.ExtraConfig {
+			// Already checked when the spec was validated
+			extraOptions.Set(e)
+		}
+	}
+}
+
+// caFilesOrExit returns the absolute paths of the CA given with --ca-cert and
+// --ca-key, after checking that it can sign the cluster certs
+func caFilesOrExit() (string, string) {
+	certPath, keyPath := viper.GetString(caCertFile), viper.GetString(caKeyFile)
+	if certPath == "" && keyPath == "" {
+		return "", ""
+	}
+	if certPath == "" || keyPath == "" {
+		fmt.Fprintf(os.Stderr, "--%s and --%s must be given together\n", caCertFile, caKeyFile)
+		os.Exit(1)
+	}
+	certPath, err := filepath.Abs(certPath)
+	if err == nil {
+		keyPath, err = filepath.Abs(keyPath)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving CA path: %s\n", err)
+		os.Exit(1)
+	}
+	if err := pkgutil.ValidateCA(certPath, keyPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid CA: %s\n", err)
+		os.Exit(1)
+	}
+	return certPath, keyPath
 }
 
 func validateK8sVersion(version string) {
@@ -391,10 +603,15 @@ func init() {
 	startCmd.Flags().StringSliceVar(&insecureRegistry, "insecure-registry", nil, "Insecure Docker registries to pass to the Docker daemon.  The default service CIDR range will automatically be added.")
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
 	startCmd.Flags().String(kubernetesVersion, constants.DefaultKubernetesVersion, "The kubernetes version that the minikube VM will use (ex: v1.2.3) \n OR a URI which contains a localkube binary (ex: https://storage.googleapis.com/minikube/k8sReleases/v1.3.0/localkube-linux-amd64)")
-	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used")
+	startCmd.Flags().String(containerRuntime, "", "The container runtime to be used (docker, cri-o, containerd, rkt)")
 	startCmd.Flags().String(networkPlugin, "", "The name of the network plugin")
 	startCmd.Flags().String(featureGates, "", "A set of key=value pairs that describe feature gates for alpha/experimental features.")
+	startCmd.Flags().String(clusterSpecFile, "", "A YAML file describing the cluster to start. Flags given on the command line take precedence over its values.")
 	startCmd.Flags().Bool(cacheImages, true, "If true, cache docker images for the current bootstrapper and load them into the machine.")
+	startCmd.Flags().Bool(offline, false, "If true, start without network access, using only artifacts in the cache. Fails listing the missing artifacts otherwise.")
+	startCmd.Flags().String(bundleFile, "", "A bundle created with 'minikube cache bundle export' to import into the cache before starting")
+	startCmd.Flags().String(caCertFile, "", "A PEM encoded CA or intermediate CA certificate to sign the cluster certificates with, instead of generating a minikubeCA. Requires --ca-key.")
+	startCmd.Flags().String(caKeyFile, "", "The PEM encoded RSA private key of --ca-cert. It is copied into the VM, where anyone with access to the VM can sign certificates trusted by whoever trusts the CA: use an intermediate CA limited with name constraints, not a root CA.")
 	startCmd.Flags().Var(&extraOptions, "extra-config",
 		`A set of key=value pairs that describe configuration that may be passed to different components.
 		The key should be '.' separated, and the first part before the dot is the component to apply the configuration to.
@@ -403,6 +620,32 @@ func init() {
 	RootCmd.AddCommand(startCmd)
 }
 
+// getKubeHost returns the URL of the apiserver running on the host
+func getKubeHost(h *host.Host) (string, error) {
+	kubeHost, err := h.Driver.GetURL()
+	if err != nil {
+		return "", err
+	}
+	kubeHost = strings.Replace(kubeHost, "tcp://", "https://", -1)
+	kubeHost = strings.Replace(kubeHost, ":2376", ":"+strconv.Itoa(pkgutil.APIServerPort), -1)
+	return kubeHost, nil
+}
+
+// newKubeConfigSetup returns the kubeconfig settings for the machine, using
+// the client cert and CA of the minikube directory
+func newKubeConfigSetup(kubeHost string, keepContext bool) *kubeconfig.KubeConfigSetup {
+	kubeCfgSetup := &kubeconfig.KubeConfigSetup{
+		ClusterName:          cfg.GetMachineName(),
+		ClusterServerAddress: kubeHost,
+		ClientCertificate:    constants.MakeMiniPath("client.crt"),
+		ClientKey:            constants.MakeMiniPath("client.key"),
+		CertificateAuthority: constants.MakeMiniPath("ca.crt"),
+		KeepContext:          keepContext,
+	}
+	kubeCfgSetup.SetKubeConfigFile(cmdutil.GetKubeConfigPath())
+	return kubeCfgSetup
+}
+
 // saveConfig saves profile cluster configuration in
 // $MINIKUBE_HOME/profiles/<profilename>/config.json
 func saveConfig(clusterConfig cluster.Config) error {
@@ -27,6 +27,7 @@ import (
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -39,7 +40,37 @@ var statusFormat string
 type Status struct {
 	MinikubeStatus   string
 	ClusterStatus    string
+	APIServerStatus  string
 	KubeconfigStatus string
+	Components       []bootstrapper.ComponentStatus
+}
+
+// StatusOutput is the machine-readable output of status
+type StatusOutput struct {
+	cmdcfg.TypeMeta
+	Host       string                         `json:"host"`
+	Cluster    string                         `json:"cluster"`
+	APIServer  string                         `json:"apiServer"`
+	Components []bootstrapper.ComponentStatus `json:"components,omitempty"`
+	Kubeconfig string                         `json:"kubeconfig"`
+	IP         string                         `json:"ip,omitempty"`
+	ExitCode   int                            `json:"exitCode"`
+	Reasons    StatusReasons                  `json:"reasons"`
+}
+
+// StatusReasons names the bits of the status exit code
+type StatusReasons struct {
+	HostNotRunning          bool `json:"hostNotRunning"`
+	ClusterNotRunning       bool `json:"clusterNotRunning"`
+	KubeconfigMisconfigured bool `json:"kubeconfigMisconfigured"`
+}
+
+func newStatusReasons(returnCode int) StatusReasons {
+	return StatusReasons{
+		HostNotRunning:          returnCode&minikubeNotRunningStatusFlag != 0,
+		ClusterNotRunning:       returnCode&clusterNotRunningStatusFlag != 0,
+		KubeconfigMisconfigured: returnCode&k8sNotRunningStatusFlag != 0,
+	}
 }
 
 const internalErrorCode = -1
@@ -56,9 +87,15 @@ var statusCmd = &cobra.Command{
 	Short: "Gets the status of a local kubernetes cluster",
 	Long: `Gets the status of a local kubernetes cluster.
 	Exit status contains the status of minikube's VM, cluster and kubernetes encoded on it's bits in this order from right to left.
-	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)`,
+	Eg: 7 meaning: 1 (for minikube NOK) + 2 (for cluster NOK) + 4 (for kubernetes NOK)
+	The cluster is NOK when the apiserver is not healthy too.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var returnCode = 0
+		output, err := cmdcfg.GetOutputFormat()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(internalErrorCode)
+		}
 		api, err := machine.NewAPIClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
@@ -73,18 +110,24 @@ var statusCmd = &cobra.Command{
 		}
 
 		cs := state.None.String()
+		as := state.None.String()
+		var components []bootstrapper.ComponentStatus
 		ks := state.None.String()
+		kubeconfigState := state.None.String()
+		ipAddress := ""
 		if ms == state.Running.String() {
 			clusterBootstrapper, err := GetClusterBootstrapper(api, viper.GetString(cmdcfg.Bootstrapper))
 			if err != nil {
 				glog.Errorf("Error getting cluster bootstrapper: %s", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			}
-			cs, err = clusterBootstrapper.GetClusterStatus()
+			clusterStatus, err := clusterBootstrapper.GetClusterStatus()
 			if err != nil {
 				glog.Errorln("Error cluster status:", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
-			} else if cs != state.Running.String() {
+			}
+			cs, as, components = clusterStatus.Cluster, clusterStatus.APIServer, clusterStatus.Components
+			if cs != state.Running.String() || as != state.Running.String() {
 				returnCode |= clusterNotRunningStatusFlag
 			}
 
@@ -98,9 +141,12 @@ var statusCmd = &cobra.Command{
 				glog.Errorln("Error kubeconfig status:", err)
 				cmdUtil.MaybeReportErrorAndExitWithCode(err, internalErrorCode)
 			}
+			ipAddress = ip.String()
 			if kstatus {
 				ks = "Correctly Configured: pointing to minikube-vm at " + ip.String()
+				kubeconfigState = "Configured"
 			} else {
+				kubeconfigState = "Misconfigured"
 				ks = "Misconfigured: pointing to stale minikube-vm." +
 					"\nTo fix the kubectl context, run minikube update-context"
 				returnCode |= k8sNotRunningStatusFlag
@@ -109,7 +155,32 @@ var statusCmd = &cobra.Command{
 			returnCode |= minikubeNotRunningStatusFlag
 		}
 
-		status := Status{ms, cs, ks}
+		if output != cmdcfg.OutputText {
+			status := StatusOutput{
+				TypeMeta:   cmdcfg.NewTypeMeta("Status"),
+				Host:       ms,
+				Cluster:    cs,
+				APIServer:  as,
+				Components: components,
+				Kubeconfig: kubeconfigState,
+				IP:         ipAddress,
+				ExitCode:   returnCode,
+				Reasons:    newStatusReasons(returnCode),
+			}
+			if err := cmdcfg.PrintStructured(os.Stdout, output, status); err != nil {
+				glog.Errorln("Error printing status:", err)
+				os.Exit(internalErrorCode)
+			}
+			os.Exit(returnCode)
+		}
+
+		status := Status{
+			MinikubeStatus:   ms,
+			ClusterStatus:    cs,
+			APIServerStatus:  as,
+			KubeconfigStatus: ks,
+			Components:       components,
+		}
 
 		tmpl, err := template.New("status").Parse(statusFormat)
 		if err != nil {
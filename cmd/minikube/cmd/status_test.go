@@ -0,0 +1,37 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func TestNewStatusReasons(t *testing.T) {
+	var tests = []struct {
+		code     int
+		expected StatusReasons
+	}{
+		{code: 0, expected: StatusReasons{}},
+		{code: 1, expected: StatusReasons{HostNotRunning: true}},
+		{code: 2, expected: StatusReasons{ClusterNotRunning: true}},
+		{code: 4, expected: StatusReasons{KubeconfigMisconfigured: true}},
+		{code: 7, expected: StatusReasons{HostNotRunning: true, ClusterNotRunning: true, KubeconfigMisconfigured: true}},
+	}
+	for _, test := range tests {
+		if r := newStatusReasons(test.code); r != test.expected {
+			t.Errorf("Exit code %d: expected %+v, got %+v", test.code, test.expected, r)
+		}
+	}
+}
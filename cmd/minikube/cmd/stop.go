@@ -20,9 +20,13 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	cmdUtil "k8s.io/minikube/cmd/util"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	pkg_config "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
@@ -41,15 +45,32 @@ itself, leaving all files intact. The cluster can be started again with the "sta
 		}
 		defer api.Close()
 
+		cc, err := loadConfigFromFile(viper.GetString(pkg_config.MachineProfile))
+		if err != nil && !os.IsNotExist(err) {
+			glog.Errorln("Error loading profile config: ", err)
+		}
+		// Keep stopping the other machines when one fails, and report once
+		var errs []error
+		for _, n := range cc.Workers() {
+			if err := cluster.StopNamedHost(api, n.Name); err != nil {
+				fmt.Printf("Error stopping node %s: %s\n", n.Name, err)
+				errs = append(errs, errors.Wrapf(err, "stopping node %s", n.Name))
+			}
+		}
+
 		if err = cluster.StopHost(api); err != nil {
 			fmt.Println("Error stopping machine: ", err)
-			cmdUtil.MaybeReportErrorAndExit(err)
+			errs = append(errs, errors.Wrap(err, "stopping machine"))
+		} else {
+			fmt.Println("Machine stopped.")
 		}
-		fmt.Println("Machine stopped.")
 
 		if err := cmdUtil.KillMountProcess(); err != nil {
 			fmt.Println("Errors occurred deleting mount process: ", err)
 		}
+		if len(errs) > 0 {
+			cmdUtil.MaybeReportErrorAndExit(fmt.Errorf("stopping cluster: %v", errs))
+		}
 	},
 }
 
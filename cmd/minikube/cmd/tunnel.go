@@ -0,0 +1,90 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	cmdUtil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+	pkgutil "k8s.io/minikube/pkg/util"
+)
+
+var tunnelCleanup bool
+var tunnelInterval time.Duration
+
+// tunnelCmd represents the tunnel command
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel",
+	Short: "Routes the service network of the cluster from the host and exposes LoadBalancer services",
+	Long: `Routes the service network of the cluster from the host and exposes LoadBalancer services.
+
+While it runs, the host can reach the cluster IP of every service, and every LoadBalancer
+service gets its cluster IP as ingress IP. Adding the route needs root, so you may be asked
+for your sudo password. The route is removed when the tunnel exits. Routes left behind by a
+tunnel that crashed are removed the next time a tunnel starts, or with --cleanup.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		registry := tunnel.NewRegistry(constants.TunnelRegistryFile)
+		cleaned, err := tunnel.CleanupStale(registry)
+		for _, e := range cleaned {
+			fmt.Printf("Removed stale route %s via %s of %s\n", e.DestCIDR, e.Gateway, e.Machine)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error cleaning up stale tunnels: %s\n", err)
+			os.Exit(1)
+		}
+		if tunnelCleanup {
+			return
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+
+		done := make(chan struct{})
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			close(done)
+		}()
+
+		fmt.Printf("Routing %s to %s, press Ctrl-C to stop.\n", pkgutil.DefaultServiceCIDR, config.GetMachineName())
+		t := tunnel.NewTunnel(api, config.GetMachineName(), pkgutil.DefaultServiceCIDR, registry, service.K8s.GetCoreClient, os.Stdout)
+		if err := t.Run(done, tunnelInterval); err != nil {
+			cmdUtil.MaybeReportErrorAndExit(err)
+		}
+	},
+}
+
+func init() {
+	tunnelCmd.Flags().BoolVar(&tunnelCleanup, "cleanup", false, "Remove the routes of tunnels that are no longer running, and exit")
+	tunnelCmd.Flags().DurationVar(&tunnelInterval, "interval", 5*time.Second, "How often to check the VM IP and the LoadBalancer services")
+	RootCmd.AddCommand(tunnelCmd)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	cmdutil "k8s.io/minikube/cmd/util"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	cfg "k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/version"
+)
+
+var upgradeKubernetesVersion string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades the Kubernetes version of the local cluster",
+	Long: `Upgrades the Kubernetes version of the local cluster with kubeadm upgrade.
+The control plane is upgraded first, then the kubelet of every node. Only the kubeadm bootstrapper supports upgrades, one minor version at a time.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if upgradeKubernetesVersion == "" {
+			fmt.Fprintln(os.Stderr, "usage: minikube upgrade --kubernetes-version VERSION")
+			os.Exit(1)
+		}
+		if viper.GetString(cmdcfg.Bootstrapper) != bootstrapper.BootstrapperTypeKubeadm {
+			fmt.Fprintln(os.Stderr, "Upgrades are only supported by the kubeadm bootstrapper")
+			os.Exit(1)
+		}
+		targetVersion := upgradeKubernetesVersion
+		if !strings.HasPrefix(targetVersion, version.VersionPrefix) {
+			targetVersion = version.VersionPrefix + targetVersion
+		}
+
+		api, err := machine.NewAPIClient()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting client: %s\n", err)
+			os.Exit(1)
+		}
+		defer api.Close()
+		cluster.EnsureMinikubeRunningOrExit(api, 1)
+
+		cc, err := loadConfigFromFile(viper.GetString(cfg.MachineProfile))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading profile config: %s\n", err)
+			os.Exit(1)
+		}
+		target := cc.KubernetesConfig
+		target.KubernetesVersion = targetVersion
+
+		k, err := kubeadm.NewKubeadmBootstrapper(api)
+		if err != nil {
+			glog.Errorln("Error getting kubeadm bootstrapper: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+		fmt.Printf("Upgrading local Kubernetes cluster from %s to %s...\n", cc.KubernetesConfig.KubernetesVersion, targetVersion)
+		if err := k.UpgradeCluster(cc.KubernetesConfig, target, os.Stdout); err != nil {
+			glog.Errorln("Error upgrading cluster: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+
+		// Record the new version before upgrading nodes so a failure there
+		// does not make the next start undo the control plane upgrade
+		cc.KubernetesConfig = target
+		if err := saveConfig(cc); err != nil {
+			glog.Errorln("Error saving profile cluster configuration: ", err)
+			cmdutil.MaybeReportErrorAndExit(err)
+		}
+
+		for _, n := range cc.Workers() {
+			fmt.Printf("Upgrading node %s to %s...\n", n.Name, targetVersion)
+			worker, err := kubeadm.NewKubeadmBootstrapperForMachine(api, n.Name)
+			if err != nil {
+				glog.Errorf("Error getting bootstrapper for %s: %s", n.Name, err)
+				cmdutil.MaybeReportErrorAndExit(err)
+			}
+			if err := worker.UpgradeNode(target, n.Name); err != nil {
+				glog.Errorf("Error upgrading node %s: %s", n.Name, err)
+				cmdutil.MaybeReportErrorAndExit(err)
+			}
+		}
+		fmt.Printf("The local Kubernetes cluster was upgraded to %s.\n", targetVersion)
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeKubernetesVersion, kubernetesVersion, "", "The kubernetes version to upgrade the cluster to (ex: v1.9.4)")
+	RootCmd.AddCommand(upgradeCmd)
+}
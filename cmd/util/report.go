@@ -0,0 +1,190 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const (
+	// ReportSinkDirPrefix selects writing error reports to a directory
+	ReportSinkDirPrefix = "dir:"
+	// ReportSinkExecPrefix selects piping error reports to a command
+	ReportSinkExecPrefix = "exec:"
+)
+
+// ErrorReporter sends the JSON error reports of minikube somewhere
+type ErrorReporter interface {
+	Report(report []byte) error
+	// String describes where reports are sent
+	String() string
+}
+
+// NewErrorReporter returns the reporter of a ReportErrorSink setting:
+//   - empty, to upload reports to the minikube error reporting service
+//   - an http:// or https:// URL, to POST reports to it with the headers
+//   - dir:DIRECTORY, to write each report to a file in DIRECTORY
+//   - exec:COMMAND, to pipe each report to COMMAND, run by the shell
+//
+// headers is a comma-separated list of Name=value pairs, in which environment
+// variables are expanded so that tokens need not be stored in the config.
+func NewErrorReporter(sink, headers string) (ErrorReporter, error) {
+	switch {
+	case sink == "":
+		return &httpReporter{url: constants.ReportingURL, description: "minikube"}, nil
+	case strings.HasPrefix(sink, ReportSinkDirPrefix):
+		dir := strings.TrimPrefix(sink, ReportSinkDirPrefix)
+		if dir == "" {
+			return nil, fmt.Errorf("no directory in error report sink %q", sink)
+		}
+		return &dirReporter{dir: dir}, nil
+	case strings.HasPrefix(sink, ReportSinkExecPrefix):
+		command := strings.TrimSpace(strings.TrimPrefix(sink, ReportSinkExecPrefix))
+		if command == "" {
+			return nil, fmt.Errorf("no command in error report sink %q", sink)
+		}
+		return &execReporter{command: command}, nil
+	case strings.HasPrefix(sink, "http://") || strings.HasPrefix(sink, "https://"):
+		h, err := parseReportHeaders(headers)
+		if err != nil {
+			return nil, err
+		}
+		return &httpReporter{url: sink, headers: h, description: sink}, nil
+	default:
+		return nil, fmt.Errorf("unknown error report sink %q, must be an http(s) URL, %sDIRECTORY or %sCOMMAND", sink, ReportSinkDirPrefix, ReportSinkExecPrefix)
+	}
+}
+
+// GetErrorReporter returns the reporter configured with ReportErrorSink and
+// ReportErrorHeaders
+func GetErrorReporter() (ErrorReporter, error) {
+	return NewErrorReporter(viper.GetString(config.ReportErrorSink), viper.GetString(config.ReportErrorHeaders))
+}
+
+// ignoredHeadersWarning returns a warning when headers are set for a dir: or
+// exec: sink, which does not send them, and an empty string otherwise
+func ignoredHeadersWarning(sink, headers string) string {
+	if strings.TrimSpace(headers) == "" {
+		return ""
+	}
+	if !strings.HasPrefix(sink, ReportSinkDirPrefix) && !strings.HasPrefix(sink, ReportSinkExecPrefix) {
+		return ""
+	}
+	return fmt.Sprintf("%s is ignored by the error report sink %q, only URL sinks send headers", config.ReportErrorHeaders, sink)
+}
+
+func parseReportHeaders(headers string) (map[string]string, error) {
+	h := map[string]string{}
+	for _, pair := range strings.Split(headers, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("invalid error report header %q, must be Name=value", pair)
+		}
+		h[strings.TrimSpace(kv[0])] = os.ExpandEnv(strings.TrimSpace(kv[1]))
+	}
+	return h, nil
+}
+
+type httpReporter struct {
+	url         string
+	headers     map[string]string
+	description string
+}
+
+func (r *httpReporter) Report(b []byte) error {
+	req, err := http.NewRequest("POST", r.url, bytes.NewBuffer(b))
+	if err != nil {
+		return errors.Wrap(err, "")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range r.headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("Error sending error report to %s, got response code %d", r.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *httpReporter) String() string {
+	return r.description
+}
+
+type dirReporter struct {
+	dir string
+}
+
+func (r *dirReporter) Report(b []byte) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return errors.Wrapf(err, "making error report directory %s", r.dir)
+	}
+	name := fmt.Sprintf("minikube-error-%s-%d.json", time.Now().UTC().Format("20060102T150405Z"), os.Getpid())
+	if err := ioutil.WriteFile(filepath.Join(r.dir, name), b, 0600); err != nil {
+		return errors.Wrap(err, "writing error report")
+	}
+	return nil
+}
+
+func (r *dirReporter) String() string {
+	return r.dir
+}
+
+type execReporter struct {
+	command string
+}
+
+func (r *execReporter) Report(b []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", r.command)
+	} else {
+		cmd = exec.Command("/bin/sh", "-c", r.command)
+	}
+	cmd.Stdin = bytes.NewReader(b)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "running %s: %s", r.command, out)
+	}
+	return nil
+}
+
+func (r *execReporter) String() string {
+	return r.command
+}
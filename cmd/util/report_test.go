@@ -0,0 +1,169 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestNewErrorReporter(t *testing.T) {
+	var tests = []struct {
+		sink        string
+		headers     string
+		description string
+		shouldErr   bool
+	}{
+		{sink: "", description: "minikube"},
+		{sink: "https://errors.example.com/report", description: "https://errors.example.com/report"},
+		{sink: "dir:/var/log/minikube", description: "/var/log/minikube"},
+		{sink: "exec: logger -t minikube", description: "logger -t minikube"},
+		{sink: "dir:", shouldErr: true},
+		{sink: "exec:", shouldErr: true},
+		{sink: "ftp://errors.example.com", shouldErr: true},
+		{sink: "https://errors.example.com", headers: "X-Token", shouldErr: true},
+	}
+	for _, test := range tests {
+		r, err := NewErrorReporter(test.sink, test.headers)
+		if err != nil {
+			if !test.shouldErr {
+				t.Errorf("NewErrorReporter(%q): unexpected error: %s", test.sink, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Errorf("NewErrorReporter(%q): expected an error", test.sink)
+			continue
+		}
+		if r.String() != test.description {
+			t.Errorf("NewErrorReporter(%q) reports to %q, expected %q", test.sink, r.String(), test.description)
+		}
+	}
+}
+
+func TestIgnoredHeadersWarning(t *testing.T) {
+	var tests = []struct {
+		sink    string
+		headers string
+		warns   bool
+	}{
+		{sink: "https://errors.example.com", headers: "X-Token=abc"},
+		{sink: "dir:/var/log/minikube"},
+		{sink: "dir:/var/log/minikube", headers: "X-Token=abc", warns: true},
+		{sink: "exec: logger -t minikube", headers: "X-Token=abc", warns: true},
+	}
+	for _, test := range tests {
+		w := ignoredHeadersWarning(test.sink, test.headers)
+		if (w != "") != test.warns {
+			t.Errorf("ignoredHeadersWarning(%q, %q) = %q, expected a warning: %v", test.sink, test.headers, w, test.warns)
+		}
+	}
+}
+
+func TestHTTPReporterHeaders(t *testing.T) {
+	os.Setenv("MINIKUBE_TEST_REPORT_TOKEN", "s3cr3t")
+	defer os.Unsetenv("MINIKUBE_TEST_REPORT_TOKEN")
+
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	r, err := NewErrorReporter(server.URL, "Authorization=Bearer $MINIKUBE_TEST_REPORT_TOKEN, X-Team=platform")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := r.Report([]byte("{}")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for k, v := range map[string]string{
+		"Authorization": "Bearer s3cr3t",
+		"X-Team":        "platform",
+		"Content-Type":  "application/json",
+	} {
+		if got.Get(k) != v {
+			t.Errorf("Header %s = %q, expected %q", k, got.Get(k), v)
+		}
+	}
+}
+
+func TestDirReporter(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	dir := filepath.Join(tempDir, "reports")
+	r, err := NewErrorReporter(ReportSinkDirPrefix+dir, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := r.Report([]byte(`{"message":"boom"}`)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "minikube-error-*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected one report in %s, got %v (%v)", dir, files, err)
+	}
+	b, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Error reading report: %s", err)
+	}
+	if string(b) != `{"message":"boom"}` {
+		t.Errorf("Report contents = %q", b)
+	}
+}
+
+func TestExecReporter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec reporter test uses a POSIX shell")
+	}
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	out := filepath.Join(tempDir, "report.json")
+	r, err := NewErrorReporter(ReportSinkExecPrefix+"cat > "+out, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := r.Report([]byte(`{"message":"boom"}`)); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Error reading report: %s", err)
+	}
+	if string(b) != `{"message":"boom"}` {
+		t.Errorf("Report contents = %q", b)
+	}
+
+	r, _ = NewErrorReporter(ReportSinkExecPrefix+"exit 3", "")
+	if err := r.Report([]byte("{}")); err == nil {
+		t.Errorf("Expected an error from a failing command")
+	}
+}
@@ -18,13 +18,11 @@ package util
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -41,6 +39,7 @@ import (
 	minikubeConfig "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	pkgutil "k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/version"
 )
 
@@ -62,7 +61,8 @@ func init() {
 	lookPath = exec.LookPath
 }
 
-func ReportError(err error, url string) error {
+// ReportError sends a report of err with its stack trace to r
+func ReportError(err error, r ErrorReporter) error {
 	errMsg, err := FormatError(err)
 	if err != nil {
 		return errors.Wrap(err, "Error formatting error message")
@@ -71,9 +71,9 @@ func ReportError(err error, url string) error {
 	if err != nil {
 		return errors.Wrap(err, "Error marshalling error message to JSON")
 	}
-	err = UploadError(jsonErrorMsg, url)
+	err = r.Report(jsonErrorMsg)
 	if err != nil {
-		return errors.Wrap(err, "Error uploading error message")
+		return errors.Wrapf(err, "Error reporting error message to %s", r)
 	}
 	return nil
 }
@@ -109,21 +109,9 @@ func MarshallError(errMsg, service, version string) ([]byte, error) {
 	return b, nil
 }
 
+// UploadError POSTs a JSON error report to url
 func UploadError(b []byte, url string) error {
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(b))
-	if err != nil {
-		return errors.Wrap(err, "")
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "")
-	} else if resp.StatusCode != 200 {
-		return errors.Errorf("Error sending error report to %s, got response code %d", url, resp.StatusCode)
-	}
-	return nil
+	return (&httpReporter{url: url}).Report(b)
 }
 
 func MaybeReportErrorAndExit(errToReport error) {
@@ -131,20 +119,33 @@ func MaybeReportErrorAndExit(errToReport error) {
 }
 
 func MaybeReportErrorAndExitWithCode(errToReport error, returnCode int) {
-	var err error
+	r, err := GetErrorReporter()
+	if err != nil {
+		// the error is not reported, so say why
+		fmt.Fprintf(os.Stderr, "Error getting error reporter: %s\n", err)
+		glog.Errorf("Error getting error reporter: %s", err)
+		os.Exit(returnCode)
+	}
+	report := func() error {
+		if w := ignoredHeadersWarning(viper.GetString(config.ReportErrorSink), viper.GetString(config.ReportErrorHeaders)); w != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		return ReportError(errToReport, r)
+	}
 	if viper.GetBool(config.WantReportError) {
-		err = ReportError(errToReport, constants.ReportingURL)
+		err = report()
 	} else if viper.GetBool(config.WantReportErrorPrompt) {
-		fmt.Println(
+		fmt.Printf(
 			`================================================================================
 An error has occurred. Would you like to opt in to sending anonymized crash
-information to minikube to help prevent future errors?
+information to %s to help prevent future errors?
 To opt out of these messages, run the command:
 	minikube config set WantReportErrorPrompt false
-================================================================================`)
+================================================================================
+`, r)
 		if PromptUserForAccept(os.Stdin) {
 			minikubeConfig.Set(config.WantReportError, "true")
-			err = ReportError(errToReport, constants.ReportingURL)
+			err = report()
 		}
 	}
 	if err != nil {
@@ -235,20 +236,37 @@ func GetPort() (string, error) {
 	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port), nil
 }
 
+// KillMountProcess kills the mount processes started by minikube start. A
+// process failing to be killed, such as one that already exited, does not
+// stop the others from being killed. The errors are returned together once
+// every process was handled, and the pid file is removed.
 func KillMountProcess() error {
-	out, err := ioutil.ReadFile(filepath.Join(constants.GetMinipath(), constants.MountProcessFileName))
+	pidFile := filepath.Join(constants.GetMinipath(), constants.MountProcessFileName)
+	out, err := ioutil.ReadFile(pidFile)
 	if err != nil {
 		return nil // no mount process to kill
 	}
-	pid, err := strconv.Atoi(string(out))
-	if err != nil {
-		return errors.Wrap(err, "error converting mount string to pid")
+	m := pkgutil.MultiError{}
+	// minikube start writes one pid per line when it sets up several mounts
+	for _, p := range strings.Fields(string(out)) {
+		pid, err := strconv.Atoi(p)
+		if err != nil {
+			m.Collect(errors.Wrapf(err, "error converting mount string %q to pid", p))
+			continue
+		}
+		mountProc, err := os.FindProcess(pid)
+		if err != nil {
+			m.Collect(errors.Wrapf(err, "error finding mount process %d", pid))
+			continue
+		}
+		if err := mountProc.Kill(); err != nil {
+			m.Collect(errors.Wrapf(err, "error killing mount process %d", pid))
+		}
 	}
-	mountProc, err := os.FindProcess(pid)
-	if err != nil {
-		return errors.Wrap(err, "error converting mount string to pid")
+	if err := os.Remove(pidFile); err != nil {
+		m.Collect(errors.Wrap(err, "error removing mount pid file"))
 	}
-	return mountProc.Kill()
+	return m.ToError()
 }
 
 func GetKubeConfigPath() string {
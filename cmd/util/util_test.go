@@ -19,12 +19,19 @@ package util
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
 	"k8s.io/minikube/pkg/version"
 
 	"github.com/pkg/errors"
@@ -163,3 +170,36 @@ func TestKubectlDownloadMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestKillMountProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatalf("Error running true: %s", err)
+	}
+	running := exec.Command("sleep", "60")
+	if err := running.Start(); err != nil {
+		t.Fatalf("Error starting sleep: %s", err)
+	}
+	defer running.Process.Kill()
+
+	pidFile := filepath.Join(constants.GetMinipath(), constants.MountProcessFileName)
+	pids := fmt.Sprintf("%d\n%d", exited.Process.Pid, running.Process.Pid)
+	if err := ioutil.WriteFile(pidFile, []byte(pids), 0644); err != nil {
+		t.Fatalf("Error writing pid file: %s", err)
+	}
+	if err := KillMountProcess(); err == nil {
+		t.Error("Expected an error killing an exited process")
+	}
+	if err := running.Wait(); err == nil {
+		t.Error("Expected the mount process after the exited one to be killed")
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Error("Expected the pid file to be removed")
+	}
+}
@@ -0,0 +1,37 @@
+################################################################################
+#
+# containerd-bin
+#
+################################################################################
+
+CONTAINERD_BIN_VERSION = 1.2.0
+CONTAINERD_BIN_SITE = https://github.com/containerd/containerd/releases/download/v$(CONTAINERD_BIN_VERSION)
+CONTAINERD_BIN_SOURCE = containerd-$(CONTAINERD_BIN_VERSION).linux-amd64.tar.gz
+
+define CONTAINERD_BIN_INSTALL_TARGET_CMDS
+	$(INSTALL) -D -m 0755 \
+		$(@D)/bin/containerd \
+		$(TARGET_DIR)/usr/bin/containerd
+
+	$(INSTALL) -D -m 0755 \
+		$(@D)/bin/containerd-shim \
+		$(TARGET_DIR)/usr/bin/containerd-shim
+
+	$(INSTALL) -D -m 0755 \
+		$(@D)/bin/ctr \
+		$(TARGET_DIR)/usr/bin/ctr
+
+	$(INSTALL) -D -m 0644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/containerd-bin/config.toml \
+		$(TARGET_DIR)/etc/containerd/config.toml
+endef
+
+# containerd is not started at boot, minikube starts it when it is selected
+# with --container-runtime=containerd
+define CONTAINERD_BIN_INSTALL_INIT_SYSTEMD
+	$(INSTALL) -D -m 644 \
+		$(BR2_EXTERNAL_MINIKUBE_PATH)/package/containerd-bin/containerd.service \
+		$(TARGET_DIR)/usr/lib/systemd/system/containerd.service
+endef
+
+$(eval $(generic-package))
@@ -0,0 +1,70 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// render_addons writes the manifests of the bundled addons, rendered with
+// their default values, to the directory given as argument. Each manifest
+// keeps the path of its template, such as deploy/addons/dashboard.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: render_addons DIR")
+		os.Exit(1)
+	}
+	if err := render(os.Args[1]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func render(dir string) error {
+	for name, addon := range assets.Addons {
+		if addon.IsUserAddon() {
+			continue
+		}
+		values, err := addon.DefaultValues()
+		if err != nil {
+			return fmt.Errorf("getting values of addon %s: %v", name, err)
+		}
+		files, err := addon.RenderAssets(values)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			data, err := ioutil.ReadAll(f)
+			if err != nil {
+				return fmt.Errorf("reading %s: %v", f.GetAssetName(), err)
+			}
+			dst := filepath.Join(dir, filepath.FromSlash(f.GetAssetName()))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(dst, data, 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,451 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldType is the type of the value of an addon configuration field
+type FieldType string
+
+const (
+	// FieldString is a plain string value
+	FieldString FieldType = "string"
+	// FieldBool is a true or false value
+	FieldBool FieldType = "bool"
+	// FieldFile is the path of a file, whose contents are the value
+	FieldFile FieldType = "file"
+	// FieldInt is a whole number, no smaller than the Min of the field
+	FieldInt FieldType = "int"
+)
+
+// ConfigField is a value of an addon set with minikube addons configure
+type ConfigField struct {
+	Name        string
+	Description string
+	Type        FieldType
+	// Secret values are not echoed when prompted for
+	Secret  bool
+	Default string
+	// Optional fields may be left empty
+	Optional bool
+	// When is the name of a bool field the field is asked for after. If it
+	// is false, the field has its default value.
+	When string
+	// Min is the smallest value of an int field
+	Min int
+	// Rendered fields are stored in the minikube config, and the manifests
+	// of the addon are rendered with them. The other fields are only used to
+	// create the secrets of the addon.
+	Rendered bool
+}
+
+// EnvName returns the environment variable the value of the field is read from
+func (f ConfigField) EnvName(addon string) string {
+	name := fmt.Sprintf("MINIKUBE_ADDON_%s_%s", addon, f.Name)
+	return strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// SecretSpec is a secret created from the values of the configuration fields
+type SecretSpec struct {
+	Namespace string
+	Name      string
+	Labels    map[string]string
+	// Data maps the keys of the secret to the names of fields
+	Data map[string]string
+}
+
+// AddonSecret is a secret to create with the configured values
+type AddonSecret struct {
+	Namespace string
+	Name      string
+	Labels    map[string]string
+	Data      map[string]string
+}
+
+// AddonConfig is the schema of the configuration of an addon
+type AddonConfig struct {
+	Fields  []ConfigField
+	Secrets []SecretSpec
+}
+
+func registryCredsLabels(cloud string) map[string]string {
+	return map[string]string{
+		"app":                           "registry-creds",
+		"cloud":                         cloud,
+		"kubernetes.io/minikube-addons": "registry-creds",
+	}
+}
+
+var registryField = ConfigField{
+	Name:        "registry",
+	Description: "Registry host replacing the registry of every image of the addon, such as a mirror",
+	Type:        FieldString,
+	Optional:    true,
+	Rendered:    true,
+}
+
+func imageField(name, image string) ConfigField {
+	return ConfigField{Name: name, Description: "Image of the addon", Type: FieldString, Default: image, Rendered: true}
+}
+
+func replicasField() ConfigField {
+	return ConfigField{Name: "replicas", Description: "Number of replicas", Type: FieldInt, Default: "1", Min: 1, Rendered: true}
+}
+
+// addonConfigs are the schemas of the configuration of the bundled addons
+var addonConfigs = map[string]*AddonConfig{
+	"addon-manager": {Fields: []ConfigField{registryField, imageField("image", "gcr.io/google-containers/kube-addon-manager:v6.5")}},
+	"dashboard": {Fields: []ConfigField{registryField, replicasField(),
+		imageField("image", "k8s.gcr.io/kubernetes-dashboard-amd64:v1.8.1")}},
+	"storage-provisioner": {Fields: []ConfigField{registryField, imageField("image", "gcr.io/k8s-minikube/storage-provisioner:v1.8.1")}},
+	"coredns": {Fields: []ConfigField{registryField, replicasField(),
+		imageField("image", "registry.hub.docker.com/coredns/coredns:1.0.2")}},
+	"kube-dns": {Fields: []ConfigField{registryField, replicasField(),
+		imageField("kubeDNSImage", "k8s.gcr.io/k8s-dns-kube-dns-amd64:1.14.5"),
+		imageField("dnsmasqImage", "k8s.gcr.io/k8s-dns-dnsmasq-nanny-amd64:1.14.5"),
+		imageField("sidecarImage", "k8s.gcr.io/k8s-dns-sidecar-amd64:1.14.5")}},
+	"heapster": {Fields: []ConfigField{registryField,
+		imageField("heapsterImage", "k8s.gcr.io/heapster-amd64:v1.5.0"),
+		imageField("influxdbImage", "k8s.gcr.io/heapster-influxdb-amd64:v1.3.3"),
+		imageField("grafanaImage", "k8s.gcr.io/heapster-grafana-amd64:v4.4.3")}},
+	"efk": {Fields: []ConfigField{registryField,
+		imageField("elasticsearchImage", "gcr.io/google-containers/elasticsearch:v5.6.2"),
+		imageField("alpineImage", "registry.hub.docker.com/library/alpine:3.6"),
+		imageField("fluentdImage", "gcr.io/google-containers/fluentd-elasticsearch:v2.0.2"),
+		imageField("kibanaImage", "docker.elastic.co/kibana/kibana:5.6.2")}},
+	"ingress": {Fields: []ConfigField{registryField, replicasField(),
+		imageField("controllerImage", "quay.io/kubernetes-ingress-controller/nginx-ingress-controller:0.9.0"),
+		imageField("defaultBackendImage", "k8s.gcr.io/defaultbackend:1.4")}},
+	"registry": {Fields: []ConfigField{registryField, replicasField(),
+		imageField("image", "registry.hub.docker.com/library/registry:2.6.1")}},
+	"freshpod": {Fields: []ConfigField{registryField, imageField("image", "gcr.io/google-samples/freshpod:v0.0.1")}},
+	"registry-creds": {
+		Fields: []ConfigField{
+			registryField,
+			imageField("image", "registry.hub.docker.com/upmcenterprises/registry-creds:1.9"),
+			{Name: "enable-aws-ecr", Description: "Do you want to enable AWS Elastic Container Registry?", Type: FieldBool, Default: "false"},
+			{Name: "aws-access-key-id", Description: "Enter AWS Access Key ID", Type: FieldString, Default: "changeme", When: "enable-aws-ecr"},
+			{Name: "aws-secret-access-key", Description: "Enter AWS Secret Access Key", Type: FieldString, Secret: true, Default: "changeme", When: "enable-aws-ecr"},
+			{Name: "aws-session-token", Description: "(Optional) Enter AWS Session Token", Type: FieldString, Secret: true, Optional: true, When: "enable-aws-ecr"},
+			{Name: "aws-region", Description: "Enter AWS Region", Type: FieldString, Default: "changeme", When: "enable-aws-ecr"},
+			{Name: "aws-account", Description: "Enter 12 digit AWS Account ID (Comma seperated list)", Type: FieldString, Default: "changeme", When: "enable-aws-ecr"},
+			{Name: "aws-role", Description: "(Optional) Enter ARN of AWS role to assume", Type: FieldString, Optional: true, When: "enable-aws-ecr"},
+			{Name: "enable-gcr", Description: "Do you want to enable Google Container Registry?", Type: FieldBool, Default: "false"},
+			{Name: "gcr-credentials", Description: "Enter path to credentials (e.g. /home/user/.config/gcloud/application_default_credentials.json)", Type: FieldFile, Secret: true, Default: "changeme", When: "enable-gcr"},
+			{Name: "gcr-url", Description: "(Optional) Enter GCR URL (Default https://gcr.io)", Type: FieldString, Default: "https://gcr.io", Optional: true, When: "enable-gcr"},
+			{Name: "enable-docker-registry", Description: "Do you want to enable Docker Registry?", Type: FieldBool, Default: "false"},
+			{Name: "docker-server", Description: "Enter docker registry server url", Type: FieldString, Default: "changeme", When: "enable-docker-registry"},
+			{Name: "docker-user", Description: "Enter docker registry username", Type: FieldString, Default: "changeme", When: "enable-docker-registry"},
+			{Name: "docker-password", Description: "Enter docker registry password", Type: FieldString, Secret: true, Default: "changeme", When: "enable-docker-registry"},
+		},
+		Secrets: []SecretSpec{
+			{
+				Namespace: "kube-system",
+				Name:      "registry-creds-ecr",
+				Labels:    registryCredsLabels("ecr"),
+				Data: map[string]string{
+					"AWS_ACCESS_KEY_ID":     "aws-access-key-id",
+					"AWS_SECRET_ACCESS_KEY": "aws-secret-access-key",
+					"AWS_SESSION_TOKEN":     "aws-session-token",
+					"aws-account":           "aws-account",
+					"aws-region":            "aws-region",
+					"aws-assume-role":       "aws-role",
+				},
+			},
+			{
+				Namespace: "kube-system",
+				Name:      "registry-creds-gcr",
+				Labels:    registryCredsLabels("gcr"),
+				Data: map[string]string{
+					"application_default_credentials.json": "gcr-credentials",
+					"gcrurl":                               "gcr-url",
+				},
+			},
+			{
+				Namespace: "kube-system",
+				Name:      "registry-creds-dpr",
+				Labels:    registryCredsLabels("dpr"),
+				Data: map[string]string{
+					"DOCKER_PRIVATE_REGISTRY_SERVER":   "docker-server",
+					"DOCKER_PRIVATE_REGISTRY_USER":     "docker-user",
+					"DOCKER_PRIVATE_REGISTRY_PASSWORD": "docker-password",
+				},
+			},
+		},
+	},
+}
+
+// Config returns the configuration schema of the addon, or nil if it has
+// none, as for user addons
+func (a *Addon) Config() *AddonConfig {
+	return addonConfigs[a.addonName]
+}
+
+// RenderedFields returns the fields the manifests of the addon are rendered
+// with
+func (c *AddonConfig) RenderedFields() []ConfigField {
+	if c == nil {
+		return nil
+	}
+	var fields []ConfigField
+	for _, f := range c.Fields {
+		if f.Rendered {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// HasSecrets returns whether the addon is configured with secrets
+func (c *AddonConfig) HasSecrets() bool {
+	return c != nil && len(c.Secrets) > 0
+}
+
+// ValidateValues checks that every value is of a field of the addon, and
+// that the values of rendered fields are valid. Empty values restore the
+// default of rendered fields.
+func (c *AddonConfig) ValidateValues(values map[string]string) error {
+	for k, v := range values {
+		f, ok := c.Field(k)
+		if !ok {
+			if c == nil {
+				return fmt.Errorf("the addon has no configuration fields")
+			}
+			return fmt.Errorf("%s is not a configuration field of the addon, valid fields are: %s", k, strings.Join(c.FieldNames(), ", "))
+		}
+		if f.Rendered && v != "" {
+			if err := f.parse(&v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Field returns the field named name
+func (c *AddonConfig) Field(name string) (ConfigField, bool) {
+	if c == nil {
+		return ConfigField{}, false
+	}
+	for _, f := range c.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return ConfigField{}, false
+}
+
+// ValueSource looks up the value of a field, such as from a flag or an
+// environment variable
+type ValueSource func(f ConfigField) (string, bool)
+
+// MapSource returns a ValueSource looking up fields in values
+func MapSource(values map[string]string) ValueSource {
+	return func(f ConfigField) (string, bool) {
+		v, ok := values[f.Name]
+		return v, ok
+	}
+}
+
+// EnvSource returns a ValueSource looking up fields in the environment
+func EnvSource(addon string) ValueSource {
+	return func(f ConfigField) (string, bool) {
+		return os.LookupEnv(f.EnvName(addon))
+	}
+}
+
+// ReadValuesFile reads key=value lines, ignoring empty lines and lines
+// starting with #
+func ReadValuesFile(r io.Reader) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+			return nil, fmt.Errorf("line %d: expected key=value", n)
+		}
+		values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return values, scanner.Err()
+}
+
+// Resolve returns the values of the fields the secrets of the addon are
+// created with, from the first source that has them. Fields no source has
+// are prompted for, if prompt is not nil, and have their default value
+// otherwise.
+func (c *AddonConfig) Resolve(sources []ValueSource, prompt func(f ConfigField) (string, error)) (map[string]string, error) {
+	values := map[string]string{}
+	for _, f := range c.Fields {
+		if f.Rendered {
+			continue
+		}
+		if f.When != "" {
+			if enabled, _ := strconv.ParseBool(values[f.When]); !enabled {
+				values[f.Name] = f.Default
+				continue
+			}
+		}
+		v, ok := lookup(sources, f)
+		if !ok && prompt != nil {
+			var err error
+			if v, err = prompt(f); err != nil {
+				return nil, errors.Wrapf(err, "prompting for %s", f.Name)
+			}
+			ok = v != ""
+		}
+		if !ok {
+			if !f.Optional && f.Default == "" {
+				return nil, fmt.Errorf("%s is required", f.Name)
+			}
+			values[f.Name] = f.Default
+			continue
+		}
+		if v == "" && f.Optional && f.Default != "" {
+			v = f.Default
+		}
+		if err := f.parse(&v); err != nil {
+			return nil, err
+		}
+		values[f.Name] = v
+	}
+	return values, nil
+}
+
+// ResolveRendered returns the values of the rendered fields that a source
+// has, checked and converted to the type of their field. Empty values are
+// kept, to restore the default.
+func (c *AddonConfig) ResolveRendered(sources []ValueSource) (map[string]string, error) {
+	values := map[string]string{}
+	for _, f := range c.RenderedFields() {
+		v, ok := lookup(sources, f)
+		if !ok {
+			continue
+		}
+		if v != "" {
+			if err := f.parse(&v); err != nil {
+				return nil, err
+			}
+		}
+		values[f.Name] = v
+	}
+	return values, nil
+}
+
+func lookup(sources []ValueSource, f ConfigField) (string, bool) {
+	for _, source := range sources {
+		if v, ok := source(f); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parse checks the value v of the field, and replaces the path of a file
+// field with the contents of the file
+func (f ConfigField) parse(v *string) error {
+	switch f.Type {
+	case FieldBool:
+		switch strings.ToLower(*v) {
+		case "yes", "y":
+			*v = "true"
+		case "no", "n":
+			*v = "false"
+		}
+		b, err := strconv.ParseBool(*v)
+		if err != nil {
+			return errors.Wrapf(err, "%s must be true or false (or yes or no)", f.Name)
+		}
+		*v = strconv.FormatBool(b)
+	case FieldInt:
+		i, err := strconv.Atoi(strings.TrimSpace(*v))
+		if err != nil {
+			return fmt.Errorf("%s must be a whole number, got %q", f.Name, *v)
+		}
+		if i < f.Min {
+			return fmt.Errorf("%s must be at least %d, got %d", f.Name, f.Min, i)
+		}
+		*v = strconv.Itoa(i)
+	case FieldFile:
+		if *v == "" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(*v)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", f.Name)
+		}
+		*v = string(data)
+	}
+	return nil
+}
+
+// typed returns the value v of the field as a value of its type, for the
+// manifests to be rendered with
+func (f ConfigField) typed(v string) (interface{}, error) {
+	if err := f.parse(&v); err != nil {
+		return nil, err
+	}
+	switch f.Type {
+	case FieldInt:
+		return strconv.Atoi(v)
+	case FieldBool:
+		return strconv.ParseBool(v)
+	}
+	return v, nil
+}
+
+// SecretsFor returns the secrets to create with the resolved values
+func (c *AddonConfig) SecretsFor(values map[string]string) []AddonSecret {
+	var secrets []AddonSecret
+	for _, spec := range c.Secrets {
+		data := map[string]string{}
+		for key, field := range spec.Data {
+			data[key] = values[field]
+		}
+		secrets = append(secrets, AddonSecret{
+			Namespace: spec.Namespace,
+			Name:      spec.Name,
+			Labels:    spec.Labels,
+			Data:      data,
+		})
+	}
+	return secrets
+}
+
+// FieldNames returns the sorted names of the fields
+func (c *AddonConfig) FieldNames() []string {
+	var names []string
+	for _, f := range c.Fields {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
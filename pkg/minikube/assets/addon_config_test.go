@@ -0,0 +1,164 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testConfig = &AddonConfig{
+	Fields: []ConfigField{
+		{Name: "enable-cloud", Type: FieldBool, Default: "false"},
+		{Name: "token", Type: FieldString, Secret: true, Default: "changeme", When: "enable-cloud"},
+		{Name: "url", Type: FieldString, Default: "https://example.com", Optional: true, When: "enable-cloud"},
+		{Name: "key-file", Type: FieldFile, Default: "changeme", When: "enable-cloud"},
+		{Name: "user", Type: FieldString},
+	},
+	Secrets: []SecretSpec{
+		{Namespace: "kube-system", Name: "cloud", Data: map[string]string{"TOKEN": "token", "URL": "url"}},
+	},
+}
+
+func TestResolveDefaultsWhenDisabled(t *testing.T) {
+	values, err := testConfig.Resolve([]ValueSource{MapSource(map[string]string{"user": "me", "token": "ignored"})}, nil)
+	if err != nil {
+		t.Fatalf("Error resolving values: %s", err)
+	}
+	expected := map[string]string{
+		"enable-cloud": "false",
+		"token":        "changeme",
+		"url":          "https://example.com",
+		"key-file":     "changeme",
+		"user":         "me",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Resolve() = %v, expected %v", values, expected)
+	}
+}
+
+func TestResolveSourcesAndPrompt(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	keyFile := filepath.Join(tempDir, "key.json")
+	ioutil.WriteFile(keyFile, []byte(`{"key": "value"}`), 0600)
+
+	flags := MapSource(map[string]string{"enable-cloud": "yes", "token": "from-flag"})
+	env := MapSource(map[string]string{"token": "from-env", "key-file": keyFile, "url": ""})
+	var prompted []string
+	prompt := func(f ConfigField) (string, error) {
+		prompted = append(prompted, f.Name)
+		return "prompted", nil
+	}
+	values, err := testConfig.Resolve([]ValueSource{flags, env}, prompt)
+	if err != nil {
+		t.Fatalf("Error resolving values: %s", err)
+	}
+	expected := map[string]string{
+		"enable-cloud": "true",
+		"token":        "from-flag",
+		"url":          "https://example.com",
+		"key-file":     `{"key": "value"}`,
+		"user":         "prompted",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Resolve() = %v, expected %v", values, expected)
+	}
+	if !reflect.DeepEqual(prompted, []string{"user"}) {
+		t.Errorf("Expected to only prompt for user, prompted for %v", prompted)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	var tests = []struct {
+		description string
+		values      map[string]string
+		prompt      func(f ConfigField) (string, error)
+	}{
+		{"missing required value", map[string]string{}, nil},
+		{"invalid bool", map[string]string{"enable-cloud": "maybe", "user": "me"}, nil},
+		{"missing file", map[string]string{"enable-cloud": "true", "key-file": "/does/not/exist", "user": "me"}, nil},
+		{"prompt error", map[string]string{}, func(f ConfigField) (string, error) { return "", fmt.Errorf("no terminal") }},
+	}
+	for _, test := range tests {
+		if _, err := testConfig.Resolve([]ValueSource{MapSource(test.values)}, test.prompt); err == nil {
+			t.Errorf("%s: expected an error", test.description)
+		}
+	}
+}
+
+func TestSecretsFor(t *testing.T) {
+	secrets := testConfig.SecretsFor(map[string]string{"token": "t", "url": "u", "user": "me"})
+	expected := []AddonSecret{
+		{Namespace: "kube-system", Name: "cloud", Data: map[string]string{"TOKEN": "t", "URL": "u"}},
+	}
+	if !reflect.DeepEqual(secrets, expected) {
+		t.Errorf("SecretsFor() = %v, expected %v", secrets, expected)
+	}
+}
+
+func TestEnvSource(t *testing.T) {
+	f := ConfigField{Name: "aws-region"}
+	if name := f.EnvName("registry-creds"); name != "MINIKUBE_ADDON_REGISTRY_CREDS_AWS_REGION" {
+		t.Errorf("Unexpected environment variable %s", name)
+	}
+	os.Setenv("MINIKUBE_ADDON_REGISTRY_CREDS_AWS_REGION", "us-east-1")
+	defer os.Unsetenv("MINIKUBE_ADDON_REGISTRY_CREDS_AWS_REGION")
+	if v, ok := EnvSource("registry-creds")(f); !ok || v != "us-east-1" {
+		t.Errorf("EnvSource() = %q, %v", v, ok)
+	}
+}
+
+func TestReadValuesFile(t *testing.T) {
+	values, err := ReadValuesFile(strings.NewReader("# registry-creds\nenable-gcr = true\n\ngcr-url=https://asia.gcr.io\n"))
+	if err != nil {
+		t.Fatalf("Error reading values: %s", err)
+	}
+	expected := map[string]string{"enable-gcr": "true", "gcr-url": "https://asia.gcr.io"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("ReadValuesFile() = %v, expected %v", values, expected)
+	}
+	if _, err := ReadValuesFile(strings.NewReader("enable-gcr\n")); err == nil {
+		t.Errorf("Expected an error reading a line without a value")
+	}
+}
+
+func TestRegistryCredsSecrets(t *testing.T) {
+	c := Addons["registry-creds"].Config()
+	values, err := c.Resolve(nil, nil)
+	if err != nil {
+		t.Fatalf("Error resolving defaults: %s", err)
+	}
+	for _, secret := range c.SecretsFor(values) {
+		if len(secret.Data) == 0 || secret.Labels["kubernetes.io/minikube-addons"] != "registry-creds" {
+			t.Errorf("Unexpected secret %v", secret)
+		}
+		for key, value := range secret.Data {
+			if value == "" && key != "AWS_SESSION_TOKEN" && key != "aws-assume-role" {
+				t.Errorf("Secret %s has an empty %s", secret.Name, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// AddonValues is the config key holding the values of the rendered fields
+// set with minikube addons configure, by addon and field
+const AddonValues = "addon-values"
+
+var addonTemplateFuncs = template.FuncMap{
+	"image": withRegistry,
+}
+
+// withRegistry replaces the registry host of image with registry, if set
+func withRegistry(registry, image string) string {
+	if registry == "" {
+		return image
+	}
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		image = parts[1]
+	}
+	return strings.TrimSuffix(registry, "/") + "/" + image
+}
+
+// ConfiguredValues returns the values of the addon set in the minikube config
+func (a *Addon) ConfiguredValues() (map[string]string, error) {
+	m, err := config.ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return GetAddonValues(m, a.addonName), nil
+}
+
+// Values returns the values the addon manifests are rendered with: the
+// configured values, and the defaults of the other rendered fields, as
+// values of the type of their field
+func (a *Addon) Values() (map[string]interface{}, error) {
+	configured, err := a.ConfiguredValues()
+	if err != nil {
+		return nil, err
+	}
+	return a.typedValues(configured)
+}
+
+// DefaultValues returns the default values the addon manifests are rendered
+// with, regardless of the minikube config
+func (a *Addon) DefaultValues() (map[string]interface{}, error) {
+	return a.typedValues(nil)
+}
+
+func (a *Addon) typedValues(configured map[string]string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, f := range a.Config().RenderedFields() {
+		v := f.Default
+		if c, ok := configured[f.Name]; ok {
+			v = c
+		}
+		var err error
+		if values[f.Name], err = f.typed(v); err != nil {
+			return nil, errors.Wrapf(err, "invalid value of %s", f.Name)
+		}
+	}
+	return values, nil
+}
+
+// GetAddonValues returns the values of addon set in the config m
+func GetAddonValues(m config.MinikubeConfig, addon string) map[string]string {
+	values := map[string]string{}
+	all, _ := m[AddonValues].(map[string]interface{})
+	addonValues, _ := all[addon].(map[string]interface{})
+	for k, v := range addonValues {
+		values[k] = fmt.Sprintf("%v", v)
+	}
+	return values
+}
+
+// SetAddonValues sets the values of addon in the config m. Empty values
+// are unset, so that the default is used again.
+func SetAddonValues(m config.MinikubeConfig, addon string, values map[string]string) {
+	all, _ := m[AddonValues].(map[string]interface{})
+	if all == nil {
+		all = map[string]interface{}{}
+	}
+	addonValues, _ := all[addon].(map[string]interface{})
+	if addonValues == nil {
+		addonValues = map[string]interface{}{}
+	}
+	for k, v := range values {
+		if v == "" {
+			delete(addonValues, k)
+		} else {
+			addonValues[k] = v
+		}
+	}
+	if len(addonValues) == 0 {
+		delete(all, addon)
+	} else {
+		all[addon] = addonValues
+	}
+	if len(all) == 0 {
+		delete(m, AddonValues)
+	} else {
+		m[AddonValues] = all
+	}
+}
+
+// RenderedAssets returns the manifests of the addon rendered with its
+// values. The manifests of user addons are not templates, and are returned
+// as they are.
+func (a *Addon) RenderedAssets() ([]CopyableFile, error) {
+	values, err := a.Values()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting values of addon %s", a.addonName)
+	}
+	return a.RenderAssets(values)
+}
+
+// RenderAssets returns the manifests of the addon rendered with values
+func (a *Addon) RenderAssets(values map[string]interface{}) ([]CopyableFile, error) {
+	var files []CopyableFile
+	for _, asset := range a.Assets {
+		data := asset.data
+		if !a.IsUserAddon() {
+			var err error
+			if data, err = renderAddonAsset(asset, values); err != nil {
+				return nil, err
+			}
+		}
+		m := NewMemoryAsset(data, asset.TargetDir, asset.TargetName, asset.Permissions)
+		m.AssetName = asset.AssetName
+		files = append(files, m)
+	}
+	return files, nil
+}
+
+func renderAddonAsset(asset *BinDataAsset, values map[string]interface{}) ([]byte, error) {
+	tmpl, err := template.New(asset.AssetName).Funcs(addonTemplateFuncs).Option("missingkey=error").Parse(string(asset.data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing addon manifest %s", asset.AssetName)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, values); err != nil {
+		return nil, errors.Wrapf(err, "rendering addon manifest %s", asset.AssetName)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// withConfig points the minikube config at a file holding m for the test
+func withConfig(t *testing.T, m config.MinikubeConfig) func() {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	oldConfigFile := constants.ConfigFile
+	constants.ConfigFile = filepath.Join(tempDir, "config.json")
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Error encoding config: %s", err)
+	}
+	if err := ioutil.WriteFile(constants.ConfigFile, data, 0644); err != nil {
+		t.Fatalf("Error writing config: %s", err)
+	}
+	return func() {
+		constants.ConfigFile = oldConfigFile
+		os.RemoveAll(tempDir)
+	}
+}
+
+// loadManifests reads the addon manifests from the source tree, as the
+// bindata of the test binary may not include them
+func loadManifests(t *testing.T, a *Addon) *Addon {
+	var loaded []*BinDataAsset
+	for _, asset := range a.Assets {
+		data, err := ioutil.ReadFile(filepath.Join("../../..", asset.AssetName))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", asset.AssetName, err)
+		}
+		copy := *asset
+		copy.data = data
+		loaded = append(loaded, &copy)
+	}
+	return NewAddon(loaded, a.enabled, a.addonName)
+}
+
+func renderedData(t *testing.T, a *Addon) string {
+	files, err := a.RenderedAssets()
+	if err != nil {
+		t.Fatalf("Error rendering addon %s: %s", a.addonName, err)
+	}
+	var b bytes.Buffer
+	for _, f := range files {
+		b.Write(f.(*MemoryAsset).data)
+	}
+	return b.String()
+}
+
+func TestRenderBundledAddonsWithDefaults(t *testing.T) {
+	defer withConfig(t, config.MinikubeConfig{})()
+	for name, bundled := range Addons {
+		if bundled.IsUserAddon() {
+			continue
+		}
+		a := loadManifests(t, bundled)
+		data := renderedData(t, a)
+		if strings.Contains(data, "{{") {
+			t.Errorf("%s was not fully rendered:\n%s", name, data)
+		}
+		for _, f := range a.Config().RenderedFields() {
+			if strings.HasSuffix(f.Name, "Image") || f.Name == "image" {
+				if !strings.Contains(data, "image: "+f.Default) {
+					t.Errorf("%s does not use the default image %s", name, f.Default)
+				}
+			}
+		}
+	}
+}
+
+func TestRenderAddonWithValues(t *testing.T) {
+	defer withConfig(t, config.MinikubeConfig{
+		AddonValues: map[string]interface{}{
+			"efk": map[string]interface{}{
+				"registry":    "mirror.example.com:5000",
+				"kibanaImage": "kibana:6.0.0",
+			},
+		},
+	})()
+	data := renderedData(t, loadManifests(t, Addons["efk"]))
+	for _, image := range []string{
+		"mirror.example.com:5000/google-containers/elasticsearch:v5.6.2",
+		"mirror.example.com:5000/library/alpine:3.6",
+		"mirror.example.com:5000/kibana:6.0.0",
+	} {
+		if !strings.Contains(data, "image: "+image) {
+			t.Errorf("Expected image %s in rendered manifests:\n%s", image, data)
+		}
+	}
+}
+
+func TestWithRegistry(t *testing.T) {
+	var tests = []struct {
+		registry string
+		image    string
+		expected string
+	}{
+		{"", "k8s.gcr.io/pause:3.0", "k8s.gcr.io/pause:3.0"},
+		{"mirror", "k8s.gcr.io/pause:3.0", "mirror/pause:3.0"},
+		{"mirror/", "localhost/pause:3.0", "mirror/pause:3.0"},
+		{"mirror", "localhost:5000/team/pause:3.0", "mirror/team/pause:3.0"},
+		{"mirror", "library/alpine:3.6", "mirror/library/alpine:3.6"},
+		{"mirror", "alpine:3.6", "mirror/alpine:3.6"},
+	}
+	for _, test := range tests {
+		if actual := withRegistry(test.registry, test.image); actual != test.expected {
+			t.Errorf("withRegistry(%q, %q) = %q, expected %q", test.registry, test.image, actual, test.expected)
+		}
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	c := Addons["dashboard"].Config()
+	if err := c.ValidateValues(map[string]string{"replicas": "2", "registry": "mirror"}); err != nil {
+		t.Errorf("Unexpected error validating values: %s", err)
+	}
+	if err := c.ValidateValues(map[string]string{"replicas": ""}); err != nil {
+		t.Errorf("Unexpected error validating an empty value: %s", err)
+	}
+	for _, values := range []map[string]string{
+		{"replica": "2"},
+		{"replicas": "two"},
+		{"replicas": "0"},
+	} {
+		if err := c.ValidateValues(values); err == nil {
+			t.Errorf("Expected an error validating %v", values)
+		}
+	}
+	if err := Addons["default-storageclass"].Config().ValidateValues(map[string]string{"image": "x"}); err == nil {
+		t.Errorf("Expected an error validating a value of an addon without fields")
+	}
+	if err := Addons["registry-creds"].Config().ValidateValues(map[string]string{"aws-region": "us-east-1", "image": "x"}); err != nil {
+		t.Errorf("Unexpected error validating registry-creds values: %s", err)
+	}
+}
+
+func TestValuesAreTyped(t *testing.T) {
+	defer withConfig(t, config.MinikubeConfig{
+		AddonValues: map[string]interface{}{
+			"registry": map[string]interface{}{"replicas": "3"},
+		},
+	})()
+	values, err := Addons["registry"].Values()
+	if err != nil {
+		t.Fatalf("Error getting values: %s", err)
+	}
+	if values["replicas"] != 3 || values["registry"] != "" {
+		t.Errorf("Unexpected values %v", values)
+	}
+}
+
+func TestResolveRendered(t *testing.T) {
+	c := Addons["registry"].Config()
+	values, err := c.ResolveRendered([]ValueSource{
+		MapSource(map[string]string{"replicas": " 2"}),
+		MapSource(map[string]string{"replicas": "5", "registry": ""}),
+	})
+	if err != nil {
+		t.Fatalf("Error resolving values: %s", err)
+	}
+	if len(values) != 2 || values["replicas"] != "2" || values["registry"] != "" {
+		t.Errorf("Unexpected values %v", values)
+	}
+	if _, err := c.ResolveRendered([]ValueSource{MapSource(map[string]string{"replicas": "-1"})}); err == nil {
+		t.Errorf("Expected an error resolving negative replicas")
+	}
+}
+
+func TestSetAddonValues(t *testing.T) {
+	m := config.MinikubeConfig{}
+	SetAddonValues(m, "registry", map[string]string{"replicas": "3", "registry": "mirror"})
+	SetAddonValues(m, "registry", map[string]string{"registry": ""})
+	values := GetAddonValues(m, "registry")
+	if len(values) != 1 || values["replicas"] != "3" {
+		t.Errorf("Unexpected values %v", values)
+	}
+	SetAddonValues(m, "registry", map[string]string{"replicas": ""})
+	if _, ok := m[AddonValues]; ok {
+		t.Errorf("Expected empty addon values to be removed from the config: %v", m)
+	}
+}
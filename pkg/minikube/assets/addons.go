@@ -21,7 +21,9 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -32,6 +34,8 @@ type Addon struct {
 	Assets    []*BinDataAsset
 	enabled   bool
 	addonName string
+	// source is where a user addon was installed from, empty for bundled addons
+	source string
 }
 
 func NewAddon(assets []*BinDataAsset, enabled bool, addonName string) *Addon {
@@ -55,6 +59,34 @@ func (a *Addon) IsEnabled() (bool, error) {
 	return a.enabled, nil
 }
 
+// Images returns the container images referenced by the addon manifests,
+// rendered with the values of the addon.
+func (a *Addon) Images() []string {
+	files, err := a.RenderedAssets()
+	if err != nil {
+		glog.Warningf("Error rendering addon %s: %s", a.addonName, err)
+		return nil
+	}
+	var images []string
+	for _, f := range files {
+		m, ok := f.(*MemoryAsset)
+		if !ok {
+			continue
+		}
+		for _, line := range strings.Split(string(m.data), "\n") {
+			line = strings.TrimPrefix(strings.TrimSpace(line), "- ")
+			if !strings.HasPrefix(line, "image:") {
+				continue
+			}
+			image := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "image:")), `"'`)
+			if image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
+
 var Addons = map[string]*Addon{
 	"addon-manager": NewAddon([]*BinDataAsset{
 		NewBinDataAsset(
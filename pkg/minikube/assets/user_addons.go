@@ -0,0 +1,329 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+// BundledAddonSource is the source of the addons compiled into minikube
+const BundledAddonSource = "minikube"
+
+const (
+	userAddonMetadataFile = "addon.json"
+	userAddonManifestsDir = "manifests"
+)
+
+var validAddonName = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// ReservedAddonNames can not be used by user addons. They are the names of
+// the minikube config settings, which minikube addons enable would set
+// instead of enabling the addon, and are registered by the config command.
+var ReservedAddonNames = map[string]bool{}
+
+// UserAddon describes an addon installed with minikube addons install
+type UserAddon struct {
+	Name      string    `json:"name"`
+	Source    string    `json:"source"`
+	Installed time.Time `json:"installed"`
+	Manifests []string  `json:"manifests"`
+}
+
+// LoadUserAddons registers the addons installed in dir in Addons
+func LoadUserAddons(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "reading user addons")
+	}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		addon, err := loadUserAddon(filepath.Join(dir, e.Name()))
+		if err != nil {
+			glog.Warningf("Skipping user addon %s: %s", e.Name(), err)
+			continue
+		}
+		if existing, ok := Addons[e.Name()]; ok && existing.source == "" {
+			glog.Warningf("Skipping user addon %s, which has the name of a bundled addon", e.Name())
+			continue
+		}
+		Addons[e.Name()] = addon
+	}
+	return nil
+}
+
+func loadUserAddon(dir string) (*Addon, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, userAddonMetadataFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading addon metadata")
+	}
+	var ua UserAddon
+	if err := json.Unmarshal(b, &ua); err != nil {
+		return nil, errors.Wrap(err, "parsing addon metadata")
+	}
+	var assets []*BinDataAsset
+	for _, m := range ua.Manifests {
+		a, err := newUserAddonAsset(filepath.Join(dir, userAddonManifestsDir, m), ua.Name+"-"+m)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, a)
+	}
+	addon := NewAddon(assets, false, ua.Name)
+	addon.source = ua.Source
+	return addon, nil
+}
+
+// newUserAddonAsset returns the manifest at path, copied to the addons
+// directory of the VM as targetName. Like the bundled manifests, it is held
+// in memory.
+func newUserAddonAsset(path, targetName string) (*BinDataAsset, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading addon manifest %s", path)
+	}
+	m := &BinDataAsset{
+		BaseAsset{
+			AssetName:   path,
+			TargetDir:   constants.AddonsPath,
+			TargetName:  targetName,
+			Permissions: "0640",
+		},
+	}
+	m.data = data
+	m.Length = len(data)
+	m.reader = strings.NewReader(string(data))
+	return m, nil
+}
+
+// InstallUserAddon installs the manifests of from, a local directory or file
+// or an http(s) URL of a manifest or a .tar.gz of manifests, as the addon
+// name in dir, and registers it in Addons. An installed user addon of the
+// same name is replaced.
+func InstallUserAddon(dir, name, from string) (*Addon, error) {
+	if !validAddonName.MatchString(name) {
+		return nil, errors.Errorf("invalid addon name %q, must consist of lower case letters, digits and '-'", name)
+	}
+	if existing, ok := Addons[name]; ok && existing.source == "" {
+		return nil, errors.Errorf("%s is a bundled addon", name)
+	}
+	if ReservedAddonNames[name] {
+		return nil, errors.Errorf("%s is the name of a minikube config setting", name)
+	}
+
+	tmp := filepath.Join(dir, "."+name+".tmp")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	manifests := filepath.Join(tmp, userAddonManifestsDir)
+	if err := os.MkdirAll(manifests, 0755); err != nil {
+		return nil, errors.Wrap(err, "making addon directory")
+	}
+
+	var err error
+	if u, perr := url.Parse(from); perr == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		err = fetchManifests(u, manifests)
+	} else {
+		err = copyManifests(from, manifests)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := ioutil.ReadDir(manifests)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading addon manifests")
+	}
+	ua := UserAddon{Name: name, Source: from, Installed: time.Now().UTC()}
+	for _, f := range files {
+		ua.Manifests = append(ua.Manifests, f.Name())
+	}
+	if len(ua.Manifests) == 0 {
+		return nil, errors.Errorf("no manifests (*.yaml, *.yml, *.json) found in %s", from)
+	}
+	b, err := json.MarshalIndent(ua, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding addon metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmp, userAddonMetadataFile), b, 0644); err != nil {
+		return nil, errors.Wrap(err, "writing addon metadata")
+	}
+
+	target := filepath.Join(dir, name)
+	if err := os.RemoveAll(target); err != nil {
+		return nil, errors.Wrapf(err, "removing previous install of %s", name)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return nil, errors.Wrap(err, "installing addon")
+	}
+	addon, err := loadUserAddon(target)
+	if err != nil {
+		return nil, err
+	}
+	Addons[name] = addon
+	return addon, nil
+}
+
+// UninstallUserAddon removes the user addon name from dir and from Addons
+func UninstallUserAddon(dir, name string) error {
+	addon, ok := Addons[name]
+	if !ok {
+		return errors.Errorf("%s is not an installed addon", name)
+	}
+	if addon.source == "" {
+		return errors.Errorf("%s is a bundled addon and can not be uninstalled", name)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, name)); err != nil {
+		return errors.Wrapf(err, "removing addon %s", name)
+	}
+	delete(Addons, name)
+	return nil
+}
+
+func isManifest(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// copyManifests copies the manifests of the directory or file src to dst
+func copyManifests(src, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return errors.Wrapf(err, "reading addon source %s", src)
+	}
+	var paths []string
+	if fi.IsDir() {
+		entries, err := ioutil.ReadDir(src)
+		if err != nil {
+			return errors.Wrapf(err, "reading addon source %s", src)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && isManifest(e.Name()) {
+				paths = append(paths, filepath.Join(src, e.Name()))
+			}
+		}
+	} else {
+		paths = []string{src}
+	}
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", p)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dst, filepath.Base(p)), b, 0644); err != nil {
+			return errors.Wrapf(err, "copying %s", p)
+		}
+	}
+	return nil
+}
+
+// fetchManifests downloads the manifest, or .tar.gz of manifests, at u to dst
+func fetchManifests(u *url.URL, dst string) error {
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s", u)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("downloading %s: %s", u, resp.Status)
+	}
+
+	if strings.HasSuffix(u.Path, ".tar.gz") || strings.HasSuffix(u.Path, ".tgz") {
+		return extractManifests(resp.Body, dst)
+	}
+	name := path.Base(u.Path)
+	if !isManifest(name) {
+		name = "manifest.yaml"
+	}
+	return writeManifest(resp.Body, filepath.Join(dst, name))
+}
+
+// extractManifests writes the manifests of a .tar.gz to dst. Manifests in
+// subdirectories are flattened, prefixed with their directory.
+func extractManifests(r io.Reader, dst string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "reading addon archive")
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "reading addon archive")
+		}
+		if hdr.Typeflag != tar.TypeReg || !isManifest(hdr.Name) {
+			continue
+		}
+		name := strings.Replace(strings.Trim(path.Clean(hdr.Name), "/"), "/", "-", -1)
+		if err := writeManifest(tr, filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeManifest(r io.Reader, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return errors.Wrap(err, "writing addon manifest")
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return errors.Wrap(err, "writing addon manifest")
+	}
+	return f.Close()
+}
+
+// Source returns where the addon came from: minikube for the bundled addons,
+// the directory or URL it was installed from for user addons
+func (a *Addon) Source() string {
+	if a.source == "" {
+		return BundledAddonSource
+	}
+	return a.source
+}
+
+// IsUserAddon returns whether the addon was installed with minikube addons install
+func (a *Addon) IsUserAddon() bool {
+	return a.source != ""
+}
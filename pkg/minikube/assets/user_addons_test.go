@@ -0,0 +1,189 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+const testManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: team-config
+  labels:
+    addonmanager.kubernetes.io/mode: Reconcile
+`
+
+func targetNames(a *Addon) []string {
+	var names []string
+	for _, asset := range a.Assets {
+		names = append(names, asset.GetTargetName())
+	}
+	return names
+}
+
+func TestInstallUserAddonFromDir(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	src := filepath.Join(tempDir, "src")
+	os.MkdirAll(filepath.Join(src, "nested"), 0755)
+	for _, f := range []string{"a.yaml", "b.json", "README.md", "nested/c.yaml"} {
+		ioutil.WriteFile(filepath.Join(src, f), []byte(testManifest), 0644)
+	}
+	dir := filepath.Join(tempDir, "user-addons")
+	defer delete(Addons, "team")
+
+	addon, err := InstallUserAddon(dir, "team", src)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := []string{"team-a.yaml", "team-b.json"}; !reflect.DeepEqual(targetNames(addon), expected) {
+		t.Errorf("Target names = %v, expected %v", targetNames(addon), expected)
+	}
+	for _, asset := range addon.Assets {
+		if asset.GetTargetDir() != constants.AddonsPath {
+			t.Errorf("%s is copied to %s, expected %s", asset.GetTargetName(), asset.GetTargetDir(), constants.AddonsPath)
+		}
+	}
+	if Addons["team"] != addon || !addon.IsUserAddon() || addon.Source() != src {
+		t.Errorf("Expected team to be registered as a user addon from %s", src)
+	}
+
+	// a new process finds the installed addon
+	delete(Addons, "team")
+	if err := LoadUserAddons(dir); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	loaded, ok := Addons["team"]
+	if !ok {
+		t.Fatalf("Expected team to be loaded")
+	}
+	if loaded.Source() != src || !reflect.DeepEqual(targetNames(loaded), targetNames(addon)) {
+		t.Errorf("Loaded %v from %s, expected %v from %s", targetNames(loaded), loaded.Source(), targetNames(addon), src)
+	}
+
+	if err := UninstallUserAddon(dir, "team"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, ok := Addons["team"]; ok {
+		t.Errorf("Expected team to be unregistered")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "team")); !os.IsNotExist(err) {
+		t.Errorf("Expected team to be removed, got %v", err)
+	}
+}
+
+func TestInstallUserAddonFromURL(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for _, name := range []string{"team/deploy.yaml", "team/svc.yml", "team/LICENSE"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(testManifest)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(testManifest))
+	}
+	tw.Close()
+	gz.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/team.tar.gz":
+			w.Write(archive.Bytes())
+		case "/team.yaml":
+			w.Write([]byte(testManifest))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	defer delete(Addons, "team")
+
+	var tests = []struct {
+		from      string
+		expected  []string
+		shouldErr bool
+	}{
+		{from: server.URL + "/team.yaml", expected: []string{"team-team.yaml"}},
+		{from: server.URL + "/team.tar.gz", expected: []string{"team-team-deploy.yaml", "team-team-svc.yml"}},
+		{from: server.URL + "/missing.yaml", shouldErr: true},
+	}
+	for _, test := range tests {
+		addon, err := InstallUserAddon(tempDir, "team", test.from)
+		if err != nil {
+			if !test.shouldErr {
+				t.Errorf("%s: unexpected error: %s", test.from, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Errorf("%s: expected an error", test.from)
+			continue
+		}
+		if !reflect.DeepEqual(targetNames(addon), test.expected) {
+			t.Errorf("%s: target names = %v, expected %v", test.from, targetNames(addon), test.expected)
+		}
+		if images := addon.Images(); len(images) != 0 {
+			t.Errorf("%s: unexpected images %v", test.from, images)
+		}
+	}
+}
+
+func TestInstallUserAddonErrors(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error creating tempdir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	empty := filepath.Join(tempDir, "empty")
+	os.MkdirAll(empty, 0755)
+	ReservedAddonNames["memory"] = true
+	defer delete(ReservedAddonNames, "memory")
+
+	for _, test := range []struct{ name, from string }{
+		{"dashboard", empty},
+		{"memory", empty},
+		{"Team_Addon", empty},
+		{"team", empty},
+		{"team", filepath.Join(tempDir, "missing")},
+	} {
+		if _, err := InstallUserAddon(tempDir, test.name, test.from); err == nil {
+			t.Errorf("Expected an error installing %s from %s", test.name, test.from)
+		}
+	}
+	if err := UninstallUserAddon(tempDir, "dashboard"); err == nil {
+		t.Errorf("Expected an error uninstalling a bundled addon")
+	}
+}
@@ -18,6 +18,8 @@ package assets
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"os"
 	"path"
@@ -60,6 +62,35 @@ func (b *BaseAsset) GetPermissions() string {
 	return b.Permissions
 }
 
+// Seek sets the offset of the next Read, so that the asset can be read again
+func (b *BaseAsset) Seek(offset int64, whence int) (int64, error) {
+	s, ok := b.reader.(io.Seeker)
+	if !ok {
+		return 0, errors.Errorf("asset %s can not be read again", b.AssetName)
+	}
+	return s.Seek(offset, whence)
+}
+
+// Checksum returns the sha256 of the contents of f, and rewinds f so that
+// it can be copied afterwards
+func Checksum(f CopyableFile) (string, error) {
+	s, ok := f.(io.Seeker)
+	if !ok {
+		return "", errors.Errorf("asset %s can not be read again", f.GetAssetName())
+	}
+	if _, err := s.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "reading %s", f.GetAssetName())
+	}
+	if _, err := s.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 type FileAsset struct {
 	BaseAsset
 }
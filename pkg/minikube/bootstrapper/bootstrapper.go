@@ -20,6 +20,7 @@ import (
 	"io"
 	"net"
 
+	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/util"
 )
@@ -29,9 +30,12 @@ type Bootstrapper interface {
 	StartCluster(KubernetesConfig) error
 	UpdateCluster(KubernetesConfig) error
 	RestartCluster(KubernetesConfig) error
-	GetClusterLogsTo(follow bool, out io.Writer) error
+	GetClusterLogsTo(opts LogOptions, out io.Writer) error
 	SetupCerts(cfg KubernetesConfig) error
-	GetClusterStatus() (string, error)
+	// RotateCerts reissues the cluster certificates, and the CAs too if
+	// rotateCA is set, and restarts the control plane to use them
+	RotateCerts(cfg KubernetesConfig, rotateCA bool) error
+	GetClusterStatus() (ClusterStatus, error)
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -49,6 +53,11 @@ type KubernetesConfig struct {
 	ServiceCIDR       string
 	ExtraOptions      util.ExtraOptionSlice
 
+	// CACertFile and CAKeyFile are the CA signing the cluster certs. A
+	// minikubeCA is generated when they are empty.
+	CACertFile string
+	CAKeyFile  string
+
 	ShouldLoadCachedImages bool
 }
 
@@ -57,13 +66,28 @@ const (
 	BootstrapperTypeKubeadm   = "kubeadm"
 )
 
+// defaultAddons are the addons enabled by default, whose images are cached
+// along with the images of the bootstrapper. kubeadm deploys its own DNS.
+var defaultAddons = map[string][]string{
+	BootstrapperTypeLocalkube: {"addon-manager", "dashboard", "kube-dns", "storage-provisioner"},
+	BootstrapperTypeKubeadm:   {"addon-manager", "dashboard", "storage-provisioner"},
+}
+
+// GetCachedImageList returns the images to cache for the bootstrapper: the
+// images it runs, and the images of the default addons, rendered with their
+// configured values such as a registry mirror.
 func GetCachedImageList(version string, bootstrapper string) []string {
+	var images []string
 	switch bootstrapper {
 	case BootstrapperTypeLocalkube:
-		return constants.LocalkubeCachedImages
+		images = append(images, constants.LocalkubeCachedImages...)
 	case BootstrapperTypeKubeadm:
-		return constants.GetKubeadmCachedImages(version)
+		images = append(images, constants.GetKubeadmCachedImages(version)...)
 	default:
 		return []string{}
 	}
+	for _, name := range defaultAddons[bootstrapper] {
+		images = append(images, assets.Addons[name].Images()...)
+	}
+	return images
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"io/ioutil"
 	"net"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
@@ -37,18 +39,38 @@ var (
 	certs = []string{
 		"ca.crt", "ca.key", "apiserver.crt", "apiserver.key", "proxy-client-ca.crt",
 		"proxy-client-ca.key", "proxy-client.crt", "proxy-client.key",
+		"client.crt", "client.key",
 	}
+
+	// caCerts and leafCerts are the base names of the certificates and
+	// keys generated in the minikube directory
+	caCerts   = []string{"ca", "proxy-client-ca"}
+	leafCerts = []string{"client", "apiserver", "proxy-client"}
+
+	// checkedCerts are the patterns, relative to the minikube directory, of
+	// the certificates CheckCerts reports on. The docker-machine ones secure
+	// the docker daemon of the VM.
+	checkedCerts = []string{"*.crt", "certs/*.pem", "machines/*/server.pem"}
 )
 
+// CertBackupSuffix is appended to the certs moved aside while they are
+// rotated, until the new ones are in place
+const CertBackupSuffix = ".bak"
+
 // SetupCerts gets the generated credentials required to talk to the APIServer.
 func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
-	localPath := constants.GetMinipath()
 	glog.Infof("Setting up certificates for IP: %s\n", k8s.NodeIP)
 
 	if err := generateCerts(k8s); err != nil {
 		return errors.Wrap(err, "Error generating certs")
 	}
+	return copyCerts(cmd, k8s)
+}
 
+// copyCerts copies the certs of the minikube directory to the VM, along with
+// the kubeconfig using them
+func copyCerts(cmd CommandRunner, k8s KubernetesConfig) error {
+	localPath := constants.GetMinipath()
 	copyableFiles := []assets.CopyableFile{}
 
 	for _, cert := range certs {
@@ -84,14 +106,93 @@ func SetupCerts(cmd CommandRunner, k8s KubernetesConfig) error {
 		util.DefaultLocalkubeDirectory, "kubeconfig", "0644")
 	copyableFiles = append(copyableFiles, kubeCfgFile)
 
-	for _, f := range copyableFiles {
-		if err := cmd.Copy(f); err != nil {
-			return err
+	return cmd.CopyAll(copyableFiles)
+}
+
+// RotateCerts reissues the leaf certificates with new keys, along with the CAs
+// signing them if rotateCA is set, and copies them to the VM. reissue, if not
+// nil, is then called to reissue the certs the bootstrapper generates in the
+// VM. The previous certs are moved aside until then, and put back in place on
+// both sides if any step fails. The control plane has to be restarted to use
+// the new certs.
+func RotateCerts(cmd CommandRunner, k8s KubernetesConfig, rotateCA bool, reissue func() error) error {
+	names := leafCerts
+	if rotateCA {
+		names = append(append([]string{}, caCerts...), leafCerts...)
+	}
+	localPath := constants.GetMinipath()
+	backups := []string{}
+	for _, name := range names {
+		for _, ext := range []string{".crt", ".key"} {
+			p := filepath.Join(localPath, name+ext)
+			if err := os.Rename(p, p+CertBackupSuffix); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				if rerr := restoreCerts(cmd, k8s, backups); rerr != nil {
+					glog.Errorf("Error restoring certs: %v", rerr)
+				}
+				return errors.Wrapf(err, "backing up %s", p)
+			}
+			backups = append(backups, p)
+		}
+	}
+
+	err := SetupCerts(cmd, k8s)
+	if err == nil && reissue != nil {
+		err = reissue()
+	}
+	if err != nil {
+		if rerr := restoreCerts(cmd, k8s, backups); rerr != nil {
+			return errors.Wrapf(rerr, "restoring the previous certs after: %v", err)
+		}
+		return err
+	}
+	for _, p := range backups {
+		if err := os.Remove(p + CertBackupSuffix); err != nil {
+			glog.Warningf("Error removing %s: %v", p+CertBackupSuffix, err)
 		}
 	}
 	return nil
 }
 
+// restoreCerts puts the backed up certs at paths back in place, and copies
+// them to the VM again
+func restoreCerts(cmd CommandRunner, k8s KubernetesConfig, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	for _, p := range paths {
+		if err := os.Rename(p+CertBackupSuffix, p); err != nil {
+			return errors.Wrapf(err, "restoring %s", p)
+		}
+	}
+	return copyCerts(cmd, k8s)
+}
+
+// CheckCerts returns the certificates found in the minikube directory dir,
+// ordered by path
+func CheckCerts(dir string) ([]*util.CertInfo, error) {
+	var infos []*util.CertInfo
+	for _, pattern := range checkedCerts {
+		paths, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing %s", pattern)
+		}
+		for _, p := range paths {
+			if strings.HasSuffix(p, "key.pem") {
+				continue
+			}
+			info, err := util.ReadCertInfo(p)
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, info)
+		}
+	}
+	return infos, nil
+}
+
 func generateCerts(k8s KubernetesConfig) error {
 	serviceIP, err := util.GetServiceClusterIP(k8s.ServiceCIDR)
 	if err != nil {
@@ -169,6 +270,12 @@ func generateCerts(k8s KubernetesConfig) error {
 		},
 	}
 
+	if k8s.CACertFile != "" {
+		if err := importCA(k8s.CACertFile, k8s.CAKeyFile, caCertPath, caKeyPath); err != nil {
+			return errors.Wrap(err, "Error importing CA certificate")
+		}
+	}
+
 	for _, caCertSpec := range caCertSpecs {
 		if !(util.CanReadFile(caCertSpec.certPath) &&
 			util.CanReadFile(caCertSpec.keyPath)) {
@@ -192,3 +299,32 @@ func generateCerts(k8s KubernetesConfig) error {
 
 	return nil
 }
+
+// importCA validates the CA certFile and keyFile and copies them to certPath
+// and keyPath, replacing the generated CA
+func importCA(certFile, keyFile, certPath, keyPath string) error {
+	if err := util.ValidateCA(certFile, keyFile); err != nil {
+		return err
+	}
+	files := []struct {
+		src  string
+		dst  string
+		perm os.FileMode
+	}{
+		{src: certFile, dst: certPath, perm: 0644},
+		{src: keyFile, dst: keyPath, perm: 0600},
+	}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f.src)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", f.src)
+		}
+		if err := os.MkdirAll(filepath.Dir(f.dst), 0755); err != nil {
+			return errors.Wrapf(err, "creating directory for %s", f.dst)
+		}
+		if err := ioutil.WriteFile(f.dst, data, f.perm); err != nil {
+			return errors.Wrapf(err, "writing %s", f.dst)
+		}
+	}
+	return nil
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"k8s.io/minikube/pkg/minikube/constants"
@@ -52,3 +55,162 @@ func TestSetupCerts(t *testing.T) {
 		}
 	}
 }
+
+func TestRotateCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	f := NewFakeCommandRunner()
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	if err := SetupCerts(f, k8s); err != nil {
+		t.Fatalf("Error setting up certs: %s", err)
+	}
+
+	read := func(name string) string {
+		contents, err := ioutil.ReadFile(filepath.Join(tempDir, name))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", name, err)
+		}
+		return string(contents)
+	}
+	caCert, caKey, apiserverKey := read("ca.crt"), read("ca.key"), read("apiserver.key")
+
+	if err := RotateCerts(f, k8s, false, nil); err != nil {
+		t.Fatalf("Error rotating certs: %s", err)
+	}
+	if read("ca.crt") != caCert || read("ca.key") != caKey {
+		t.Errorf("CA changed without rotating it")
+	}
+	if read("apiserver.key") == apiserverKey {
+		t.Errorf("apiserver key was not reissued")
+	}
+	copied, err := f.GetFileToContents(filepath.Join(tempDir, "apiserver.key"))
+	if err != nil || copied != read("apiserver.key") {
+		t.Errorf("Reissued apiserver key was not copied: %v", err)
+	}
+
+	if err := RotateCerts(f, k8s, true, nil); err != nil {
+		t.Fatalf("Error rotating certs and CA: %s", err)
+	}
+	if read("ca.crt") == caCert || read("ca.key") == caKey {
+		t.Errorf("CA was not reissued")
+	}
+
+	// The previous certs are put back when the bootstrapper fails to reissue
+	// its own
+	caCert, apiserverKey = read("ca.crt"), read("apiserver.key")
+	failing := func() error { return fmt.Errorf("reissue failed") }
+	if err := RotateCerts(f, k8s, true, failing); err == nil {
+		t.Fatalf("Expected an error rotating certs")
+	}
+	if read("ca.crt") != caCert || read("apiserver.key") != apiserverKey {
+		t.Errorf("Previous certs were not restored")
+	}
+	copied, err = f.GetFileToContents(filepath.Join(tempDir, "apiserver.key"))
+	if err != nil || copied != apiserverKey {
+		t.Errorf("Previous apiserver key was not copied again: %v", err)
+	}
+}
+
+func TestCheckCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		NodeIP:        "192.168.99.100",
+	}
+	if err := SetupCerts(NewFakeCommandRunner(), k8s); err != nil {
+		t.Fatalf("Error setting up certs: %s", err)
+	}
+	machineCerts := filepath.Join(tempDir, "certs")
+	if err := util.GenerateCACert(filepath.Join(machineCerts, "ca.pem"), filepath.Join(machineCerts, "ca-key.pem"), "docker"); err != nil {
+		t.Fatalf("Error generating docker-machine CA: %s", err)
+	}
+
+	infos, err := CheckCerts(tempDir)
+	if err != nil {
+		t.Fatalf("Error checking certs: %s", err)
+	}
+	var names []string
+	for _, info := range infos {
+		rel, _ := filepath.Rel(tempDir, info.Path)
+		names = append(names, rel)
+	}
+	expected := []string{
+		"apiserver.crt", "ca.crt", "client.crt", "proxy-client-ca.crt", "proxy-client.crt",
+		filepath.Join("certs", "ca.pem"),
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected certs %v, got %v", expected, names)
+	}
+	apiserver := infos[0]
+	if apiserver.Subject != "CN=minikube,O=system:masters" || apiserver.Issuer != "CN=minikubeCA" {
+		t.Errorf("Unexpected apiserver subject %q and issuer %q", apiserver.Subject, apiserver.Issuer)
+	}
+	found := false
+	for _, san := range apiserver.SANs() {
+		found = found || san == "192.168.99.100"
+	}
+	if !found {
+		t.Errorf("Node IP missing from apiserver SANs %v", apiserver.SANs())
+	}
+}
+
+func TestSetupCertsWithCA(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	caDir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatalf("Error creating CA dir: %s", err)
+	}
+	defer os.RemoveAll(caDir)
+
+	caCert, caKey := filepath.Join(caDir, "dev-ca.crt"), filepath.Join(caDir, "dev-ca.key")
+	otherKey := filepath.Join(caDir, "other.key")
+	if err := util.GenerateCACert(caCert, caKey, "devCA"); err != nil {
+		t.Fatalf("Error generating CA: %s", err)
+	}
+	if err := util.GenerateCACert(filepath.Join(caDir, "other.crt"), otherKey, "otherCA"); err != nil {
+		t.Fatalf("Error generating CA: %s", err)
+	}
+
+	k8s := KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+		NodeIP:        "192.168.99.100",
+		CACertFile:    caCert,
+		CAKeyFile:     otherKey,
+	}
+	if err := SetupCerts(NewFakeCommandRunner(), k8s); err == nil {
+		t.Fatalf("Expected an error setting up certs with the key of another CA")
+	}
+
+	k8s.CAKeyFile = caKey
+	f := NewFakeCommandRunner()
+	if err := SetupCerts(f, k8s); err != nil {
+		t.Fatalf("Error setting up certs: %s", err)
+	}
+	given, err := ioutil.ReadFile(caCert)
+	if err != nil {
+		t.Fatalf("Error reading CA: %s", err)
+	}
+	copied, err := f.GetFileToContents(filepath.Join(tempDir, "ca.crt"))
+	if err != nil || copied != string(given) {
+		t.Errorf("Given CA was not copied: %v", err)
+	}
+	apiserver, err := util.ReadCertInfo(filepath.Join(tempDir, "apiserver.crt"))
+	if err != nil {
+		t.Fatalf("Error reading apiserver cert: %s", err)
+	}
+	if apiserver.Issuer != "CN=devCA" {
+		t.Errorf("Expected apiserver cert issued by devCA, got %s", apiserver.Issuer)
+	}
+}
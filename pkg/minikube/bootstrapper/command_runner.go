@@ -17,9 +17,13 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/assets"
 )
@@ -29,6 +33,11 @@ type CommandRunner interface {
 	// Run starts the specified command and waits for it to complete.
 	Run(cmd string) error
 
+	// RunCmd runs the command of the request and waits for it to complete.
+	// The result has the exit code of the command, which is also returned
+	// as an *ExitError when it is not zero.
+	RunCmd(req RunRequest) (*RunResult, error)
+
 	// CombinedOutputTo runs the command and stores both command
 	// output and error to out. A typical usage is:
 	//
@@ -49,6 +58,10 @@ type CommandRunner interface {
 	// Copy is a convenience method that runs a command to copy a file
 	Copy(assets.CopyableFile) error
 
+	// CopyAll copies the files, skipping the ones the target already has
+	// where the runner can tell
+	CopyAll([]assets.CopyableFile) error
+
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
 }
@@ -56,3 +69,114 @@ type CommandRunner interface {
 func getDeleteFileCommand(f assets.CopyableFile) string {
 	return fmt.Sprintf("sudo rm %s", filepath.Join(f.GetTargetDir(), f.GetTargetName()))
 }
+
+// copyEach copies the files one at a time
+func copyEach(r CommandRunner, files []assets.CopyableFile) error {
+	for _, f := range files {
+		if err := r.Copy(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunRequest is a command to run with RunCmd.
+type RunRequest struct {
+	// Args are the command and its arguments. They are not interpreted by
+	// a shell, so Args[0] is run with exactly these arguments.
+	Args []string
+	// Stdin is read as the standard input of the command, if not nil.
+	Stdin io.Reader
+	// Env are KEY=value pairs added to the environment of the command.
+	Env []string
+	// Stdout and Stderr receive the output of the command. When nil, the
+	// output is kept in the result instead.
+	Stdout io.Writer
+	Stderr io.Writer
+	// Timeout stops the command when it runs for longer, if not zero.
+	Timeout time.Duration
+	// Context stops the command when it is done, if not nil.
+	Context context.Context
+}
+
+// RunResult is the result of a command run with RunCmd.
+type RunResult struct {
+	Args     []string
+	ExitCode int
+	Duration time.Duration
+	// Stdout and Stderr hold the output the request had no writer for.
+	Stdout bytes.Buffer
+	Stderr bytes.Buffer
+}
+
+// ExitError is returned by RunCmd when the command exits with a non-zero
+// code.
+type ExitError struct {
+	Args     []string
+	ExitCode int
+	Stderr   string
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s: exit status %d", strings.Join(e.Args, " "), e.ExitCode)
+	if e.Stderr != "" {
+		msg += ": " + strings.TrimSpace(e.Stderr)
+	}
+	return msg
+}
+
+// ShellCommand returns the command line of the request, quoted for a
+// POSIX shell, for runners that can only run a command line.
+func (req RunRequest) ShellCommand() string {
+	var words []string
+	if len(req.Env) > 0 {
+		words = append(words, "env")
+		for _, e := range req.Env {
+			words = append(words, shellQuote(e))
+		}
+	}
+	for _, a := range req.Args {
+		words = append(words, shellQuote(a))
+	}
+	return strings.Join(words, " ")
+}
+
+// context returns the context stopping the command of the request.
+func (req RunRequest) context() (context.Context, context.CancelFunc) {
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req.Timeout > 0 {
+		return context.WithTimeout(ctx, req.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// writers returns where the output of the command of the request goes.
+// Writes to the same writer are serialized, as stdout and stderr may be
+// copied concurrently.
+func (req RunRequest) writers(rr *RunResult) (stdout io.Writer, stderr io.Writer) {
+	stdout, stderr = req.Stdout, req.Stderr
+	if stdout == nil {
+		stdout = &rr.Stdout
+	}
+	if stderr == nil {
+		stderr = &rr.Stderr
+	}
+	if stdout == stderr {
+		w := &singleWriter{w: stdout}
+		return w, w
+	}
+	return stdout, stderr
+}
+
+// exitError returns the error of a command that exited with code, and
+// sets the code in rr.
+func exitError(rr *RunResult, code int) error {
+	rr.ExitCode = code
+	if code == 0 {
+		return nil
+	}
+	return &ExitError{Args: rr.Args, ExitCode: code, Stderr: rr.Stderr.String()}
+}
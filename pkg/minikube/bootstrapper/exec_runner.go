@@ -23,6 +23,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -44,6 +46,43 @@ func (*ExecRunner) Run(cmd string) error {
 	return nil
 }
 
+// RunCmd runs the command of the request, without a shell.
+func (*ExecRunner) RunCmd(req RunRequest) (*RunResult, error) {
+	rr := &RunResult{Args: req.Args}
+	if len(req.Args) == 0 {
+		rr.ExitCode = -1
+		return rr, errors.New("no command to run")
+	}
+	glog.Infoln("Run:", req.ShellCommand())
+	ctx, cancel := req.context()
+	defer cancel()
+
+	c := exec.CommandContext(ctx, req.Args[0], req.Args[1:]...)
+	if len(req.Env) > 0 {
+		c.Env = append(os.Environ(), req.Env...)
+	}
+	c.Stdin = req.Stdin
+	c.Stdout, c.Stderr = req.writers(rr)
+
+	start := time.Now()
+	err := c.Run()
+	rr.Duration = time.Since(start)
+	if ctx.Err() != nil {
+		rr.ExitCode = -1
+		return rr, errors.Wrapf(ctx.Err(), "running command: %s", req.ShellCommand())
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return rr, exitError(rr, status.ExitStatus())
+		}
+	}
+	if err != nil {
+		rr.ExitCode = -1
+		return rr, errors.Wrapf(err, "running command: %s", req.ShellCommand())
+	}
+	return rr, nil
+}
+
 // CombinedOutputTo runs the command and stores both command
 // output and error to out.
 func (*ExecRunner) CombinedOutputTo(cmd string, out io.Writer) error {
@@ -103,6 +142,11 @@ do you have the correct permissions?`,
 	return target.Close()
 }
 
+// CopyAll copies the files
+func (e *ExecRunner) CopyAll(files []assets.CopyableFile) error {
+	return copyEach(e, files)
+}
+
 // Remove removes a file
 func (e *ExecRunner) Remove(f assets.CopyableFile) error {
 	targetPath := filepath.Join(f.GetTargetDir(), f.GetTargetName())
@@ -0,0 +1,110 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecRunnerRunCmd(t *testing.T) {
+	r := &ExecRunner{}
+	rr, err := r.RunCmd(RunRequest{
+		Args:  []string{"sh", "-c", `read line; echo "$line $GREETING"; echo oops >&2; exit 3`},
+		Stdin: strings.NewReader("hello\n"),
+		Env:   []string{"GREETING=world"},
+	})
+	exitErr, ok := err.(*ExitError)
+	if !ok {
+		t.Fatalf("Expected an ExitError, got %v", err)
+	}
+	if rr.ExitCode != 3 || exitErr.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d and %d", rr.ExitCode, exitErr.ExitCode)
+	}
+	if rr.Stdout.String() != "hello world\n" || rr.Stderr.String() != "oops\n" {
+		t.Errorf("Unexpected output %q and %q", rr.Stdout.String(), rr.Stderr.String())
+	}
+	if exitErr.Stderr != "oops\n" {
+		t.Errorf("Expected the error to have stderr, got %q", exitErr.Stderr)
+	}
+}
+
+func TestExecRunnerRunCmdWriters(t *testing.T) {
+	var b bytes.Buffer
+	rr, err := (&ExecRunner{}).RunCmd(RunRequest{
+		Args:   []string{"sh", "-c", "echo out; echo err >&2"},
+		Stdout: &b,
+		Stderr: &b,
+	})
+	if err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	if !strings.Contains(b.String(), "out\n") || !strings.Contains(b.String(), "err\n") {
+		t.Errorf("Expected both outputs in the writer, got %q", b.String())
+	}
+	if rr.Stdout.Len() != 0 || rr.Stderr.Len() != 0 {
+		t.Errorf("Expected no output in the result when writers are set")
+	}
+}
+
+func TestExecRunnerRunCmdTimeout(t *testing.T) {
+	rr, err := (&ExecRunner{}).RunCmd(RunRequest{
+		Args:    []string{"sleep", "10"},
+		Timeout: 50 * time.Millisecond,
+	})
+	if err == nil || rr.ExitCode != -1 {
+		t.Fatalf("Expected a timeout, got exit code %d and %v", rr.ExitCode, err)
+	}
+	if rr.Duration > 5*time.Second {
+		t.Errorf("Command was not stopped, ran for %s", rr.Duration)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (&ExecRunner{}).RunCmd(RunRequest{Args: []string{"sleep", "10"}, Context: ctx}); err == nil {
+		t.Errorf("Expected an error running with a cancelled context")
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	req := RunRequest{
+		Args: []string{"sudo", "cat", "/etc/it's here"},
+		Env:  []string{"A=b c"},
+	}
+	expected := `env 'A=b c' 'sudo' 'cat' '/etc/it'\''s here'`
+	if cmd := req.ShellCommand(); cmd != expected {
+		t.Errorf("ShellCommand() = %s, expected %s", cmd, expected)
+	}
+}
+
+func TestFakeCommandRunnerRunCmd(t *testing.T) {
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{"echo hi": "hi\n"})
+	f.SetCommandToExitCode(map[string]int{"false": 1})
+	if rr, err := f.RunCmd(RunRequest{Args: []string{"echo", "hi"}}); err != nil || rr.Stdout.String() != "hi\n" {
+		t.Errorf("Unexpected result %q, %v", rr.Stdout.String(), err)
+	}
+	if rr, err := f.RunCmd(RunRequest{Args: []string{"false"}}); err == nil || rr.ExitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d, %v", rr.ExitCode, err)
+	}
+	if _, err := f.RunCmd(RunRequest{Args: []string{"unknown"}}); err == nil {
+		t.Errorf("Expected an error for an unavailable command")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
 
 	"golang.org/x/sync/syncmap"
 
@@ -32,8 +33,9 @@ import (
 //
 // It implements the CommandRunner interface and is used for testing.
 type FakeCommandRunner struct {
-	cmdMap  syncmap.Map
-	fileMap syncmap.Map
+	cmdMap      syncmap.Map
+	exitCodeMap syncmap.Map
+	fileMap     syncmap.Map
 }
 
 // NewFakeCommandRunner returns a new FakeCommandRunner
@@ -49,6 +51,29 @@ func (f *FakeCommandRunner) Run(cmd string) error {
 	return err
 }
 
+// RunCmd writes the output set for the command, its arguments joined by
+// spaces, and exits with the code set with SetCommandToExitCode, or 0.
+func (f *FakeCommandRunner) RunCmd(req RunRequest) (*RunResult, error) {
+	rr := &RunResult{Args: req.Args}
+	cmd := strings.Join(req.Args, " ")
+	out, hasOutput := f.cmdMap.Load(cmd)
+	code, hasCode := f.exitCodeMap.Load(cmd)
+	if !hasOutput && !hasCode {
+		rr.ExitCode = -1
+		return rr, fmt.Errorf("unavailable command: %s", cmd)
+	}
+	if hasOutput {
+		stdout, _ := req.writers(rr)
+		if _, err := fmt.Fprint(stdout, out); err != nil {
+			return rr, err
+		}
+	}
+	if !hasCode {
+		return rr, nil
+	}
+	return rr, exitError(rr, code.(int))
+}
+
 // CombinedOutputTo runs the command and stores both command
 // output and error to out.
 func (f *FakeCommandRunner) CombinedOutputTo(cmd string, out io.Writer) error {
@@ -84,6 +109,11 @@ func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyAll adds the files to the file map
+func (f *FakeCommandRunner) CopyAll(files []assets.CopyableFile) error {
+	return copyEach(f, files)
+}
+
 // Remove removes the filename, file contents key value pair from the stored map
 func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	f.fileMap.Delete(file.GetAssetName())
@@ -97,6 +127,13 @@ func (f *FakeCommandRunner) SetFileToContents(fileToContents map[string]string)
 	}
 }
 
+// SetCommandToExitCode stores the exit codes RunCmd returns for commands
+func (f *FakeCommandRunner) SetCommandToExitCode(cmdToCode map[string]int) {
+	for k, v := range cmdToCode {
+		f.exitCodeMap.Store(k, v)
+	}
+}
+
 // SetCommandToOutput stores the file to contents map for the FakeCommandRunner
 func (f *FakeCommandRunner) SetCommandToOutput(cmdToOutput map[string]string) {
 	for k, v := range cmdToOutput {
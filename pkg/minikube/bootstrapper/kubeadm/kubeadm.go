@@ -21,22 +21,26 @@ import (
 	"crypto"
 	"fmt"
 	"io"
-	"os"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/golang/glog"
-	download "github.com/jimmidyson/go-download"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/service"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/util"
 )
@@ -46,7 +50,13 @@ type KubeadmBootstrapper struct {
 }
 
 func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
-	h, err := api.Load(config.GetMachineName())
+	return NewKubeadmBootstrapperForMachine(api, config.GetMachineName())
+}
+
+// NewKubeadmBootstrapperForMachine returns a bootstrapper that runs its
+// commands on the machine with the given name.
+func NewKubeadmBootstrapperForMachine(api libmachine.API, name string) (*KubeadmBootstrapper, error) {
+	h, err := api.Load(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "getting api client")
 	}
@@ -55,7 +65,7 @@ func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
 	if h.Driver.DriverName() == constants.DriverNone {
 		cmd = &bootstrapper.ExecRunner{}
 	} else {
-		client, err := sshutil.NewSSHClient(h.Driver)
+		client, err := sshutil.GetClient(h.Driver)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting ssh client")
 		}
@@ -66,42 +76,129 @@ func NewKubeadmBootstrapper(api libmachine.API) (*KubeadmBootstrapper, error) {
 	}, nil
 }
 
-//TODO(r2d4): This should most likely check the health of the apiserver
-func (k *KubeadmBootstrapper) GetClusterStatus() (string, error) {
-	statusCmd := `sudo systemctl is-active kubelet &>/dev/null && echo "Running" || echo "Stopped"`
-	status, err := k.c.CombinedOutput(statusCmd)
+// kubeletStatusArgs exit with 0 when the kubelet is running
+var kubeletStatusArgs = []string{"sudo", "systemctl", "is-active", "--quiet", "kubelet"}
+
+// GetClusterStatus returns the status of the kubelet, of the apiserver as
+// reported by its healthz endpoint, and of the control plane containers.
+func (k *KubeadmBootstrapper) GetClusterStatus() (bootstrapper.ClusterStatus, error) {
+	var cs bootstrapper.ClusterStatus
+	rr, err := k.c.RunCmd(bootstrapper.RunRequest{Args: kubeletStatusArgs})
+	status := state.Running.String()
+	if _, ok := err.(*bootstrapper.ExitError); ok {
+		// systemctl is-active exits with a non-zero code for inactive units
+		status = state.Stopped.String()
+	} else if err != nil {
+		return cs, errors.Wrap(err, "getting status")
+	}
+	glog.Infof("kubelet is %s (exit code %d)", status, rr.ExitCode)
+	cs.Cluster = status
+	if status != state.Running.String() {
+		cs.APIServer = state.Stopped.String()
+		cs.Components = []bootstrapper.ComponentStatus{{Name: "kubelet", State: status}}
+		return cs, nil
+	}
+
+	r, err := cruntime.Detect(k.c)
 	if err != nil {
-		return "", errors.Wrap(err, "getting status")
+		return cs, errors.Wrap(err, "detecting runtime")
+	}
+	health := bootstrapper.APIServerHealth(k.c)
+	cs.APIServer = health.State
+	// Healthz is what matters for the apiserver, but when it is not healthy
+	// a stopped container tells why
+	apiserver := k.containerStatus(r, "apiserver")
+	if health.State == state.Running.String() || apiserver.State == state.Running.String() {
+		apiserver = health
+	}
+	cs.Components = append(cs.Components,
+		bootstrapper.ComponentStatus{Name: "kubelet", State: status},
+		apiserver,
+		k.containerStatus(r, "etcd"),
+		k.containerStatus(r, "scheduler"),
+		k.containerStatus(r, "controller-manager"))
+	return cs, nil
+}
+
+// containerStatus returns the status of the container of a control plane
+// component. The last error of a stopped container is its last log line.
+func (k *KubeadmBootstrapper) containerStatus(r cruntime.Manager, component string) bootstrapper.ComponentStatus {
+	s := bootstrapper.ComponentStatus{Name: component, State: state.Error.String()}
+	id, err := r.FindContainer(k.c, controlPlaneContainers[component])
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	if id == "" {
+		s.State = state.Stopped.String()
+		s.Error = "no container found"
+		return s
 	}
-	status = strings.TrimSpace(status)
-	if status == state.Running.String() || status == state.Stopped.String() {
-		return status, nil
+	running, err := r.ContainerRunning(k.c, id)
+	if err != nil {
+		s.Error = err.Error()
+		return s
 	}
-	return "", fmt.Errorf("Error: Unrecognized output from ClusterStatus: %s", status)
+	if running {
+		s.State = state.Running.String()
+		return s
+	}
+	s.State = state.Stopped.String()
+	logs, err := k.c.CombinedOutput(r.ContainerLogCmd(id, 0, 1, false))
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	_, s.Error, _ = bootstrapper.ParseLogLine(strings.TrimSpace(logs))
+	return s
 }
 
-// TODO(r2d4): Should this aggregate all the logs from the control plane?
-// Maybe subcommands for each component? minikube logs apiserver?
-func (k *KubeadmBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) error {
-	var flags []string
-	if follow {
-		flags = append(flags, "-f")
-	}
-	logsCommand := fmt.Sprintf("sudo journalctl %s -u kubelet", strings.Join(flags, " "))
+// controlPlaneContainers maps the control plane components running as static
+// pods to their Kubernetes container names
+var controlPlaneContainers = map[string]string{
+	"apiserver":          "kube-apiserver",
+	"etcd":               "etcd",
+	"scheduler":          "kube-scheduler",
+	"controller-manager": "kube-controller-manager",
+}
 
-	if follow {
-		if err := k.c.CombinedOutputTo(logsCommand, out); err != nil {
-			return errors.Wrap(err, "getting cluster logs")
-		}
-	} else {
+// GetClusterLogsTo writes the logs of the kubelet, the container runtime and
+// the control plane containers selected by opts to out.
+func (k *KubeadmBootstrapper) GetClusterLogsTo(opts bootstrapper.LogOptions, out io.Writer) error {
+	r, err := cruntime.New(opts.ContainerRuntime)
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	runtimeUnit := strings.TrimSuffix(r.ServiceName(), ".socket")
+	available := []string{"apiserver", "etcd", "scheduler", "controller-manager", "kubelet", r.Name()}
+	components, err := bootstrapper.SelectLogComponents(opts, available)
+	if err != nil {
+		return err
+	}
 
-		logs, err := k.c.CombinedOutput(logsCommand)
-		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
+	commands := map[string]string{}
+	for _, c := range components {
+		switch c {
+		case "kubelet":
+			commands[c] = bootstrapper.JournalLogsCommand("kubelet", opts)
+		case r.Name():
+			commands[c] = bootstrapper.JournalLogsCommand(runtimeUnit, opts)
+		default:
+			id, err := r.FindContainer(k.c, controlPlaneContainers[c])
+			if err == nil && id == "" {
+				err = fmt.Errorf("no %s container found", c)
+			}
+			if err != nil {
+				if len(components) == 1 {
+					return errors.Wrapf(err, "finding %s container", c)
+				}
+				glog.Warningf("Skipping %s logs: %v", c, err)
+				continue
+			}
+			commands[c] = r.ContainerLogCmd(id, opts.Since, opts.CommandTail(), opts.Follow)
 		}
-		fmt.Fprint(out, logs)
 	}
-	return nil
+	return bootstrapper.WriteLogsTo(k.c, commands, opts, out)
 }
 
 func (k *KubeadmBootstrapper) StartCluster(k8s bootstrapper.KubernetesConfig) error {
@@ -145,9 +242,11 @@ func addAddons(files *[]assets.CopyableFile) error {
 			continue
 		}
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				*files = append(*files, addon)
+			addonFiles, err := addonBundle.RenderedAssets()
+			if err != nil {
+				return errors.Wrapf(err, "rendering addon %s", addonName)
 			}
+			*files = append(*files, addonFiles...)
 		} else if err != nil {
 			return nil
 		}
@@ -157,6 +256,20 @@ func addAddons(files *[]assets.CopyableFile) error {
 }
 
 func (k *KubeadmBootstrapper) RestartCluster(k8s bootstrapper.KubernetesConfig) error {
+	if err := k.restoreControlPlane(); err != nil {
+		return err
+	}
+
+	if err := restartKubeProxy(k8s); err != nil {
+		return errors.Wrap(err, "restarting kube-proxy")
+	}
+
+	return nil
+}
+
+// restoreControlPlane runs the kubeadm phases generating whatever certs,
+// kubeconfig files and static pod manifests are missing
+func (k *KubeadmBootstrapper) restoreControlPlane() error {
 	opts := struct {
 		KubeadmConfigFile string
 	}{
@@ -171,40 +284,216 @@ func (k *KubeadmBootstrapper) RestartCluster(k8s bootstrapper.KubernetesConfig)
 	if err := k.c.Run(b.String()); err != nil {
 		return errors.Wrapf(err, "running cmd: %s", b.String())
 	}
+	return nil
+}
 
-	if err := restartKubeProxy(k8s); err != nil {
-		return errors.Wrap(err, "restarting kube-proxy")
+func (k *KubeadmBootstrapper) SetupCerts(k8s bootstrapper.KubernetesConfig) error {
+	return bootstrapper.SetupCerts(k.c, k8s)
+}
+
+// vmCertDirs are the directories of the machine holding the certificates
+// kubeadm and the kubelet generate, which are not in the minikube directory,
+// and the kubeconfigs of the components, which embed client certificates
+var vmCertDirs = []string{util.DefaultCertPath, "/var/lib/kubelet/pki", "/etc/kubernetes"}
+
+// CheckCerts returns the certificates of the machine, including the client
+// certificates of the kubeconfigs, ordered by path. Their paths are prefixed
+// with the machine name.
+func (k *KubeadmBootstrapper) CheckCerts(name string) ([]*util.CertInfo, error) {
+	args := append([]string{"sudo", "find"}, vmCertDirs...)
+	args = append(args, "-maxdepth", "2", "-name", "*.crt", "-o", "-name", "*.conf", "-o", "-name", "kubelet-client-current.pem")
+	rr, err := k.c.RunCmd(bootstrapper.RunRequest{Args: args})
+	if err != nil {
+		// find still lists the directories that exist
+		if _, ok := errors.Cause(err).(*bootstrapper.ExitError); !ok {
+			return nil, errors.Wrap(err, "listing certificates")
+		}
+		glog.Warningf("Error listing some certificates: %v", err)
+	}
+	paths := strings.Fields(rr.Stdout.String())
+	sort.Strings(paths)
+
+	var infos []*util.CertInfo
+	for _, p := range paths {
+		rr, err := k.c.RunCmd(bootstrapper.RunRequest{Args: []string{"sudo", "cat", p}})
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", p)
+		}
+		vmPath := name + ":" + p
+		if strings.HasSuffix(p, ".conf") {
+			certs, err := kubeconfigCertInfos(vmPath, rr.Stdout.Bytes())
+			if err != nil {
+				return nil, err
+			}
+			infos = append(infos, certs...)
+			continue
+		}
+		info, err := util.ParseCertInfo(vmPath, rr.Stdout.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// kubeconfigCertInfos returns the client certificates embedded in the
+// kubeconfig read from path, ordered by user
+func kubeconfigCertInfos(path string, data []byte) ([]*util.CertInfo, error) {
+	kubeconfig, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", path)
+	}
+	var users []string
+	for user, auth := range kubeconfig.AuthInfos {
+		if len(auth.ClientCertificateData) > 0 {
+			users = append(users, user)
+		}
+	}
+	sort.Strings(users)
+	var infos []*util.CertInfo
+	for _, user := range users {
+		info, err := util.ParseCertInfo(fmt.Sprintf("%s (%s)", path, user), kubeconfig.AuthInfos[user].ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// RotateCerts reissues the minikube certs, then the certs and kubeconfig
+// files kubeadm generated, and restarts the kubelet and the control plane
+// containers to use them.
+func (k *KubeadmBootstrapper) RotateCerts(k8s bootstrapper.KubernetesConfig, rotateCA bool) error {
+	reissue := func() error { return k.reissueKubeadmCerts(rotateCA) }
+	if err := bootstrapper.RotateCerts(k.c, k8s, rotateCA, reissue); err != nil {
+		return errors.Wrap(err, "rotating certs")
+	}
+	if err := k.c.Run(kubeletRestartCmd); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
 	}
 
+	r, err := cruntime.New(k8s.ContainerRuntime)
+	if err != nil {
+		return err
+	}
+	components := []string{}
+	for component := range controlPlaneContainers {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	// The kubelet restarts the stopped static pod containers
+	for _, component := range components {
+		id, err := r.FindContainer(k.c, controlPlaneContainers[component])
+		if err != nil {
+			return errors.Wrapf(err, "finding %s container", component)
+		}
+		if id == "" {
+			continue
+		}
+		if err := r.StopContainer(k.c, id); err != nil {
+			return errors.Wrapf(err, "stopping %s container", component)
+		}
+	}
 	return nil
 }
 
-func (k *KubeadmBootstrapper) SetupCerts(k8s bootstrapper.KubernetesConfig) error {
-	return bootstrapper.SetupCerts(k.c, k8s)
+var (
+	// kubeadmLeafCerts and kubeadmCACerts are the certs kubeadm generates
+	// in the cert dir, besides the ones copied by SetupCerts
+	kubeadmLeafCerts = []string{
+		"apiserver-kubelet-client", "front-proxy-client", "apiserver-etcd-client",
+		"etcd/server", "etcd/peer", "etcd/healthcheck-client",
+	}
+	kubeadmCACerts = []string{"front-proxy-ca", "etcd/ca"}
+
+	// kubeadmKubeconfigs embed client certs, and kubeadm only writes them
+	// when they are missing
+	kubeadmKubeconfigs = []string{
+		"/etc/kubernetes/admin.conf", "/etc/kubernetes/kubelet.conf",
+		"/etc/kubernetes/controller-manager.conf", "/etc/kubernetes/scheduler.conf",
+	}
+
+	// kubeletServingCert is self signed by the kubelet when it is missing
+	kubeletServingCert = "/var/lib/kubelet/pki/kubelet"
+)
+
+// reissueKubeadmCerts moves aside the certs and kubeconfig files kubeadm
+// generated so that it reissues them. They are put back in place if kubeadm
+// fails.
+func (k *KubeadmBootstrapper) reissueKubeadmCerts(rotateCA bool) error {
+	paths := kubeadmCertPaths(rotateCA)
+	if err := k.c.Run(backupFilesCmd(paths)); err != nil {
+		return errors.Wrap(err, "backing up kubeadm certs")
+	}
+	if err := k.restoreControlPlane(); err != nil {
+		if rerr := k.c.Run(restoreFilesCmd(paths)); rerr != nil {
+			return errors.Wrapf(rerr, "restoring kubeadm certs after: %v", err)
+		}
+		return err
+	}
+	rmCmd := removeBackupsCmd(paths)
+	if err := k.c.Run(rmCmd); err != nil {
+		glog.Warningf("Error running %s: %v", rmCmd, err)
+	}
+	return nil
+}
+
+// kubeadmCertPaths returns the leaf certs, and the CAs if rotateCA is set,
+// that kubeadm and the kubelet generated, along with the kubeconfig files
+// embedding them
+func kubeadmCertPaths(rotateCA bool) []string {
+	names := kubeadmLeafCerts
+	if rotateCA {
+		names = append(append([]string{}, kubeadmCACerts...), kubeadmLeafCerts...)
+	}
+	paths := []string{}
+	for _, name := range names {
+		p := path.Join(util.DefaultCertPath, name)
+		paths = append(paths, p+".crt", p+".key")
+	}
+	paths = append(paths, kubeletServingCert+".crt", kubeletServingCert+".key")
+	return append(paths, kubeadmKubeconfigs...)
+}
+
+// backupFilesCmd returns the command moving the existing files at paths to
+// their backup
+func backupFilesCmd(paths []string) string {
+	return moveFilesCmd(paths, "", bootstrapper.CertBackupSuffix)
+}
+
+// restoreFilesCmd returns the command moving the backups of paths back in
+// place
+func restoreFilesCmd(paths []string) string {
+	return moveFilesCmd(paths, bootstrapper.CertBackupSuffix, "")
+}
+
+func moveFilesCmd(paths []string, from, to string) string {
+	return fmt.Sprintf(`sudo sh -c 'for f in %s; do if [ -e "$f%s" ]; then mv -f "$f%s" "$f%s"; fi; done'`,
+		strings.Join(paths, " "), from, from, to)
+}
+
+// removeBackupsCmd returns the command removing the backups of paths
+func removeBackupsCmd(paths []string) string {
+	backups := []string{}
+	for _, p := range paths {
+		backups = append(backups, p+bootstrapper.CertBackupSuffix)
+	}
+	return "sudo rm -f " + strings.Join(backups, " ")
 }
 
 // SetContainerRuntime possibly sets the container runtime, if it hasn't already
 // been specified by the extra-config option.  It has a set of defaults known to
 // work for a particular runtime.
-func SetContainerRuntime(cfg map[string]string, runtime string) map[string]string {
+func SetContainerRuntime(cfg map[string]string, r cruntime.Manager) map[string]string {
 	if _, ok := cfg["container-runtime"]; ok {
 		glog.Infoln("Container runtime already set through extra options, ignoring --container-runtime flag.")
 		return cfg
 	}
 
-	if runtime == "" {
-		glog.Infoln("Container runtime flag provided with no value, using defaults.")
-		return cfg
-	}
-
-	switch runtime {
-	case "crio", "cri-o":
-		cfg["container-runtime"] = "remote"
-		cfg["container-runtime-endpoint"] = "/var/run/crio/crio.sock"
-		cfg["image-service-endpoint"] = "/var/run/crio/crio.sock"
-		cfg["runtime-request-timeout"] = "15m"
-	default:
-		cfg["container-runtime"] = runtime
+	for k, v := range r.KubeletOptions() {
+		cfg[k] = v
 	}
 
 	return cfg
@@ -223,17 +512,21 @@ func NewKubeletConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 		return "", errors.Wrap(err, "generating extra configuration for kubelet")
 	}
 
-	extraOpts = SetContainerRuntime(extraOpts, k8s.ContainerRuntime)
+	r, err := cruntime.New(k8s.ContainerRuntime)
+	if err != nil {
+		return "", err
+	}
+	extraOpts = SetContainerRuntime(extraOpts, r)
 	extraFlags := convertToFlags(extraOpts)
 	b := bytes.Buffer{}
 	opts := struct {
-		ExtraOptions     string
-		FeatureGates     string
-		ContainerRuntime string
+		ExtraOptions   string
+		FeatureGates   string
+		RuntimeService string
 	}{
-		ExtraOptions:     extraFlags,
-		FeatureGates:     k8s.FeatureGates,
-		ContainerRuntime: k8s.ContainerRuntime,
+		ExtraOptions:   extraFlags,
+		FeatureGates:   k8s.FeatureGates,
+		RuntimeService: r.ServiceName(),
 	}
 	if err := kubeletSystemdTemplate.Execute(&b, opts); err != nil {
 		return "", err
@@ -243,9 +536,15 @@ func NewKubeletConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 }
 
 func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) error {
+	r, err := cruntime.New(cfg.ContainerRuntime)
+	if err != nil {
+		return err
+	}
+	if err := cruntime.Enable(k.c, r); err != nil {
+		return errors.Wrapf(err, "enabling %s", r.Name())
+	}
 	if cfg.ShouldLoadCachedImages {
-		// Make best effort to load any cached images
-		go machine.LoadImages(k.c, constants.GetKubeadmCachedImages(cfg.KubernetesVersion), constants.ImageCacheDir)
+		machine.LoadCachedImages(k.c, r, bootstrapper.GetCachedImageList(cfg.KubernetesVersion, bootstrapper.BootstrapperTypeKubeadm))
 	}
 	kubeadmCfg, err := generateConfig(cfg)
 	if err != nil {
@@ -263,25 +562,7 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) e
 		assets.NewMemoryAssetTarget([]byte(kubeadmCfg), constants.KubeadmConfigFile, "0640"),
 	}
 
-	var g errgroup.Group
-	for _, bin := range []string{"kubelet", "kubeadm"} {
-		bin := bin
-		g.Go(func() error {
-			path, err := maybeDownloadAndCache(bin, cfg.KubernetesVersion)
-			if err != nil {
-				return errors.Wrapf(err, "downloading %s", bin)
-			}
-			f, err := assets.NewFileAsset(path, "/usr/bin", bin, "0641")
-			if err != nil {
-				return errors.Wrap(err, "making new file asset")
-			}
-			if err := k.c.Copy(f); err != nil {
-				return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
-			}
-			return nil
-		})
-	}
-	if err := g.Wait(); err != nil {
+	if err := k.copyBinaries(cfg.KubernetesVersion); err != nil {
 		return errors.Wrap(err, "downloading binaries")
 	}
 
@@ -289,10 +570,8 @@ func (k *KubeadmBootstrapper) UpdateCluster(cfg bootstrapper.KubernetesConfig) e
 		return errors.Wrap(err, "adding addons to copyable files")
 	}
 
-	for _, f := range files {
-		if err := k.c.Copy(f); err != nil {
-			return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
-		}
+	if err := k.c.CopyAll(files); err != nil {
+		return errors.Wrap(err, "transferring kubeadm files")
 	}
 
 	err = k.c.Run(`
@@ -307,6 +586,132 @@ sudo systemctl start kubelet
 	return nil
 }
 
+// copyBinaries transfers the kubelet and kubeadm binaries for version to the
+// machine, downloading them into the local cache first if needed.
+func (k *KubeadmBootstrapper) copyBinaries(version string) error {
+	var g errgroup.Group
+	for _, bin := range CachedBinaries {
+		bin := bin
+		g.Go(func() error {
+			return k.copyBinary(bin, version)
+		})
+	}
+	return g.Wait()
+}
+
+// copyBinary transfers a single kubernetes binary for version to the machine.
+func (k *KubeadmBootstrapper) copyBinary(bin, version string) error {
+	path, err := maybeDownloadAndCache(bin, version)
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s", bin)
+	}
+	f, err := assets.NewFileAsset(path, "/usr/bin", bin, "0641")
+	if err != nil {
+		return errors.Wrap(err, "making new file asset")
+	}
+	if err := k.c.Copy(f); err != nil {
+		return errors.Wrapf(err, "transferring kubeadm file: %+v", f)
+	}
+	return nil
+}
+
+// GetJoinCommand creates a new bootstrap token on the control plane and
+// returns the kubeadm join command that worker nodes should run with it.
+func (k *KubeadmBootstrapper) GetJoinCommand() (string, error) {
+	out, err := k.c.CombinedOutput(kubeadmTokenCreateCmd)
+	if err != nil {
+		return "", errors.Wrap(err, "creating join token")
+	}
+	joinCmd := strings.TrimSpace(out)
+	if !strings.HasPrefix(joinCmd, "kubeadm join") {
+		return "", fmt.Errorf("Error: Unrecognized output from kubeadm token create: %s", joinCmd)
+	}
+	return joinCmd, nil
+}
+
+// UpdateNode transfers the binaries and kubelet configuration needed for
+// the machine to act as a worker node named nodeName.
+func (k *KubeadmBootstrapper) UpdateNode(cfg bootstrapper.KubernetesConfig, nodeName string) error {
+	r, err := cruntime.New(cfg.ContainerRuntime)
+	if err != nil {
+		return err
+	}
+	if err := cruntime.Enable(k.c, r); err != nil {
+		return errors.Wrapf(err, "enabling %s", r.Name())
+	}
+	if cfg.ShouldLoadCachedImages {
+		machine.LoadCachedImages(k.c, r, bootstrapper.GetCachedImageList(cfg.KubernetesVersion, bootstrapper.BootstrapperTypeKubeadm))
+	}
+
+	// Workers register under their own name and get the cluster CA from kubeadm join
+	cfg.NodeName = nodeName
+	cfg.ExtraOptions = append(util.ExtraOptionSlice{}, cfg.ExtraOptions...)
+	cfg.ExtraOptions = append(cfg.ExtraOptions,
+		util.ExtraOption{Component: Kubelet, Key: "hostname-override", Value: nodeName},
+		util.ExtraOption{Component: Kubelet, Key: "client-ca-file", Value: constants.KubeadmCACertFile},
+	)
+	kubeletCfg, err := NewKubeletConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet config")
+	}
+
+	files := []assets.CopyableFile{
+		assets.NewMemoryAssetTarget([]byte(kubeletService), constants.KubeletServiceFile, "0640"),
+		assets.NewMemoryAssetTarget([]byte(kubeletCfg), constants.KubeletSystemdConfFile, "0640"),
+	}
+
+	if err := k.copyBinaries(cfg.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+
+	if err := k.c.CopyAll(files); err != nil {
+		return errors.Wrap(err, "transferring kubeadm files")
+	}
+
+	if err := k.c.Run(`
+sudo systemctl daemon-reload &&
+sudo systemctl enable kubelet
+`); err != nil {
+		return errors.Wrap(err, "enabling kubelet")
+	}
+
+	return nil
+}
+
+// JoinCluster runs the join command obtained from the control plane's
+// GetJoinCommand on this machine.
+func (k *KubeadmBootstrapper) JoinCluster(joinCmd string) error {
+	b := bytes.Buffer{}
+	if err := kubeadmJoinTemplate.Execute(&b, struct{ JoinCommand string }{joinCmd}); err != nil {
+		return err
+	}
+
+	if err := k.c.Run(b.String()); err != nil {
+		return errors.Wrapf(err, "kubeadm join error running command: %s", b.String())
+	}
+	return nil
+}
+
+// ResetNode undoes the changes made to this machine by JoinCluster.
+func (k *KubeadmBootstrapper) ResetNode() error {
+	if err := k.c.Run(kubeadmResetCmd); err != nil {
+		return errors.Wrapf(err, "running cmd: %s", kubeadmResetCmd)
+	}
+	return nil
+}
+
+// RemoveNode removes the node object for nodeName from the cluster.
+func RemoveNode(nodeName string) error {
+	client, err := service.K8s.GetCoreClient()
+	if err != nil {
+		return errors.Wrap(err, "getting core client")
+	}
+	if err := client.Nodes().Delete(nodeName, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+		return errors.Wrapf(err, "deleting node %s", nodeName)
+	}
+	return nil
+}
+
 func generateConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 	version, err := ParseKubernetesVersion(k8s.KubernetesVersion)
 	if err != nil {
@@ -334,7 +739,7 @@ func generateConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 		AdvertiseAddress:  k8s.NodeIP,
 		APIServerPort:     util.APIServerPort,
 		KubernetesVersion: k8s.KubernetesVersion,
-		EtcdDataDir:       "/data", //TODO(r2d4): change to something else persisted
+		EtcdDataDir:       constants.KubeadmEtcdDataDir,
 		NodeName:          k8s.NodeName,
 		ExtraArgs:         extraComponentConfig,
 	}
@@ -347,33 +752,43 @@ func generateConfig(k8s bootstrapper.KubernetesConfig) (string, error) {
 	return b.String(), nil
 }
 
-func maybeDownloadAndCache(binary, version string) (string, error) {
-	targetDir := constants.MakeMiniPath("cache", version)
-	targetFilepath := path.Join(targetDir, binary)
+// CachedBinaries are the kubernetes binaries the kubeadm bootstrapper
+// downloads to the cache and transfers to the machine
+var CachedBinaries = []string{"kubelet", "kubeadm"}
 
-	_, err := os.Stat(targetFilepath)
-	// If it exists, do no verification and continue
-	if err == nil {
-		return targetFilepath, nil
-	}
-	if !os.IsNotExist(err) {
-		return "", errors.Wrapf(err, "stat %s version %s at %s", binary, version, targetDir)
+// CachedBinaryPath returns the location of a cached kubernetes binary.
+func CachedBinaryPath(binary, version string) string {
+	return path.Join(constants.MakeMiniPath("cache", version), binary)
+}
+
+// CacheBinaries downloads the kubernetes binaries for version to the cache,
+// unless they are already there.
+func CacheBinaries(version string) error {
+	var g errgroup.Group
+	for _, bin := range CachedBinaries {
+		bin := bin
+		g.Go(func() error {
+			_, err := maybeDownloadAndCache(bin, version)
+			return err
+		})
 	}
+	return g.Wait()
+}
 
-	if err = os.MkdirAll(targetDir, 0777); err != nil {
-		return "", errors.Wrapf(err, "mkdir %s", targetDir)
+func maybeDownloadAndCache(binary, version string) (string, error) {
+	targetFilepath := CachedBinaryPath(binary, version)
+	if util.IsCached(targetFilepath) {
+		return targetFilepath, nil
 	}
 
 	url := constants.GetKubernetesReleaseURL(binary, version)
-	options := download.FileOptions{
-		Mkdirs: download.MkdirAll,
+	options := util.DownloadOptions{
+		ChecksumURL:  constants.GetKubernetesReleaseURLSha1(binary, version),
+		ChecksumHash: crypto.SHA1,
 	}
 
-	options.Checksum = constants.GetKubernetesReleaseURLSha1(binary, version)
-	options.ChecksumHash = crypto.SHA1
-
 	fmt.Printf("Downloading %s %s\n", binary, version)
-	if err := download.ToFile(url, targetFilepath, options); err != nil {
+	if err := util.DownloadToCache(url, targetFilepath, options); err != nil {
 		return "", errors.Wrapf(err, "Error downloading %s %s", binary, version)
 	}
 	fmt.Printf("Finished Downloading %s %s\n", binary, version)
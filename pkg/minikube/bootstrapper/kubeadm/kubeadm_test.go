@@ -17,9 +17,20 @@ limitations under the License.
 package kubeadm
 
 import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -232,3 +243,301 @@ schedulerExtraArgs:
 		})
 	}
 }
+
+func TestGetClusterLogsTo(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"docker ps -a --filter=name=k8s_kube-apiserver --format={{.ID}}":         "abc123\n0ld\n",
+		"docker ps -a --filter=name=k8s_etcd --format={{.ID}}":                   "",
+		"docker logs --timestamps --since=600s --tail=5 abc123":                  "2018-03-02T14:13:20Z serving\n",
+		"sudo journalctl --no-pager -o short-unix --since=-600s -n 5 -u kubelet": "1520000001.000000 minikube kubelet[1]: started\n",
+	})
+	k := KubeadmBootstrapper{c: f}
+
+	var tests = []struct {
+		description string
+		components  []string
+		expected    string
+		shouldErr   bool
+	}{
+		{
+			description: "apiserver and kubelet",
+			components:  []string{"apiserver", "kubelet"},
+			expected: "[apiserver] 2018-03-02 14:13:20.000000 serving\n" +
+				"[kubelet] 2018-03-02 14:13:21.000000 minikube kubelet[1]: started\n",
+		},
+		{
+			description: "missing container skipped",
+			components:  []string{"etcd", "kubelet"},
+			expected:    "[kubelet] 2018-03-02 14:13:21.000000 minikube kubelet[1]: started\n",
+		},
+		{
+			description: "missing container",
+			components:  []string{"etcd"},
+			shouldErr:   true,
+		},
+		{
+			description: "unknown component",
+			components:  []string{"localkube"},
+			shouldErr:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var b bytes.Buffer
+			opts := bootstrapper.LogOptions{Components: test.components, Since: 10 * time.Minute, Tail: 5}
+			err := k.GetClusterLogsTo(opts, &b)
+			if err != nil && !test.shouldErr {
+				t.Fatalf("Error getting logs: %s", err)
+			}
+			if err == nil && test.shouldErr {
+				t.Fatal("Didn't get error, but expected to")
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected logs:\n%s\ngot:\n%s", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestGetClusterStatus(t *testing.T) {
+	kubeletCmd := "sudo systemctl is-active --quiet kubelet"
+	detectCmd := "sudo systemctl is-active crio.service containerd.service rkt-api.service || true"
+	healthzCmd := "curl -sS --max-time 5 --cacert /var/lib/localkube/certs/ca.crt --cert /var/lib/localkube/certs/client.crt --key /var/lib/localkube/certs/client.key https://localhost:8443/healthz"
+	containers := map[string]string{
+		"docker ps -a --filter=name=k8s_etcd --format={{.ID}}":                    "e1\n",
+		"docker inspect --format={{.State.Running}} e1":                           "true",
+		"docker ps -a --filter=name=k8s_kube-scheduler --format={{.ID}}":          "s1\n",
+		"docker inspect --format={{.State.Running}} s1":                           "true",
+		"docker ps -a --filter=name=k8s_kube-controller-manager --format={{.ID}}": "c1\n",
+		"docker inspect --format={{.State.Running}} c1":                           "true",
+		"docker ps -a --filter=name=k8s_kube-apiserver --format={{.ID}}":          "a2\na1\n",
+		detectCmd: "inactive\ninactive\ninactive\n",
+	}
+
+	var tests = []struct {
+		description string
+		cmds        map[string]string
+		apiserver   bootstrapper.ComponentStatus
+		expected    string
+	}{
+		{
+			description: "healthy",
+			cmds: map[string]string{
+				kubeletCmd: "",
+				healthzCmd: "ok",
+				"docker inspect --format={{.State.Running}} a2": "true",
+			},
+			apiserver: bootstrapper.ComponentStatus{Name: "apiserver", State: "Running"},
+			expected:  "Running",
+		},
+		{
+			description: "crash looping",
+			cmds: map[string]string{
+				kubeletCmd: "",
+				"docker inspect --format={{.State.Running}} a2": "false",
+				"docker logs --timestamps --tail=1 a2":          "2018-03-02T14:13:20Z error: unable to load server certificate\n",
+			},
+			apiserver: bootstrapper.ComponentStatus{Name: "apiserver", State: "Stopped", Error: "error: unable to load server certificate"},
+			expected:  "Stopped",
+		},
+		{
+			description: "unhealthy",
+			cmds: map[string]string{
+				kubeletCmd: "",
+				healthzCmd: "[-]etcd failed: reason withheld\nhealthz check failed",
+				"docker inspect --format={{.State.Running}} a2": "true",
+			},
+			apiserver: bootstrapper.ComponentStatus{Name: "apiserver", State: "Error", Error: "[-]etcd failed: reason withheld\nhealthz check failed"},
+			expected:  "Error",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			f := bootstrapper.NewFakeCommandRunner()
+			f.SetCommandToOutput(containers)
+			f.SetCommandToOutput(test.cmds)
+			k := KubeadmBootstrapper{c: f}
+			cs, err := k.GetClusterStatus()
+			if err != nil {
+				t.Fatalf("Error getting status: %s", err)
+			}
+			if cs.Cluster != "Running" || cs.APIServer != test.expected {
+				t.Errorf("Expected cluster Running and apiserver %s, got %+v", test.expected, cs)
+			}
+			if len(cs.Components) != 5 || cs.Components[1] != test.apiserver {
+				t.Fatalf("Expected apiserver status %+v, got %+v", test.apiserver, cs.Components)
+			}
+			for _, c := range cs.Components[2:] {
+				if c.State != "Running" {
+					t.Errorf("Expected %s to be running, got %+v", c.Name, c)
+				}
+			}
+		})
+	}
+}
+
+func TestGetClusterStatusStopped(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToExitCode(map[string]int{"sudo systemctl is-active --quiet kubelet": 3})
+	k := KubeadmBootstrapper{c: f}
+	cs, err := k.GetClusterStatus()
+	if err != nil {
+		t.Fatalf("Error getting status: %s", err)
+	}
+	if cs.Cluster != "Stopped" || cs.APIServer != "Stopped" {
+		t.Errorf("Expected cluster and apiserver Stopped, got %+v", cs)
+	}
+}
+
+func TestRotateCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		backupFilesCmd(kubeadmCertPaths(true)):   "",
+		removeBackupsCmd(kubeadmCertPaths(true)): "",
+		`
+sudo kubeadm alpha phase certs all --config /var/lib/kubeadm.yaml &&
+sudo /usr/bin/kubeadm alpha phase kubeconfig all --config /var/lib/kubeadm.yaml &&
+sudo /usr/bin/kubeadm alpha phase controlplane all --config /var/lib/kubeadm.yaml &&
+sudo /usr/bin/kubeadm alpha phase etcd local --config /var/lib/kubeadm.yaml
+`: "",
+		"sudo systemctl restart kubelet":                                          "",
+		"docker ps -a --filter=name=k8s_etcd --format={{.ID}}":                    "e1\n",
+		"docker ps -a --filter=name=k8s_kube-scheduler --format={{.ID}}":          "",
+		"docker ps -a --filter=name=k8s_kube-controller-manager --format={{.ID}}": "c1\n",
+		"docker ps -a --filter=name=k8s_kube-apiserver --format={{.ID}}":          "a2\na1\n",
+		"docker stop e1": "",
+		"docker stop c1": "",
+		"docker stop a2": "",
+	})
+	k := KubeadmBootstrapper{c: f}
+	k8s := bootstrapper.KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	if err := k.RotateCerts(k8s, true); err != nil {
+		t.Fatalf("Error rotating certs: %s", err)
+	}
+	if _, err := f.GetFileToContents(filepath.Join(tempDir, "ca.crt")); err != nil {
+		t.Errorf("CA was not copied: %s", err)
+	}
+}
+
+func TestRotateCertsFailure(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	k8s := bootstrapper.KubernetesConfig{
+		APIServerName: constants.APIServerName,
+		DNSDomain:     constants.ClusterDNSDomain,
+		ServiceCIDR:   util.DefaultServiceCIDR,
+	}
+	f := bootstrapper.NewFakeCommandRunner()
+	if err := bootstrapper.SetupCerts(f, k8s); err != nil {
+		t.Fatalf("Error setting up certs: %s", err)
+	}
+	caPath := filepath.Join(tempDir, "ca.crt")
+	ca, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		t.Fatalf("Error reading CA: %s", err)
+	}
+
+	// kubeadm fails to reissue its certs
+	f.SetCommandToOutput(map[string]string{
+		backupFilesCmd(kubeadmCertPaths(true)):  "",
+		restoreFilesCmd(kubeadmCertPaths(true)): "",
+	})
+	k := KubeadmBootstrapper{c: f}
+	err = k.RotateCerts(k8s, true)
+	if err == nil {
+		t.Fatalf("Expected an error rotating certs")
+	}
+	if strings.Contains(err.Error(), "restoring") {
+		t.Fatalf("Error restoring the previous certs: %s", err)
+	}
+
+	restored, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		t.Fatalf("Error reading CA: %s", err)
+	}
+	if string(restored) != string(ca) {
+		t.Errorf("Expected the previous CA to be restored")
+	}
+	copied, err := f.GetFileToContents(caPath)
+	if err != nil || copied != string(ca) {
+		t.Errorf("Expected the previous CA to be copied to the VM again")
+	}
+	if _, err := os.Stat(caPath + bootstrapper.CertBackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("Expected the CA backup to be removed")
+	}
+}
+
+func TestKubeadmCertPaths(t *testing.T) {
+	leaf := strings.Join(kubeadmCertPaths(false), " ")
+	for _, p := range []string{
+		"/var/lib/localkube/certs/apiserver-kubelet-client.crt",
+		"/var/lib/localkube/certs/front-proxy-client.key",
+		"/var/lib/kubelet/pki/kubelet.crt",
+		"/etc/kubernetes/admin.conf",
+	} {
+		if !strings.Contains(leaf, p) {
+			t.Errorf("Expected %q to include %s", leaf, p)
+		}
+	}
+	if strings.Contains(leaf, "front-proxy-ca") {
+		t.Errorf("Expected %q to keep the CAs", leaf)
+	}
+	if all := strings.Join(kubeadmCertPaths(true), " "); !strings.Contains(all, "/var/lib/localkube/certs/front-proxy-ca.key") {
+		t.Errorf("Expected %q to include the CAs", all)
+	}
+}
+
+func TestCheckCerts(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	caPath := filepath.Join(tempDir, "ca.crt")
+	if err := util.GenerateCACert(caPath, filepath.Join(tempDir, "ca.key"), "minikubeCA"); err != nil {
+		t.Fatalf("Error generating CA: %s", err)
+	}
+	ca, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		t.Fatalf("Error reading CA: %s", err)
+	}
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+users:
+- name: system:node:minikube
+  user:
+    client-certificate-data: %s
+- name: token-user
+  user:
+    token: abc
+`, base64.StdEncoding.EncodeToString(ca))
+
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo find /var/lib/localkube/certs/ /var/lib/kubelet/pki /etc/kubernetes -maxdepth 2 -name *.crt -o -name *.conf -o -name kubelet-client-current.pem": "/var/lib/kubelet/pki/kubelet.crt\n/etc/kubernetes/kubelet.conf\n",
+		"sudo cat /var/lib/kubelet/pki/kubelet.crt": string(ca),
+		"sudo cat /etc/kubernetes/kubelet.conf":     kubeconfig,
+	})
+	k := KubeadmBootstrapper{c: f}
+	infos, err := k.CheckCerts("minikube")
+	if err != nil {
+		t.Fatalf("Error checking certs: %s", err)
+	}
+	var paths []string
+	for _, info := range infos {
+		paths = append(paths, info.Path)
+	}
+	expected := []string{
+		"minikube:/etc/kubernetes/kubelet.conf (system:node:minikube)",
+		"minikube:/var/lib/kubelet/pki/kubelet.crt",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected certificates %v, got %v", expected, paths)
+	}
+}
@@ -46,7 +46,7 @@ ExecStart=
 ExecStart=/usr/bin/kubelet {{.ExtraOptions}} {{if .FeatureGates}}--feature-gates={{.FeatureGates}}{{end}}
 
 [Install]
-{{if or (eq .ContainerRuntime "cri-o") (eq .ContainerRuntime "cri")}}Wants=crio.service{{else}}Wants=docker.socket{{end}}
+Wants={{.RuntimeService}}
 `))
 
 const kubeletService = `
@@ -71,8 +71,22 @@ sudo /usr/bin/kubeadm alpha phase controlplane all --config {{.KubeadmConfigFile
 sudo /usr/bin/kubeadm alpha phase etcd local --config {{.KubeadmConfigFile}}
 `))
 
+var kubeadmUpgradePlanTemplate = template.Must(template.New("kubeadmUpgradePlanTemplate").Parse("sudo /usr/bin/kubeadm upgrade plan --config {{.KubeadmConfigFile}} --allow-experimental-upgrades --allow-release-candidate-upgrades"))
+
+var kubeadmUpgradeApplyTemplate = template.Must(template.New("kubeadmUpgradeApplyTemplate").Parse("sudo /usr/bin/kubeadm upgrade apply {{.KubernetesVersion}} --config {{.KubeadmConfigFile}} --yes --force --allow-experimental-upgrades --allow-release-candidate-upgrades"))
+
 var kubeadmInitTemplate = template.Must(template.New("kubeadmInitTemplate").Parse("sudo /usr/bin/kubeadm init --config {{.KubeadmConfigFile}} --skip-preflight-checks"))
 
+// kubeadmJoinTemplate runs the output of kubeadmTokenCreateCmd, which starts with "kubeadm join"
+var kubeadmJoinTemplate = template.Must(template.New("kubeadmJoinTemplate").Parse("sudo /usr/bin/{{.JoinCommand}} --skip-preflight-checks"))
+
+const kubeadmTokenCreateCmd = "sudo /usr/bin/kubeadm token create --print-join-command"
+
+const kubeadmResetCmd = "sudo /usr/bin/kubeadm reset"
+
+// kubeletRestartCmd makes the kubelet reload its kubeconfig and client CA
+const kubeletRestartCmd = "sudo systemctl restart kubelet"
+
 // printMapInOrder sorts the keys and prints the map in order, combining key
 // value pairs with the separator character
 //
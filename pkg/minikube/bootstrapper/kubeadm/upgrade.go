@@ -0,0 +1,177 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	clientv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+// controlPlaneComponents are the static pods upgraded by kubeadm upgrade apply
+var controlPlaneComponents = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler"}
+
+// UpgradeCluster upgrades the control plane from the version in current to
+// the version in target with kubeadm upgrade, then upgrades the kubelet.
+// Progress is written to out.
+func (k *KubeadmBootstrapper) UpgradeCluster(current, target bootstrapper.KubernetesConfig, out io.Writer) error {
+	from, err := ParseKubernetesVersion(current.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing current kubernetes version")
+	}
+	to, err := ParseKubernetesVersion(target.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing target kubernetes version")
+	}
+	if err := CheckUpgradeVersionSkew(from, to); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Upgrading kubeadm to %s...\n", target.KubernetesVersion)
+	if err := k.copyBinary("kubeadm", target.KubernetesVersion); err != nil {
+		return errors.Wrap(err, "upgrading kubeadm")
+	}
+	kubeadmCfg, err := generateConfig(target)
+	if err != nil {
+		return errors.Wrap(err, "generating kubeadm cfg")
+	}
+	if err := k.c.Copy(assets.NewMemoryAssetTarget([]byte(kubeadmCfg), constants.KubeadmConfigFile, "0640")); err != nil {
+		return errors.Wrap(err, "transferring kubeadm config")
+	}
+
+	opts := struct {
+		KubeadmConfigFile string
+		KubernetesVersion string
+	}{
+		KubeadmConfigFile: constants.KubeadmConfigFile,
+		KubernetesVersion: target.KubernetesVersion,
+	}
+	fmt.Fprintln(out, "Checking upgrade plan...")
+	if err := k.runTemplateTo(kubeadmUpgradePlanTemplate, opts, out); err != nil {
+		return errors.Wrap(err, "kubeadm upgrade plan")
+	}
+	fmt.Fprintf(out, "Upgrading control plane to %s...\n", target.KubernetesVersion)
+	if err := k.runTemplateTo(kubeadmUpgradeApplyTemplate, opts, out); err != nil {
+		return errors.Wrap(err, "kubeadm upgrade apply")
+	}
+
+	fmt.Fprintf(out, "Upgrading kubelet to %s...\n", target.KubernetesVersion)
+	if err := k.upgradeKubelet(target); err != nil {
+		return errors.Wrap(err, "upgrading kubelet")
+	}
+
+	client, err := util.GetClient()
+	if err != nil {
+		return errors.Wrap(err, "getting k8s client")
+	}
+	for _, component := range controlPlaneComponents {
+		fmt.Fprintf(out, "Waiting for %s %s...\n", component, target.KubernetesVersion)
+		if err := waitForComponentVersion(client, component, target.KubernetesVersion); err != nil {
+			return errors.Wrapf(err, "waiting for %s", component)
+		}
+		fmt.Fprintf(out, "%s is running %s\n", component, target.KubernetesVersion)
+	}
+
+	// kubeadm upgrade apply rewrites the kube-proxy configmap
+	fmt.Fprintln(out, "Restarting kube-proxy...")
+	if err := restartKubeProxy(target); err != nil {
+		return errors.Wrap(err, "restarting kube-proxy")
+	}
+	return nil
+}
+
+// UpgradeNode upgrades the kubelet of a worker node named nodeName.
+func (k *KubeadmBootstrapper) UpgradeNode(cfg bootstrapper.KubernetesConfig, nodeName string) error {
+	if err := k.UpdateNode(cfg, nodeName); err != nil {
+		return errors.Wrap(err, "updating node")
+	}
+	if err := k.c.Run("sudo systemctl restart kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
+
+// upgradeKubelet installs the kubelet and its configuration for the new
+// version and restarts it.
+func (k *KubeadmBootstrapper) upgradeKubelet(cfg bootstrapper.KubernetesConfig) error {
+	kubeletCfg, err := NewKubeletConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "generating kubelet config")
+	}
+	if err := k.copyBinary("kubelet", cfg.KubernetesVersion); err != nil {
+		return err
+	}
+	if err := k.c.Copy(assets.NewMemoryAssetTarget([]byte(kubeletCfg), constants.KubeletSystemdConfFile, "0640")); err != nil {
+		return errors.Wrap(err, "transferring kubelet config")
+	}
+	return k.c.Run(`
+sudo systemctl daemon-reload &&
+sudo systemctl restart kubelet
+`)
+}
+
+// runTemplateTo runs the command rendered from t, streaming its output to out.
+func (k *KubeadmBootstrapper) runTemplateTo(t *template.Template, opts interface{}, out io.Writer) error {
+	b := bytes.Buffer{}
+	if err := t.Execute(&b, opts); err != nil {
+		return err
+	}
+	if err := k.c.CombinedOutputTo(b.String(), out); err != nil {
+		return errors.Wrapf(err, "running cmd: %s", b.String())
+	}
+	return nil
+}
+
+// waitForComponentVersion waits until every pod of a control plane component
+// runs the given version.
+func waitForComponentVersion(client kubernetes.Interface, component, version string) error {
+	selector := labels.SelectorFromSet(labels.Set(map[string]string{"component": component}))
+	check := func() error {
+		pods, err := client.CoreV1().Pods("kube-system").List(metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return err
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Errorf("no %s pods found", component)
+		}
+		for _, p := range pods.Items {
+			if p.Status.Phase != clientv1.PodRunning {
+				return fmt.Errorf("pod %s is %s", p.Name, p.Status.Phase)
+			}
+			for _, c := range p.Spec.Containers {
+				if !strings.HasSuffix(c.Image, ":"+version) {
+					return fmt.Errorf("pod %s runs %s", p.Name, c.Image)
+				}
+			}
+		}
+		return nil
+	}
+	return util.RetryAfter(90, check, 2*time.Second)
+}
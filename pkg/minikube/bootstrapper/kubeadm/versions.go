@@ -212,6 +212,31 @@ func VersionIsBetween(version, gte, lte semver.Version) bool {
 	return true
 }
 
+// MinimumUpgradeVersion is the oldest version a cluster can be upgraded from,
+// kubeadm upgrade was introduced in 1.8
+var MinimumUpgradeVersion = semver.MustParse("1.8.0")
+
+// CheckUpgradeVersionSkew returns an error if kubeadm cannot upgrade a cluster
+// from one version to another in a single step. Only upgrades to a newer patch
+// release or to the next minor release are supported.
+func CheckUpgradeVersionSkew(from, to semver.Version) error {
+	if from.LT(MinimumUpgradeVersion) {
+		return fmt.Errorf("clusters running %s cannot be upgraded, the minimum version is %s", from, MinimumUpgradeVersion)
+	}
+	if !to.GT(from) {
+		return fmt.Errorf("version %s is not newer than the running version %s", to, from)
+	}
+	if to.Major != from.Major || to.Minor > from.Minor+1 {
+		return fmt.Errorf("cannot upgrade from %s to %s, upgrade one minor version at a time", from, to)
+	}
+	for _, component := range []string{Kubelet, Apiserver, ControllerManager, Scheduler} {
+		if _, err := DefaultOptionsForComponentAndVersion(component, to); err != nil {
+			return errors.Wrapf(err, "default options for %s %s", component, to)
+		}
+	}
+	return nil
+}
+
 func DefaultOptionsForComponentAndVersion(component string, version semver.Version) (map[string]string, error) {
 	versionedOpts := map[string]string{}
 	for _, opts := range versionSpecificOpts {
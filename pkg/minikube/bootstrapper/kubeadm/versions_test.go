@@ -101,3 +101,64 @@ func TestParseKubernetesVersion(t *testing.T) {
 		t.Errorf("Expected: %s, Actual:%s", "1.8.0-alpha.5", version)
 	}
 }
+
+func TestCheckUpgradeVersionSkew(t *testing.T) {
+	tests := []struct {
+		description string
+		from        string
+		to          string
+		shouldErr   bool
+	}{
+		{
+			description: "patch upgrade",
+			from:        "1.9.0",
+			to:          "1.9.4",
+		},
+		{
+			description: "minor upgrade",
+			from:        "1.9.4",
+			to:          "1.10.0",
+		},
+		{
+			description: "minor upgrade to pre-release",
+			from:        "1.9.4",
+			to:          "1.10.0-beta.1",
+		},
+		{
+			description: "skipping a minor version",
+			from:        "1.8.0",
+			to:          "1.10.0",
+			shouldErr:   true,
+		},
+		{
+			description: "same version",
+			from:        "1.9.0",
+			to:          "1.9.0",
+			shouldErr:   true,
+		},
+		{
+			description: "downgrade",
+			from:        "1.9.0",
+			to:          "1.8.5",
+			shouldErr:   true,
+		},
+		{
+			description: "too old to upgrade",
+			from:        "1.7.5",
+			to:          "1.8.0",
+			shouldErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := CheckUpgradeVersionSkew(semver.MustParse(test.from), semver.MustParse(test.to))
+			if err != nil && !test.shouldErr {
+				t.Errorf("Unexpected error upgrading from %s to %s: %s", test.from, test.to, err)
+			}
+			if err == nil && test.shouldErr {
+				t.Errorf("Expected an error upgrading from %s to %s", test.from, test.to)
+			}
+		})
+	}
+}
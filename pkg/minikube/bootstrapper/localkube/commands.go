@@ -186,19 +186,24 @@ func GenLocalkubeStartCmd(kubernetesConfig bootstrapper.KubernetesConfig) (strin
 }
 
 const logsTemplate = "if [[ `systemctl` =~ -\\.mount ]] &>/dev/null; " + `then
-  sudo journalctl {{.Flags}} -u localkube
+  {{.JournalCommand}}
 else
-  tail -n +1 {{.Flags}} {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} 
+  tail {{.Flags}} {{.RemoteLocalkubeErrPath}} {{.RemoteLocalkubeOutPath}} 
 fi
 `
 
-func GetLogsCommand(follow bool) (string, error) {
+// GetLogsCommand returns the command printing the localkube logs selected by
+// opts. Without systemd, the logs have no timestamps and --since is ignored.
+func GetLogsCommand(opts bootstrapper.LogOptions) (string, error) {
 	t, err := template.New("logsTemplate").Parse(logsTemplate)
 	if err != nil {
 		return "", err
 	}
-	var flags []string
-	if follow {
+	flags := []string{"-n +1"}
+	if tail := opts.CommandTail(); tail > 0 {
+		flags = []string{fmt.Sprintf("-n %d", tail)}
+	}
+	if opts.Follow {
 		flags = append(flags, "-f")
 	}
 
@@ -206,10 +211,12 @@ func GetLogsCommand(follow bool) (string, error) {
 	data := struct {
 		RemoteLocalkubeErrPath string
 		RemoteLocalkubeOutPath string
+		JournalCommand         string
 		Flags                  string
 	}{
 		RemoteLocalkubeErrPath: constants.RemoteLocalKubeErrPath,
 		RemoteLocalkubeOutPath: constants.RemoteLocalKubeOutPath,
+		JournalCommand:         bootstrapper.JournalLogsCommand("localkube", opts),
 		Flags: strings.Join(flags, " "),
 	}
 	if err := t.Execute(&buf, data); err != nil {
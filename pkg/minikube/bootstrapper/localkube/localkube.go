@@ -25,6 +25,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/machine"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 
@@ -47,7 +48,7 @@ func NewLocalkubeBootstrapper(api libmachine.API) (*LocalkubeBootstrapper, error
 	if h.Driver.DriverName() == constants.DriverNone {
 		cmd = &bootstrapper.ExecRunner{}
 	} else {
-		client, err := sshutil.NewSSHClient(h.Driver)
+		client, err := sshutil.GetClient(h.Driver)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting ssh client")
 		}
@@ -58,43 +59,53 @@ func NewLocalkubeBootstrapper(api libmachine.API) (*LocalkubeBootstrapper, error
 	}, nil
 }
 
-// GetClusterLogs
-// If follow is specified, it will tail the logs
-func (lk *LocalkubeBootstrapper) GetClusterLogsTo(follow bool, out io.Writer) error {
-	logsCommand, err := GetLogsCommand(follow)
+// GetClusterLogsTo writes the logs of localkube and of the container runtime
+// selected by opts to out.
+func (lk *LocalkubeBootstrapper) GetClusterLogsTo(opts bootstrapper.LogOptions, out io.Writer) error {
+	r, err := cruntime.New(opts.ContainerRuntime)
 	if err != nil {
-		return errors.Wrap(err, "Error getting logs command")
+		return errors.Wrap(err, "runtime")
 	}
-
-	if follow {
-		err = lk.cmd.CombinedOutputTo(logsCommand, out)
-		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
+	components, err := bootstrapper.SelectLogComponents(opts, []string{"localkube", r.Name()})
+	if err != nil {
+		return err
+	}
+	commands := map[string]string{}
+	for _, c := range components {
+		if c == r.Name() {
+			commands[c] = bootstrapper.JournalLogsCommand(strings.TrimSuffix(r.ServiceName(), ".socket"), opts)
+			continue
 		}
-	} else {
-		logs, err := lk.cmd.CombinedOutput(logsCommand)
+		commands[c], err = GetLogsCommand(opts)
 		if err != nil {
-			return errors.Wrap(err, "getting cluster logs")
+			return errors.Wrap(err, "Error getting logs command")
 		}
-		fmt.Fprint(out, logs)
 	}
-	return nil
+	return bootstrapper.WriteLogsTo(lk.cmd, commands, opts, out)
 }
 
-// GetClusterStatus gets the status of localkube from the host VM.
-func (lk *LocalkubeBootstrapper) GetClusterStatus() (string, error) {
+// GetClusterStatus gets the status of localkube from the host VM, and of the
+// apiserver it runs as reported by its healthz endpoint.
+func (lk *LocalkubeBootstrapper) GetClusterStatus() (bootstrapper.ClusterStatus, error) {
+	var cs bootstrapper.ClusterStatus
 	s, err := lk.cmd.CombinedOutput(localkubeStatusCommand)
 	if err != nil {
-		return "", err
+		return cs, err
 	}
 	s = strings.TrimSpace(s)
-	if state.Running.String() == s {
-		return state.Running.String(), nil
-	} else if state.Stopped.String() == s {
-		return state.Stopped.String(), nil
-	} else {
-		return "", fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
+	if state.Running.String() != s && state.Stopped.String() != s {
+		return cs, fmt.Errorf("Error: Unrecognize output from GetLocalkubeStatus: %s", s)
 	}
+	cs.Cluster = s
+	cs.Components = []bootstrapper.ComponentStatus{{Name: "localkube", State: s}}
+	if s != state.Running.String() {
+		cs.APIServer = state.Stopped.String()
+		return cs, nil
+	}
+	apiserver := bootstrapper.APIServerHealth(lk.cmd)
+	cs.APIServer = apiserver.State
+	cs.Components = append(cs.Components, apiserver)
+	return cs, nil
 }
 
 // StartCluster starts a k8s cluster on the specified Host.
@@ -114,15 +125,28 @@ func (lk *LocalkubeBootstrapper) RestartCluster(kubernetesConfig bootstrapper.Ku
 	return lk.StartCluster(kubernetesConfig)
 }
 
+// RotateCerts reissues the certs and restarts localkube to use them
+func (lk *LocalkubeBootstrapper) RotateCerts(k8s bootstrapper.KubernetesConfig, rotateCA bool) error {
+	if err := bootstrapper.RotateCerts(lk.cmd, k8s, rotateCA, nil); err != nil {
+		return errors.Wrap(err, "rotating certs")
+	}
+	return lk.RestartCluster(k8s)
+}
+
 func (lk *LocalkubeBootstrapper) UpdateCluster(config bootstrapper.KubernetesConfig) error {
+	r, err := cruntime.New(config.ContainerRuntime)
+	if err != nil {
+		return err
+	}
+	if err := cruntime.Enable(lk.cmd, r); err != nil {
+		return errors.Wrapf(err, "enabling %s", r.Name())
+	}
 	if config.ShouldLoadCachedImages {
-		// Make best effort to load any cached images
-		go machine.LoadImages(lk.cmd, constants.LocalkubeCachedImages, constants.ImageCacheDir)
+		machine.LoadCachedImages(lk.cmd, r, bootstrapper.GetCachedImageList(config.KubernetesVersion, bootstrapper.BootstrapperTypeLocalkube))
 	}
 
 	copyableFiles := []assets.CopyableFile{}
 	var localkubeFile assets.CopyableFile
-	var err error
 
 	//add url/file/bundled localkube to file list
 	lCacher := localkubeCacher{config}
@@ -137,22 +161,19 @@ func (lk *LocalkubeBootstrapper) UpdateCluster(config bootstrapper.KubernetesCon
 		return errors.Wrap(err, "adding minikube dir assets")
 	}
 	// bundled addons
-	for _, addonBundle := range assets.Addons {
+	for addonName, addonBundle := range assets.Addons {
 		if isEnabled, err := addonBundle.IsEnabled(); err == nil && isEnabled {
-			for _, addon := range addonBundle.Assets {
-				copyableFiles = append(copyableFiles, addon)
+			addonFiles, err := addonBundle.RenderedAssets()
+			if err != nil {
+				return errors.Wrapf(err, "rendering addon %s", addonName)
 			}
+			copyableFiles = append(copyableFiles, addonFiles...)
 		} else if err != nil {
 			return err
 		}
 	}
 
-	for _, f := range copyableFiles {
-		if err := lk.cmd.Copy(f); err != nil {
-			return err
-		}
-	}
-	return nil
+	return lk.cmd.CopyAll(copyableFiles)
 }
 
 func (lk *LocalkubeBootstrapper) SetupCerts(k8s bootstrapper.KubernetesConfig) error {
@@ -17,18 +17,13 @@ limitations under the License.
 package localkube
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"crypto"
 	"fmt"
-	"io"
-	"io/ioutil"
 	"net/url"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/golang/glog"
-	download "github.com/jimmidyson/go-download"
 	"github.com/pkg/errors"
 
 	"k8s.io/minikube/pkg/minikube/assets"
@@ -47,56 +42,15 @@ func (l *localkubeCacher) getLocalkubeCacheFilepath() string {
 		filepath.Base(url.QueryEscape("localkube-"+l.k8sConf.KubernetesVersion)))
 }
 
-func (l *localkubeCacher) getLocalkubeSha256CacheFilepath() string {
-	return l.getLocalkubeCacheFilepath() + ".sha256"
-}
-
 func localkubeURIWasSpecified(config bootstrapper.KubernetesConfig) bool {
 	// see if flag is different than default -> it was passed by user
 	return config.KubernetesVersion != constants.DefaultKubernetesVersion
 }
 
+// isLocalkubeCached returns whether localkube was completely downloaded and
+// matched its published checksum.
 func (l *localkubeCacher) isLocalkubeCached() bool {
-	url, err := util.GetLocalkubeDownloadURL(l.k8sConf.KubernetesVersion, constants.LocalkubeLinuxFilename)
-	if err != nil {
-		glog.Warningf("Unable to get localkube checksum url...continuing.")
-		return true
-	}
-	opts := download.FileOptions{
-		Mkdirs: download.MkdirAll,
-	}
-
-	if err := download.ToFile(url+".sha256", l.getLocalkubeSha256CacheFilepath(), opts); err != nil {
-		glog.Warningf("Unable to check localkube checksum... continuing.")
-		return true
-	}
-
-	if _, err := os.Stat(l.getLocalkubeCacheFilepath()); os.IsNotExist(err) {
-		return false
-	}
-
-	localkubeSha256, err := ioutil.ReadFile(l.getLocalkubeSha256CacheFilepath())
-	if err != nil {
-		glog.Infof("Error reading localkube checksum: %s", err)
-		return false
-	}
-
-	h := sha256.New()
-	f, err := os.Open(l.getLocalkubeCacheFilepath())
-	if err != nil {
-		glog.Infof("Error opening localkube for checksum verification: %s", err)
-		return false
-	}
-	if _, err := io.Copy(h, f); err != nil {
-		glog.Infof("Error copying contents to hasher: %s", err)
-	}
-
-	actualChecksum := hex.EncodeToString(h.Sum(nil))
-	if strings.TrimSpace(string(localkubeSha256)) != actualChecksum {
-		glog.Infof("Localkube checksums do not match actual: %s expected: %s", actualChecksum, string(localkubeSha256))
-		return false
-	}
-	return true
+	return util.IsCached(l.getLocalkubeCacheFilepath())
 }
 
 func (l *localkubeCacher) downloadAndCacheLocalkube() error {
@@ -104,21 +58,15 @@ func (l *localkubeCacher) downloadAndCacheLocalkube() error {
 	if err != nil {
 		return errors.Wrap(err, "Error getting localkube download url")
 	}
-	opts := download.FileOptions{
-		Mkdirs: download.MkdirAll,
-		Options: download.Options{
-			ProgressBars: &download.ProgressBarOptions{
-				MaxWidth: 80,
-			},
-		},
+	opts := util.DownloadOptions{
+		ChecksumURL:  url + util.ChecksumSuffix,
+		ChecksumHash: crypto.SHA256,
+		ShowProgress: true,
 	}
 	fmt.Println("Downloading localkube binary")
-	if err := download.ToFile(url, l.getLocalkubeCacheFilepath(), opts); err != nil {
+	if err := util.DownloadToCache(url, l.getLocalkubeCacheFilepath(), opts); err != nil {
 		return errors.Wrap(err, "downloading localkube")
 	}
-	if err := download.ToFile(url+".sha256", l.getLocalkubeSha256CacheFilepath(), opts); err != nil {
-		return errors.Wrap(err, "downloading localkube checksum")
-	}
 
 	return nil
 }
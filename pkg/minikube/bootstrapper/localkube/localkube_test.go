@@ -98,6 +98,10 @@ func TestUpdateCluster(t *testing.T) {
 		t.Run(test.description, func(t *testing.T) {
 			t.Parallel()
 			f := bootstrapper.NewFakeCommandRunner()
+			f.SetCommandToOutput(map[string]string{
+				"sudo systemctl is-active crio.service containerd.service rkt-api.service || true": "inactive\ninactive\ninactive\n",
+				"sudo systemctl start docker": "",
+			})
 			l := LocalkubeBootstrapper{f}
 			err := l.UpdateCluster(test.k8s)
 			if err != nil && !test.shouldErr {
@@ -153,7 +157,8 @@ func TestGetLocalkubeStatus(t *testing.T) {
 			f := bootstrapper.NewFakeCommandRunner()
 			f.SetCommandToOutput(test.statusCmdMap)
 			l := LocalkubeBootstrapper{f}
-			actualStatus, err := l.GetClusterStatus()
+			cs, err := l.GetClusterStatus()
+			actualStatus := cs.Cluster
 			if err != nil && !test.shouldErr {
 				t.Errorf("Error getting localkube status: %s", err)
 				return
@@ -170,11 +175,13 @@ func TestGetLocalkubeStatus(t *testing.T) {
 }
 
 func TestGetHostLogs(t *testing.T) {
-	logs, err := GetLogsCommand(false)
+	opts := bootstrapper.LogOptions{Components: []string{"localkube"}}
+	logs, err := GetLogsCommand(opts)
 	if err != nil {
 		t.Fatalf("Error getting logs command: %s", err)
 	}
-	logsf, err := GetLogsCommand(true)
+	optsf := bootstrapper.LogOptions{Components: []string{"localkube"}, Follow: true}
+	logsf, err := GetLogsCommand(optsf)
 	if err != nil {
 		t.Fatalf("Error gettings logs -f command: %s", err)
 	}
@@ -182,21 +189,23 @@ func TestGetHostLogs(t *testing.T) {
 	cases := []struct {
 		description string
 		logsCmdMap  map[string]string
-		follow      bool
+		opts        bootstrapper.LogOptions
 		shouldErr   bool
 	}{
 		{
 			description: "get logs correct",
 			logsCmdMap:  map[string]string{logs: "fee"},
+			opts:        opts,
 		},
 		{
 			description: "follow logs correct",
 			logsCmdMap:  map[string]string{logsf: "fi"},
-			follow:      true,
+			opts:        optsf,
 		},
 		{
 			description: "get logs incorrect",
 			logsCmdMap:  map[string]string{"fo": "fum"},
+			opts:        opts,
 			shouldErr:   true,
 		},
 	}
@@ -208,7 +217,7 @@ func TestGetHostLogs(t *testing.T) {
 			f := bootstrapper.NewFakeCommandRunner()
 			f.SetCommandToOutput(test.logsCmdMap)
 			l := LocalkubeBootstrapper{f}
-			err := l.GetClusterLogsTo(test.follow, &b)
+			err := l.GetClusterLogsTo(test.opts, &b)
 			if err != nil && !test.shouldErr {
 				t.Errorf("Error getting localkube logs: %s", err)
 				return
@@ -0,0 +1,269 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LogComponentAll selects the logs of every component
+const LogComponentAll = "all"
+
+// LogOptions selects the cluster logs written by GetClusterLogsTo
+type LogOptions struct {
+	// Components are the components whose logs are written, all of them if
+	// empty or containing LogComponentAll
+	Components []string
+	// Follow continuously writes new log entries
+	Follow bool
+	// Since only writes the entries of the last Since, if non-zero
+	Since time.Duration
+	// Tail only writes the last Tail entries, if non-zero
+	Tail int
+	// Grep only writes the entries matching Grep, if not nil
+	Grep *regexp.Regexp
+	// ContainerRuntime is the container runtime of the cluster
+	ContainerRuntime string
+}
+
+// CommandTail returns how many of the most recent lines the log command of a
+// component prints. The entries are filtered by Grep before keeping the last
+// Tail of them, which needs every line, unless following: the entries written
+// as they arrive cannot be tailed.
+func (o LogOptions) CommandTail() int {
+	if o.Grep != nil && !o.Follow {
+		return 0
+	}
+	return o.Tail
+}
+
+// SelectLogComponents returns the components of available selected by opts,
+// or an error if opts selects a component that is not available.
+func SelectLogComponents(opts LogOptions, available []string) ([]string, error) {
+	if len(opts.Components) == 0 {
+		return available, nil
+	}
+	var selected []string
+	for _, c := range opts.Components {
+		if c == LogComponentAll {
+			return available, nil
+		}
+		found := false
+		for _, a := range available {
+			if a == c {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown log component %q, must be one of: %s", c, strings.Join(append(available, LogComponentAll), ", "))
+		}
+		selected = append(selected, c)
+	}
+	return selected, nil
+}
+
+// JournalLogsCommand returns the command printing the journal of a systemd
+// unit selected by opts, with timestamps that ParseLogLine understands.
+func JournalLogsCommand(unit string, opts LogOptions) string {
+	flags := []string{"--no-pager", "-o short-unix"}
+	if opts.Follow {
+		flags = append(flags, "-f")
+	}
+	if opts.Since > 0 {
+		flags = append(flags, fmt.Sprintf("--since=-%ds", int(opts.Since.Seconds())))
+	}
+	if tail := opts.CommandTail(); tail > 0 {
+		flags = append(flags, fmt.Sprintf("-n %d", tail))
+	}
+	return fmt.Sprintf("sudo journalctl %s -u %s", strings.Join(flags, " "), unit)
+}
+
+// logEntry is a line of the logs of a component
+type logEntry struct {
+	component string
+	time      time.Time
+	text      string
+}
+
+func (e logEntry) String() string {
+	if e.time.IsZero() {
+		return fmt.Sprintf("[%s] %s", e.component, e.text)
+	}
+	return fmt.Sprintf("[%s] %s %s", e.component, e.time.UTC().Format("2006-01-02 15:04:05.000000"), e.text)
+}
+
+var unixTimestamp = regexp.MustCompile(`^(\d+)\.(\d{6}) `)
+
+// ParseLogLine splits a log line printed by journalctl -o short-unix, or by
+// docker and crictl logs --timestamps, into its timestamp and text. ok is
+// false if the line does not start with a timestamp.
+func ParseLogLine(line string) (t time.Time, text string, ok bool) {
+	if m := unixTimestamp.FindStringSubmatch(line); m != nil {
+		sec, _ := strconv.ParseInt(m[1], 10, 64)
+		usec, _ := strconv.ParseInt(m[2], 10, 64)
+		return time.Unix(sec, usec*1000), line[len(m[0]):], true
+	}
+	fields := strings.SplitN(line, " ", 2)
+	if t, err := time.Parse(time.RFC3339Nano, fields[0]); err == nil {
+		if len(fields) == 1 {
+			return t, "", true
+		}
+		return t, fields[1], true
+	}
+	return time.Time{}, line, false
+}
+
+// parseLogs parses the output of a log command. Lines without a timestamp,
+// such as the continuation of multi-line entries, get the timestamp of the
+// previous line so that they stay together.
+func parseLogs(component, logs string, grep *regexp.Regexp) []logEntry {
+	var entries []logEntry
+	var last time.Time
+	for _, line := range strings.Split(strings.TrimRight(logs, "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		t, text, ok := ParseLogLine(line)
+		if ok {
+			last = t
+		} else {
+			t = last
+		}
+		if grep != nil && !grep.MatchString(text) {
+			continue
+		}
+		entries = append(entries, logEntry{component: component, time: t, text: text})
+	}
+	return entries
+}
+
+// WriteLogsTo runs the log command of each component, and writes their
+// output to out prefixed with the component name. Unless following, the
+// output is interleaved by timestamp. commands maps components to commands.
+func WriteLogsTo(cmd CommandRunner, commands map[string]string, opts LogOptions, out io.Writer) error {
+	var components []string
+	for c := range commands {
+		components = append(components, c)
+	}
+	sort.Strings(components)
+	if opts.Follow {
+		return followLogs(cmd, components, commands, opts, out)
+	}
+
+	var entries []logEntry
+	for _, c := range components {
+		logs, err := cmd.CombinedOutput(commands[c])
+		if err != nil {
+			return errors.Wrapf(err, "getting %s logs", c)
+		}
+		entries = append(entries, parseLogs(c, logs, opts.Grep)...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].time.Before(entries[j].time) })
+	if opts.Tail > 0 && len(entries) > opts.Tail {
+		entries = entries[len(entries)-opts.Tail:]
+	}
+	for _, e := range entries {
+		fmt.Fprintln(out, e)
+	}
+	return nil
+}
+
+// followLogs streams the output of the log commands to out as it arrives.
+func followLogs(cmd CommandRunner, components []string, commands map[string]string, opts LogOptions, out io.Writer) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []string
+	)
+	for _, c := range components {
+		c := c
+		w := &logWriter{component: c, grep: opts.Grep, mu: &mu, out: out}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := cmd.CombinedOutputTo(commands[c], w)
+			w.Flush()
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Sprintf("%s: %v", c, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return fmt.Errorf("getting logs: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// logWriter writes the complete lines written to it as log entries of a
+// component. Writers of different components share mu, so that their
+// entries are not mixed up.
+type logWriter struct {
+	component string
+	grep      *regexp.Regexp
+	mu        *sync.Mutex
+	out       io.Writer
+	buf       bytes.Buffer
+	last      time.Time
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf.Next(i + 1))
+		w.writeLine(strings.TrimSuffix(line, "\n"))
+	}
+}
+
+// Flush writes the last line if it was not terminated
+func (w *logWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.writeLine(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+func (w *logWriter) writeLine(line string) {
+	t, text, ok := ParseLogLine(line)
+	if ok {
+		w.last = t
+	} else {
+		t = w.last
+	}
+	if w.grep != nil && !w.grep.MatchString(text) {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	fmt.Fprintln(w.out, logEntry{component: w.component, time: t, text: text})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	var tests = []struct {
+		line string
+		time time.Time
+		text string
+		ok   bool
+	}{
+		{
+			line: "1520000000.123456 minikube kubelet[2481]: I0302 started",
+			time: time.Unix(1520000000, 123456000),
+			text: "minikube kubelet[2481]: I0302 started",
+			ok:   true,
+		},
+		{
+			line: "2018-03-02T14:13:20.5Z I0302 serving",
+			time: time.Date(2018, 3, 2, 14, 13, 20, 500000000, time.UTC),
+			text: "I0302 serving",
+			ok:   true,
+		},
+		{
+			line: "goroutine 1 [running]:",
+			text: "goroutine 1 [running]:",
+		},
+	}
+	for _, test := range tests {
+		tm, text, ok := ParseLogLine(test.line)
+		if !tm.Equal(test.time) || text != test.text || ok != test.ok {
+			t.Errorf("ParseLogLine(%q) = %v, %q, %t, expected %v, %q, %t", test.line, tm, text, ok, test.time, test.text, test.ok)
+		}
+	}
+}
+
+func TestWriteLogsTo(t *testing.T) {
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"kubelet logs": "1520000001.000000 kubelet one\n1520000003.000000 kubelet two\n",
+		"apiserver logs": "2018-03-02T14:13:20Z apiserver one\n" +
+			"2018-03-02T14:13:22Z apiserver panic\ngoroutine 1\n",
+	})
+	commands := map[string]string{"kubelet": "kubelet logs", "apiserver": "apiserver logs"}
+
+	var tests = []struct {
+		description string
+		opts        LogOptions
+		expected    string
+	}{
+		{
+			description: "interleaved",
+			expected: "[apiserver] 2018-03-02 14:13:20.000000 apiserver one\n" +
+				"[kubelet] 2018-03-02 14:13:21.000000 kubelet one\n" +
+				"[apiserver] 2018-03-02 14:13:22.000000 apiserver panic\n" +
+				"[apiserver] 2018-03-02 14:13:22.000000 goroutine 1\n" +
+				"[kubelet] 2018-03-02 14:13:23.000000 kubelet two\n",
+		},
+		{
+			description: "tail",
+			opts:        LogOptions{Tail: 2},
+			expected: "[apiserver] 2018-03-02 14:13:22.000000 goroutine 1\n" +
+				"[kubelet] 2018-03-02 14:13:23.000000 kubelet two\n",
+		},
+		{
+			description: "grep then tail",
+			opts:        LogOptions{Grep: regexp.MustCompile("one"), Tail: 1},
+			expected:    "[kubelet] 2018-03-02 14:13:21.000000 kubelet one\n",
+		},
+		{
+			description: "grep",
+			opts:        LogOptions{Grep: regexp.MustCompile("one")},
+			expected: "[apiserver] 2018-03-02 14:13:20.000000 apiserver one\n" +
+				"[kubelet] 2018-03-02 14:13:21.000000 kubelet one\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := WriteLogsTo(f, commands, test.opts, &b); err != nil {
+				t.Fatalf("Error writing logs: %s", err)
+			}
+			if b.String() != test.expected {
+				t.Errorf("Expected logs:\n%s\ngot:\n%s", test.expected, b.String())
+			}
+		})
+	}
+}
+
+func TestJournalLogsCommandTail(t *testing.T) {
+	var tests = []struct {
+		opts     LogOptions
+		expected string
+	}{
+		{opts: LogOptions{Tail: 5}, expected: "sudo journalctl --no-pager -o short-unix -n 5 -u kubelet"},
+		// every line is needed to keep the last 5 matching ones
+		{opts: LogOptions{Tail: 5, Grep: regexp.MustCompile("x")}, expected: "sudo journalctl --no-pager -o short-unix -u kubelet"},
+		{opts: LogOptions{Tail: 5, Grep: regexp.MustCompile("x"), Follow: true}, expected: "sudo journalctl --no-pager -o short-unix -f -n 5 -u kubelet"},
+	}
+	for _, test := range tests {
+		if cmd := JournalLogsCommand("kubelet", test.opts); cmd != test.expected {
+			t.Errorf("Expected %q, got %q", test.expected, cmd)
+		}
+	}
+}
+
+func TestFollowLogs(t *testing.T) {
+	f := NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{"kubelet logs": "1520000001.000000 kubelet one\nkubelet two"})
+
+	var b bytes.Buffer
+	opts := LogOptions{Follow: true, Grep: regexp.MustCompile("two")}
+	if err := WriteLogsTo(f, map[string]string{"kubelet": "kubelet logs"}, opts, &b); err != nil {
+		t.Fatalf("Error following logs: %s", err)
+	}
+	if expected := "[kubelet] 2018-03-02 14:13:21.000000 kubelet two\n"; b.String() != expected {
+		t.Errorf("Expected logs %q, got %q", expected, b.String())
+	}
+}
+
+func TestSelectLogComponents(t *testing.T) {
+	available := []string{"apiserver", "kubelet", "docker"}
+	var tests = []struct {
+		components []string
+		expected   []string
+		shouldErr  bool
+	}{
+		{components: nil, expected: available},
+		{components: []string{"all"}, expected: available},
+		{components: []string{"kubelet", "docker"}, expected: []string{"kubelet", "docker"}},
+		{components: []string{"localkube"}, shouldErr: true},
+	}
+	for _, test := range tests {
+		selected, err := SelectLogComponents(LogOptions{Components: test.components}, available)
+		if (err != nil) != test.shouldErr {
+			t.Errorf("SelectLogComponents(%v) error: %v, expected error: %t", test.components, err, test.shouldErr)
+			continue
+		}
+		if len(selected) != len(test.expected) {
+			t.Errorf("SelectLogComponents(%v) = %v, expected %v", test.components, selected, test.expected)
+		}
+	}
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package bootstrapper
 
 import (
+	"archive/tar"
 	"bytes"
 	"fmt"
 	"io"
 	"path"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -33,12 +37,18 @@ import (
 //
 // It implements the CommandRunner interface.
 type SSHRunner struct {
-	c *ssh.Client
+	c SSHClient
+}
+
+// SSHClient opens the sessions an SSHRunner runs commands in, such as an
+// *ssh.Client or a pooled *sshutil.Client.
+type SSHClient interface {
+	NewSession() (*ssh.Session, error)
 }
 
 // NewSSHRunner returns a new SSHRunner that will run commands
-// through the ssh.Client provided.
-func NewSSHRunner(c *ssh.Client) *SSHRunner {
+// through the client provided.
+func NewSSHRunner(c SSHClient) *SSHRunner {
 	return &SSHRunner{c}
 }
 
@@ -64,6 +74,56 @@ func (s *SSHRunner) Run(cmd string) error {
 	return sess.Run(cmd)
 }
 
+// RunCmd runs the command of the request on the remote. The environment
+// is set with env, as SSH servers usually refuse to set it.
+func (s *SSHRunner) RunCmd(req RunRequest) (*RunResult, error) {
+	rr := &RunResult{Args: req.Args}
+	cmd := req.ShellCommand()
+	glog.Infoln("Run:", cmd)
+	ctx, cancel := req.context()
+	defer cancel()
+
+	sess, err := s.c.NewSession()
+	if err != nil {
+		rr.ExitCode = -1
+		return rr, errors.Wrap(err, "getting ssh session")
+	}
+	defer sess.Close()
+	sess.Stdin = req.Stdin
+	sess.Stdout, sess.Stderr = req.writers(rr)
+
+	start := time.Now()
+	if err := sess.Start(cmd); err != nil {
+		rr.ExitCode = -1
+		return rr, errors.Wrapf(err, "starting command: %s", cmd)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- sess.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// Closing the session makes Wait return, even when the server does
+		// not support signals
+		sess.Signal(ssh.SIGKILL)
+		sess.Close()
+		<-done
+		rr.Duration = time.Since(start)
+		rr.ExitCode = -1
+		return rr, errors.Wrapf(ctx.Err(), "running command: %s", cmd)
+	}
+	rr.Duration = time.Since(start)
+	if exitErr, ok := err.(*ssh.ExitError); ok {
+		return rr, exitError(rr, exitErr.ExitStatus())
+	}
+	if err != nil {
+		rr.ExitCode = -1
+		return rr, errors.Wrapf(err, "running command: %s", cmd)
+	}
+	return rr, nil
+}
+
 // CombinedOutputTo runs the command and stores both command
 // output and error to out.
 func (s *SSHRunner) CombinedOutputTo(cmd string, out io.Writer) error {
@@ -74,8 +134,10 @@ func (s *SSHRunner) CombinedOutputTo(cmd string, out io.Writer) error {
 	}
 	defer sess.Close()
 
-	sess.Stdout = out
-	sess.Stderr = out
+	// stdout and stderr are copied concurrently, which out may not support
+	w := &singleWriter{w: out}
+	sess.Stdout = w
+	sess.Stderr = w
 
 	err = sess.Run(cmd)
 	if err != nil {
@@ -85,6 +147,18 @@ func (s *SSHRunner) CombinedOutputTo(cmd string, out io.Writer) error {
 	return nil
 }
 
+// singleWriter serializes the writes to w.
+type singleWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *singleWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
 // CombinedOutput runs the command on the remote and returns its combined
 // standard output and standard error.
 func (s *SSHRunner) CombinedOutput(cmd string) (string, error) {
@@ -96,14 +170,185 @@ func (s *SSHRunner) CombinedOutput(cmd string) (string, error) {
 	return b.String(), nil
 }
 
-// Copy copies a file to the remote over SSH.
+// Copy copies a file to the remote over SSH, unless the remote already
+// has it.
 func (s *SSHRunner) Copy(f assets.CopyableFile) error {
-	deleteCmd := fmt.Sprintf("sudo rm -f %s", path.Join(f.GetTargetDir(), f.GetTargetName()))
-	mkdirCmd := fmt.Sprintf("sudo mkdir -p %s", f.GetTargetDir())
-	for _, cmd := range []string{deleteCmd, mkdirCmd} {
-		if err := s.Run(cmd); err != nil {
-			return errors.Wrapf(err, "Error running command: %s", cmd)
+	return s.CopyAll([]assets.CopyableFile{f})
+}
+
+// CopyAll copies the files to the remote over SSH. The files the remote
+// already has with the same contents and permissions are skipped, and the
+// others are sent in a single tar stream.
+func (s *SSHRunner) CopyAll(files []assets.CopyableFile) error {
+	changed := s.changedFiles(files)
+	switch len(changed) {
+	case 0:
+		return nil
+	case 1:
+		return s.scp(changed[0])
+	}
+	return s.copyTar(changed)
+}
+
+// remoteFile is the state of a file on the remote
+type remoteFile struct {
+	mode     uint64
+	checksum string
+}
+
+// changedFiles returns the files that are missing or different on the
+// remote. Files that can not be compared are returned as changed.
+func (s *SSHRunner) changedFiles(files []assets.CopyableFile) []assets.CopyableFile {
+	local := map[string]remoteFile{}
+	var paths []string
+	for _, f := range files {
+		sum, err := assets.Checksum(f)
+		if err != nil {
+			glog.Infof("Not comparing %s: %s", f.GetAssetName(), err)
+			continue
+		}
+		mode, err := strconv.ParseUint(f.GetPermissions(), 8, 32)
+		if err != nil {
+			continue
 		}
+		p := targetPath(f)
+		local[p] = remoteFile{mode, sum}
+		paths = append(paths, p)
+	}
+
+	remote := map[string]remoteFile{}
+	if len(paths) > 0 {
+		out, err := s.CombinedOutput(remoteChecksumCommand(paths))
+		if err != nil {
+			glog.Infof("Error getting remote checksums, copying all files: %s", err)
+		} else {
+			remote = parseRemoteChecksums(out)
+		}
+	}
+
+	var changed []assets.CopyableFile
+	for _, f := range files {
+		p := targetPath(f)
+		if l, ok := local[p]; ok && l == remote[p] {
+			glog.Infof("Skipping %s, it is up to date", p)
+			continue
+		}
+		changed = append(changed, f)
+	}
+	return changed
+}
+
+func targetPath(f assets.CopyableFile) string {
+	return path.Join(f.GetTargetDir(), f.GetTargetName())
+}
+
+// remoteChecksumCommand returns a command printing the mode, sha256 and
+// path of every existing file of paths
+func remoteChecksumCommand(paths []string) string {
+	var quoted []string
+	for _, p := range paths {
+		quoted = append(quoted, shellQuote(p))
+	}
+	return fmt.Sprintf(`sudo sh -c 'for f in "$@"; do [ -f "$f" ] && echo "$(stat -c %%a "$f") $(sha256sum "$f")"; done; true' -- %s`, strings.Join(quoted, " "))
+}
+
+// parseRemoteChecksums parses the "mode sha256  path" lines of the output
+// of remoteChecksumCommand
+func parseRemoteChecksums(out string) map[string]remoteFile {
+	files := map[string]remoteFile{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			continue
+		}
+		sum := strings.SplitN(fields[1], "  ", 2)
+		if len(sum) != 2 {
+			continue
+		}
+		files[sum[1]] = remoteFile{mode, sum[0]}
+	}
+	return files
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// copyTar sends the files to the remote in a single tar stream, extracted
+// relative to the root directory
+func (s *SSHRunner) copyTar(files []assets.CopyableFile) error {
+	dirs := map[string]bool{}
+	var quoted []string
+	for _, f := range files {
+		if !dirs[f.GetTargetDir()] {
+			dirs[f.GetTargetDir()] = true
+			quoted = append(quoted, shellQuote(f.GetTargetDir()))
+		}
+	}
+	glog.Infof("Copying %d files in a single tar stream", len(files))
+
+	sess, err := s.c.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "Error creating new session via ssh client")
+	}
+	defer sess.Close()
+	w, err := sess.StdinPipe()
+	if err != nil {
+		return errors.Wrap(err, "Error accessing StdinPipe via ssh session")
+	}
+	var wg sync.WaitGroup
+	var tarErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer w.Close()
+		tarErr = writeTar(w, files)
+	}()
+
+	cmd := fmt.Sprintf("sudo mkdir -p %s && sudo tar -x -f - -C /", strings.Join(quoted, " "))
+	out, err := sess.CombinedOutput(cmd)
+	wg.Wait()
+	if err != nil {
+		return errors.Wrapf(err, "Error running tar command: %s output: %s", cmd, out)
+	}
+	return errors.Wrap(tarErr, "Error writing tar stream")
+}
+
+// writeTar writes the files as a tar stream of paths relative to the root
+// directory
+func writeTar(w io.Writer, files []assets.CopyableFile) error {
+	tw := tar.NewWriter(w)
+	for _, f := range files {
+		mode, err := strconv.ParseInt(f.GetPermissions(), 8, 64)
+		if err != nil {
+			return errors.Wrapf(err, "parsing permissions of %s", f.GetAssetName())
+		}
+		hdr := &tar.Header{
+			Name:     strings.TrimPrefix(targetPath(f), "/"),
+			Mode:     mode,
+			Size:     int64(f.GetLength()),
+			ModTime:  time.Now(),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return errors.Wrapf(err, "writing tar header of %s", f.GetAssetName())
+		}
+		if _, err := io.Copy(tw, f); err != nil {
+			return errors.Wrapf(err, "writing %s", f.GetAssetName())
+		}
+	}
+	return tw.Close()
+}
+
+// scp copies a single file to the remote with scp.
+func (s *SSHRunner) scp(f assets.CopyableFile) error {
+	cmd := fmt.Sprintf("sudo rm -f %s && sudo mkdir -p %s", targetPath(f), f.GetTargetDir())
+	if err := s.Run(cmd); err != nil {
+		return errors.Wrapf(err, "Error running command: %s", cmd)
 	}
 
 	sess, err := s.c.NewSession()
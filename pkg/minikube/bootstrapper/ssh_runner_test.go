@@ -0,0 +1,159 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/sshutil"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func TestParseRemoteChecksums(t *testing.T) {
+	out := "640 abc  /etc/kubernetes/addons/dashboard-dp.yaml\n" +
+		"755 def  /usr/bin/kubelet\n" +
+		"garbage\n\n"
+	expected := map[string]remoteFile{
+		"/etc/kubernetes/addons/dashboard-dp.yaml": {0640, "abc"},
+		"/usr/bin/kubelet":                         {0755, "def"},
+	}
+	if actual := parseRemoteChecksums(out); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("parseRemoteChecksums() = %v, expected %v", actual, expected)
+	}
+}
+
+func TestRemoteChecksumCommand(t *testing.T) {
+	cmd := remoteChecksumCommand([]string{"/etc/a.yaml", "/etc/it's.yaml"})
+	if !strings.HasSuffix(cmd, ` -- '/etc/a.yaml' '/etc/it'\''s.yaml'`) {
+		t.Errorf("Unexpected quoting of paths: %s", cmd)
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	var b bytes.Buffer
+	files := []assets.CopyableFile{
+		assets.NewMemoryAsset([]byte("a"), "/etc/kubernetes/addons", "a.yaml", "0640"),
+		assets.NewMemoryAsset([]byte("bb"), "/usr/bin", "b", "0755"),
+	}
+	if err := writeTar(&b, files); err != nil {
+		t.Fatalf("Error writing tar: %s", err)
+	}
+	expected := map[string]string{"etc/kubernetes/addons/a.yaml": "a", "usr/bin/b": "bb"}
+	if actual := readTar(t, &b); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Tar contents = %v, expected %v", actual, expected)
+	}
+}
+
+func readTar(t *testing.T, r io.Reader) map[string]string {
+	contents := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return contents
+		}
+		if err != nil {
+			t.Fatalf("Error reading tar: %s", err)
+		}
+		data, _ := ioutil.ReadAll(tr)
+		contents[hdr.Name] = string(data)
+	}
+}
+
+func newTestSSHRunner(t *testing.T) (*SSHRunner, *tests.SSHServer) {
+	s, err := tests.NewSSHServer()
+	if err != nil {
+		t.Fatalf("Error creating ssh server: %s", err)
+	}
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	c, err := sshutil.NewSSHClient(&tests.MockDriver{
+		Port:       port,
+		BaseDriver: drivers.BaseDriver{IPAddress: "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("Error creating ssh client: %s", err)
+	}
+	return NewSSHRunner(c), s
+}
+
+func TestSSHRunnerRunCmd(t *testing.T) {
+	r, s := newTestSSHRunner(t)
+	s.SetCommandToOutput(map[string]string{"env 'A=b' 'sudo' 'tee' '/etc/x'": "written"})
+	rr, err := r.RunCmd(RunRequest{
+		Args:  []string{"sudo", "tee", "/etc/x"},
+		Env:   []string{"A=b"},
+		Stdin: strings.NewReader("contents"),
+	})
+	if err != nil {
+		t.Fatalf("Error running command: %s", err)
+	}
+	if rr.ExitCode != 0 || rr.Stdout.String() != "written" {
+		t.Errorf("Unexpected result: exit code %d, output %q", rr.ExitCode, rr.Stdout.String())
+	}
+	if s.Transfers.String() != "contents" {
+		t.Errorf("Expected stdin to be sent, got %q", s.Transfers.String())
+	}
+}
+
+func TestSSHRunnerCopyAllSkipsUpToDateFiles(t *testing.T) {
+	r, s := newTestSSHRunner(t)
+
+	upToDate := assets.NewMemoryAsset([]byte("same"), "/etc/kubernetes/addons", "same.yaml", "0640")
+	modeChanged := assets.NewMemoryAsset([]byte("mode"), "/etc/kubernetes/addons", "mode.yaml", "0640")
+	missing := assets.NewMemoryAsset([]byte("missing"), "/usr/bin", "missing", "0755")
+	files := []assets.CopyableFile{upToDate, modeChanged, missing}
+	var paths []string
+	var out string
+	for _, f := range files {
+		paths = append(paths, targetPath(f))
+	}
+	for _, f := range []assets.CopyableFile{upToDate, modeChanged} {
+		sum, err := assets.Checksum(f)
+		if err != nil {
+			t.Fatalf("Error computing checksum: %s", err)
+		}
+		mode := "640"
+		if f == modeChanged {
+			mode = "644"
+		}
+		out += mode + " " + sum + "  " + targetPath(f) + "\n"
+	}
+	s.SetCommandToOutput(map[string]string{remoteChecksumCommand(paths): out})
+
+	if err := r.CopyAll(files); err != nil {
+		t.Fatalf("Error copying files: %s", err)
+	}
+	tarCmd := "sudo mkdir -p '/etc/kubernetes/addons' '/usr/bin' && sudo tar -x -f - -C /"
+	if _, ok := s.Commands[tarCmd]; !ok {
+		t.Fatalf("Expected command %s, got %v", tarCmd, s.Commands)
+	}
+	expected := map[string]string{"etc/kubernetes/addons/mode.yaml": "mode", "usr/bin/missing": "missing"}
+	if actual := readTar(t, s.Transfers); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Copied %v, expected %v", actual, expected)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapper
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/docker/machine/libmachine/state"
+	"k8s.io/minikube/pkg/util"
+)
+
+// ClusterStatus is the status of a cluster and of its components
+type ClusterStatus struct {
+	// Cluster is Running if the cluster was started and not stopped
+	Cluster string
+	// APIServer is Running if the apiserver reports itself healthy
+	APIServer string
+	// Components are the statuses of the cluster components
+	Components []ComponentStatus
+}
+
+// ComponentStatus is the status of a cluster component
+type ComponentStatus struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// Error is the last error of a component that is not running
+	Error string `json:"error,omitempty"`
+}
+
+// healthzArgs query the healthz endpoint of the apiserver from the
+// machine, authenticating with the client certificate kubectl uses rather
+// than with the serving certificate of the apiserver
+var healthzArgs = []string{"curl", "-sS", "--max-time", "5",
+	"--cacert", path.Join(util.DefaultCertPath, "ca.crt"),
+	"--cert", path.Join(util.DefaultCertPath, "client.crt"),
+	"--key", path.Join(util.DefaultCertPath, "client.key"),
+	fmt.Sprintf("https://localhost:%d/healthz", util.APIServerPort)}
+
+// APIServerHealth returns the status of the apiserver, as reported by its
+// healthz endpoint.
+func APIServerHealth(cmd CommandRunner) ComponentStatus {
+	s := ComponentStatus{Name: "apiserver"}
+	rr, err := cmd.RunCmd(RunRequest{Args: healthzArgs})
+	out := strings.TrimSpace(rr.Stdout.String())
+	switch {
+	case err != nil:
+		// curl failed to get a response, and tells why on stderr
+		s.State = state.Stopped.String()
+		s.Error = strings.TrimSpace(rr.Stderr.String())
+		if s.Error == "" {
+			s.Error = err.Error()
+		}
+	case out == "ok":
+		s.State = state.Running.String()
+	default:
+		s.State = state.Error.String()
+		s.Error = out
+	}
+	return s
+}
@@ -0,0 +1,402 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle packages the artifacts needed to start a cluster into a
+// single archive, so that clusters can be started without network access.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/kubeadm"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/util"
+)
+
+// manifestFile is the name of the manifest inside a bundle
+const manifestFile = "bundle.json"
+
+// Manifest describes the contents of a bundle.
+type Manifest struct {
+	MinikubeVersion   string    `json:"minikubeVersion"`
+	Created           time.Time `json:"created"`
+	Bootstrapper      string    `json:"bootstrapper"`
+	KubernetesVersion string    `json:"kubernetesVersion"`
+	ISOURL            string    `json:"isoURL"`
+	Images            []string  `json:"images"`
+	// Files are the bundled artifacts, relative to the minikube directory
+	Files []string `json:"files"`
+}
+
+// Artifact is a file in the minikube cache that is needed to start a cluster.
+type Artifact struct {
+	Description string
+	Path        string
+	// Checksummed artifacts are downloaded with a checksum sidecar, which
+	// marks the download as complete
+	Checksummed bool
+}
+
+// Images returns the images a cluster needs: the images of the bootstrapper,
+// the images of the enabled addons and the images added with minikube cache add.
+func Images(bootstrapperName, version string) ([]string, error) {
+	images := append([]string{}, bootstrapper.GetCachedImageList(version, bootstrapperName)...)
+	addonImages, err := AddonImages()
+	if err != nil {
+		return nil, err
+	}
+	images = append(images, addonImages...)
+	cfg, err := config.ReadConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading config")
+	}
+	if values, ok := cfg[constants.Cache].(map[string]interface{}); ok {
+		for image := range values {
+			images = append(images, image)
+		}
+	}
+
+	seen := map[string]bool{}
+	var unique []string
+	for _, image := range images {
+		if !seen[image] {
+			seen[image] = true
+			unique = append(unique, image)
+		}
+	}
+	sort.Strings(unique)
+	return unique, nil
+}
+
+// AddonImages returns the images of the enabled addons.
+func AddonImages() ([]string, error) {
+	var images []string
+	for name, addon := range assets.Addons {
+		enabled, err := addon.IsEnabled()
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking status of addon %s", name)
+		}
+		if enabled {
+			images = append(images, addon.Images()...)
+		}
+	}
+	return images, nil
+}
+
+// Artifacts returns the cached files needed to start a cluster from the ISO
+// at isoURL with the given bootstrapper, kubernetes version and images.
+func Artifacts(isoURL, bootstrapperName, version string, images []string) ([]Artifact, error) {
+	if bootstrapperName != bootstrapper.BootstrapperTypeKubeadm {
+		return nil, fmt.Errorf("offline clusters are only supported by the %s bootstrapper", bootstrapper.BootstrapperTypeKubeadm)
+	}
+	var artifacts []Artifact
+	// ISOs given as file:// URLs are never cached
+	if u, err := url.Parse(isoURL); err == nil && u.Scheme != "file" {
+		artifacts = append(artifacts, Artifact{
+			Description: "ISO " + isoURL,
+			Path:        util.DefaultDownloader{}.GetISOCacheFilepath(isoURL),
+			Checksummed: true,
+		})
+	}
+	for _, bin := range kubeadm.CachedBinaries {
+		artifacts = append(artifacts, Artifact{
+			Description: fmt.Sprintf("%s %s", bin, version),
+			Path:        kubeadm.CachedBinaryPath(bin, version),
+			Checksummed: true,
+		})
+	}
+	for _, image := range images {
+		artifacts = append(artifacts, Artifact{
+			Description: "image " + image,
+			Path:        machine.CachedImagePath(constants.ImageCacheDir, image),
+		})
+	}
+	return artifacts, nil
+}
+
+// ManifestArtifacts returns the artifacts needed by the cluster described by m.
+func ManifestArtifacts(m *Manifest) ([]Artifact, error) {
+	return Artifacts(m.ISOURL, m.Bootstrapper, m.KubernetesVersion, m.Images)
+}
+
+// Missing returns the artifacts that are not in the cache.
+func Missing(artifacts []Artifact) []Artifact {
+	var missing []Artifact
+	for _, a := range artifacts {
+		if a.Checksummed {
+			if !util.IsCached(a.Path) {
+				missing = append(missing, a)
+			}
+			continue
+		}
+		if _, err := os.Stat(a.Path); err != nil {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
+
+// Export writes the artifacts needed by the cluster described by m to a
+// bundle at path. The artifacts must already be in the cache.
+func Export(path string, m Manifest) error {
+	artifacts, err := ManifestArtifacts(&m)
+	if err != nil {
+		return err
+	}
+	if missing := Missing(artifacts); len(missing) > 0 {
+		return fmt.Errorf("missing artifacts: %s", describe(missing))
+	}
+	m.Files = nil
+	for _, a := range artifacts {
+		paths := []string{a.Path}
+		if a.Checksummed {
+			if err := util.EnsureChecksum(a.Path); err != nil {
+				return errors.Wrapf(err, "checksumming %s", a.Path)
+			}
+			paths = append(paths, util.ChecksumFile(a.Path))
+		}
+		for _, p := range paths {
+			rel, err := filepath.Rel(constants.GetMinipath(), p)
+			if err != nil {
+				return errors.Wrapf(err, "locating %s", p)
+			}
+			m.Files = append(m.Files, filepath.ToSlash(rel))
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+	if err := write(f, m); err != nil {
+		f.Close()
+		os.Remove(path)
+		return errors.Wrap(err, "writing bundle")
+	}
+	return f.Close()
+}
+
+func write(w io.Writer, m Manifest) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	data, err := json.MarshalIndent(m, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestFile, Mode: 0644, Size: int64(len(data)), ModTime: m.Created}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	for _, name := range m.Files {
+		if err := addFile(tw, name); err != nil {
+			return errors.Wrapf(err, "adding %s", name)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func addFile(tw *tar.Writer, name string) error {
+	f, err := os.Open(filepath.Join(constants.GetMinipath(), filepath.FromSlash(name)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// cacheDir is the directory of the minikube directory that bundles can
+// write to
+const cacheDir = "cache/"
+
+// Import extracts the bundle at path into the minikube cache and returns its
+// manifest. Only the cache files listed in the manifest are extracted. They
+// are checked against the checksums of the bundle before replacing the
+// cached files, so a bundle failing to import leaves the cache unchanged.
+func Import(path string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening bundle")
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading bundle")
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	// Export writes the manifest first
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestFile {
+		return nil, fmt.Errorf("%s is not a minikube bundle, it does not start with %s", path, manifestFile)
+	}
+	m := &Manifest{}
+	if err := json.NewDecoder(tr).Decode(m); err != nil {
+		return nil, errors.Wrap(err, "decoding bundle manifest")
+	}
+	listed := map[string]bool{}
+	for _, name := range m.Files {
+		if !isCacheFile(name) {
+			return nil, fmt.Errorf("bundle manifest lists %s, which is not in the minikube cache", name)
+		}
+		listed[name] = true
+	}
+
+	// extracted maps the bundle files to the temporary files they are
+	// extracted to
+	extracted := map[string]string{}
+	defer func() {
+		for _, tmp := range extracted {
+			os.Remove(tmp)
+		}
+	}()
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "reading bundle")
+		}
+		if hdr.Typeflag != tar.TypeReg || !listed[hdr.Name] {
+			return nil, fmt.Errorf("bundle contains %s, which is not listed in its manifest", hdr.Name)
+		}
+		if _, ok := extracted[hdr.Name]; ok {
+			return nil, fmt.Errorf("bundle contains %s twice", hdr.Name)
+		}
+		tmp, err := extractFile(tr, hdr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "extracting %s", hdr.Name)
+		}
+		extracted[hdr.Name] = tmp
+	}
+
+	var missing []string
+	for _, name := range m.Files {
+		if _, ok := extracted[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("bundle is incomplete, it is missing: %s", strings.Join(missing, ", "))
+	}
+	for name, tmp := range extracted {
+		if strings.HasSuffix(name, util.ChecksumSuffix) {
+			continue
+		}
+		if sidecar, ok := extracted[name+util.ChecksumSuffix]; ok {
+			if err := util.VerifyChecksumFile(tmp, sidecar); err != nil {
+				return nil, errors.Wrapf(err, "verifying %s", name)
+			}
+		}
+	}
+
+	// The checksum sidecars mark cached files as complete, so they are
+	// moved last
+	names := append([]string{}, m.Files...)
+	sort.SliceStable(names, func(i, j int) bool {
+		return !strings.HasSuffix(names[i], util.ChecksumSuffix) && strings.HasSuffix(names[j], util.ChecksumSuffix)
+	})
+	for _, name := range names {
+		dst := filepath.Join(constants.GetMinipath(), filepath.FromSlash(name))
+		glog.Infof("Importing %s", dst)
+		if err := os.Rename(extracted[name], dst); err != nil {
+			return nil, errors.Wrapf(err, "importing %s", name)
+		}
+		delete(extracted, name)
+	}
+	return m, nil
+}
+
+// isCacheFile returns whether the bundle file name is in the minikube cache
+func isCacheFile(name string) bool {
+	clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(name)))
+	return clean == name && strings.HasPrefix(name, cacheDir)
+}
+
+// extractFile writes the bundle file of hdr next to its destination in the
+// minikube cache, and returns the path it was written to
+func extractFile(r io.Reader, hdr *tar.Header) (string, error) {
+	dst := filepath.Join(constants.GetMinipath(), filepath.FromSlash(hdr.Name))
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(dst), filepath.Base(dst)+".import")
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := os.Chmod(f.Name(), os.FileMode(hdr.Mode).Perm()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// describe lists the artifacts for error messages.
+func describe(artifacts []Artifact) string {
+	var d []string
+	for _, a := range artifacts {
+		d = append(d, a.Description)
+	}
+	return strings.Join(d, ", ")
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func setupCache(t *testing.T) (string, Manifest) {
+	tempDir := tests.MakeTempDir()
+	constants.ImageCacheDir = constants.MakeMiniPath("cache", "images")
+	m := Manifest{
+		Created:           time.Now(),
+		Bootstrapper:      bootstrapper.BootstrapperTypeKubeadm,
+		KubernetesVersion: "v1.9.4",
+		ISOURL:            "https://storage.googleapis.com/minikube/iso/minikube-v0.25.1.iso",
+		Images:            []string{"k8s.gcr.io/pause-amd64:3.0", "gcr.io/k8s-minikube/storage-provisioner:v1.8.1"},
+	}
+	artifacts, err := ManifestArtifacts(&m)
+	if err != nil {
+		t.Fatalf("Error listing artifacts: %s", err)
+	}
+	for _, a := range artifacts {
+		if err := os.MkdirAll(filepath.Dir(a.Path), 0777); err != nil {
+			t.Fatalf("Error creating cache dir: %s", err)
+		}
+		if err := ioutil.WriteFile(a.Path, []byte(a.Description), 0644); err != nil {
+			t.Fatalf("Error writing %s: %s", a.Path, err)
+		}
+		if a.Checksummed {
+			if err := util.WriteChecksum(a.Path); err != nil {
+				t.Fatalf("Error writing checksum of %s: %s", a.Path, err)
+			}
+		}
+	}
+	return tempDir, m
+}
+
+func TestExportImport(t *testing.T) {
+	defer func(dir string) { constants.ImageCacheDir = dir }(constants.ImageCacheDir)
+	tempDir, m := setupCache(t)
+	defer os.RemoveAll(tempDir)
+
+	bundlePath := filepath.Join(filepath.Dir(tempDir), "bundle.tar.gz")
+	if err := Export(bundlePath, m); err != nil {
+		t.Fatalf("Error exporting bundle: %s", err)
+	}
+
+	// Import into an empty cache
+	if err := os.RemoveAll(constants.MakeMiniPath("cache")); err != nil {
+		t.Fatalf("Error clearing cache: %s", err)
+	}
+	artifacts, err := ManifestArtifacts(&m)
+	if err != nil {
+		t.Fatalf("Error listing artifacts: %s", err)
+	}
+	if missing := Missing(artifacts); len(missing) != len(artifacts) {
+		t.Fatalf("Expected every artifact to be missing, got %v", missing)
+	}
+
+	imported, err := Import(bundlePath)
+	if err != nil {
+		t.Fatalf("Error importing bundle: %s", err)
+	}
+	// The ISO and binaries are bundled with their checksum
+	if imported.KubernetesVersion != m.KubernetesVersion || len(imported.Files) != len(artifacts)+3 {
+		t.Fatalf("Unexpected manifest: %+v", imported)
+	}
+	if missing := Missing(artifacts); len(missing) != 0 {
+		t.Fatalf("Artifacts missing after import: %v", missing)
+	}
+	contents, err := ioutil.ReadFile(artifacts[0].Path)
+	if err != nil {
+		t.Fatalf("Error reading %s: %s", artifacts[0].Path, err)
+	}
+	if string(contents) != artifacts[0].Description {
+		t.Fatalf("Unexpected contents of %s: %s", artifacts[0].Path, contents)
+	}
+}
+
+// writeBundle writes a bundle of m holding files, which may differ from the
+// files listed in m
+func writeBundle(t *testing.T, path string, m Manifest, files map[string]string) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Error encoding manifest: %s", err)
+	}
+	entries := map[string]string{manifestFile: string(data)}
+	names := []string{manifestFile}
+	for name, contents := range files {
+		entries[name] = contents
+		names = append(names, name)
+	}
+	for _, name := range names {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(entries[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Error writing %s: %s", name, err)
+		}
+		if _, err := tw.Write([]byte(entries[name])); err != nil {
+			t.Fatalf("Error writing %s: %s", name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Error writing bundle: %s", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Error writing bundle: %s", err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Error writing bundle: %s", err)
+	}
+}
+
+func checksum(t *testing.T, contents string) string {
+	f, err := ioutil.TempFile("", "checksum")
+	if err != nil {
+		t.Fatalf("Error creating temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+	defer os.Remove(util.ChecksumFile(f.Name()))
+	f.WriteString(contents)
+	f.Close()
+	if err := util.WriteChecksum(f.Name()); err != nil {
+		t.Fatalf("Error writing checksum: %s", err)
+	}
+	sum, err := ioutil.ReadFile(util.ChecksumFile(f.Name()))
+	if err != nil {
+		t.Fatalf("Error reading checksum: %s", err)
+	}
+	return string(sum)
+}
+
+func TestImportInvalid(t *testing.T) {
+	const iso = "cache/iso/minikube.iso"
+	var testCases = []struct {
+		description string
+		listed      []string
+		files       map[string]string
+	}{
+		{
+			description: "file outside of the cache",
+			listed:      []string{"config/config.json"},
+			files:       map[string]string{"config/config.json": "{}"},
+		},
+		{
+			description: "path escaping the cache",
+			listed:      []string{"cache/../ca.key"},
+			files:       map[string]string{"cache/../ca.key": "key"},
+		},
+		{
+			description: "unlisted file",
+			listed:      []string{iso},
+			files:       map[string]string{iso: "iso", "cache/images/unlisted": "image"},
+		},
+		{
+			description: "missing file",
+			listed:      []string{iso, iso + util.ChecksumSuffix},
+			files:       map[string]string{iso + util.ChecksumSuffix: checksum(t, "iso")},
+		},
+		{
+			description: "checksum mismatch",
+			listed:      []string{iso, iso + util.ChecksumSuffix},
+			files:       map[string]string{iso: "tampered", iso + util.ChecksumSuffix: checksum(t, "iso")},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.description, func(t *testing.T) {
+			tempDir := tests.MakeTempDir()
+			defer os.RemoveAll(tempDir)
+
+			bundlePath := filepath.Join(filepath.Dir(tempDir), "bundle.tar.gz")
+			writeBundle(t, bundlePath, Manifest{Files: test.listed}, test.files)
+			if _, err := Import(bundlePath); err == nil {
+				t.Fatalf("Expected an error importing the bundle")
+			}
+			for name := range test.files {
+				if _, err := os.Stat(filepath.Join(tempDir, filepath.FromSlash(name))); !os.IsNotExist(err) {
+					t.Errorf("Expected %s not to be imported", name)
+				}
+			}
+		})
+	}
+}
+
+func TestExportMissingArtifacts(t *testing.T) {
+	defer func(dir string) { constants.ImageCacheDir = dir }(constants.ImageCacheDir)
+	tempDir, m := setupCache(t)
+	defer os.RemoveAll(tempDir)
+
+	m.Images = append(m.Images, "k8s.gcr.io/not-cached:v1.0")
+	bundlePath := filepath.Join(filepath.Dir(tempDir), "bundle.tar.gz")
+	if err := Export(bundlePath, m); err == nil {
+		t.Fatalf("Expected an error exporting a bundle with missing artifacts")
+	}
+	if _, err := os.Stat(bundlePath); !os.IsNotExist(err) {
+		t.Fatalf("Expected no bundle to be written")
+	}
+}
+
+func TestArtifactsUnsupportedBootstrapper(t *testing.T) {
+	if _, err := Artifacts(constants.DefaultIsoUrl, bootstrapper.BootstrapperTypeLocalkube, "v1.9.4", nil); err == nil {
+		t.Fatalf("Expected an error for the localkube bootstrapper")
+	}
+}
@@ -40,8 +40,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	pkgutil "k8s.io/minikube/pkg/util"
 
 	"k8s.io/minikube/pkg/util"
@@ -62,19 +64,25 @@ func init() {
 
 // StartHost starts a host VM.
 func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	return StartNamedHost(api, cfg.GetMachineName(), config)
+}
+
+// StartNamedHost starts the host VM with the given machine name, creating it
+// from config if it does not exist yet.
+func StartNamedHost(api libmachine.API, name string, config MachineConfig) (*host.Host, error) {
+	exists, err := api.Exists(name)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error checking if host exists: %s", cfg.GetMachineName())
+		return nil, errors.Wrapf(err, "Error checking if host exists: %s", name)
 	}
 	if !exists {
 		glog.Infoln("Machine does not exist... provisioning new machine")
 		glog.Infof("Provisioning machine with config: %+v", config)
-		return createHost(api, config)
+		return createNamedHost(api, name, config)
 	} else {
 		glog.Infoln("Skipping create...Using existing machine configuration")
 	}
 
-	h, err := api.Load(cfg.GetMachineName())
+	h, err := api.Load(name)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error loading existing host. Please try running [minikube delete], then run [minikube start] again.")
 	}
@@ -104,45 +112,60 @@ func StartHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 
 // StopHost stops the host VM.
 func StopHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+	return StopNamedHost(api, cfg.GetMachineName())
+}
+
+// StopNamedHost stops the host VM with the given machine name.
+func StopNamedHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error loading host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error loading host: %s", name)
 	}
 	if err := host.Stop(); err != nil {
 		alreadyInStateError, ok := err.(mcnerror.ErrHostAlreadyInState)
 		if ok && alreadyInStateError.State == state.Stopped {
 			return nil
 		}
-		return errors.Wrapf(err, "Error stopping host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error stopping host: %s", name)
 	}
 	return nil
 }
 
 // DeleteHost deletes the host VM.
 func DeleteHost(api libmachine.API) error {
-	host, err := api.Load(cfg.GetMachineName())
+	return DeleteNamedHost(api, cfg.GetMachineName())
+}
+
+// DeleteNamedHost deletes the host VM with the given machine name.
+func DeleteNamedHost(api libmachine.API, name string) error {
+	host, err := api.Load(name)
 	if err != nil {
-		return errors.Wrapf(err, "Error deleting host: %s", cfg.GetMachineName())
+		return errors.Wrapf(err, "Error deleting host: %s", name)
 	}
 	m := util.MultiError{}
 	m.Collect(host.Driver.Remove())
-	m.Collect(api.Remove(cfg.GetMachineName()))
+	m.Collect(api.Remove(name))
 	return m.ToError()
 }
 
 // GetHostStatus gets the status of the host VM.
 func GetHostStatus(api libmachine.API) (string, error) {
-	exists, err := api.Exists(cfg.GetMachineName())
+	return GetNamedHostStatus(api, cfg.GetMachineName())
+}
+
+// GetNamedHostStatus gets the status of the host VM with the given machine name.
+func GetNamedHostStatus(api libmachine.API, name string) (string, error) {
+	exists, err := api.Exists(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error checking that api exists for: %s", cfg.GetMachineName())
+		return "", errors.Wrapf(err, "Error checking that api exists for: %s", name)
 	}
 	if !exists {
 		return state.None.String(), nil
 	}
 
-	host, err := api.Load(cfg.GetMachineName())
+	host, err := api.Load(name)
 	if err != nil {
-		return "", errors.Wrapf(err, "Error loading api for: %s", cfg.GetMachineName())
+		return "", errors.Wrapf(err, "Error loading api for: %s", name)
 	}
 
 	s, err := host.Driver.GetState()
@@ -170,6 +193,15 @@ func GetHostDriverIP(api libmachine.API) (net.IP, error) {
 	return ip, nil
 }
 
+// GetNodeName returns the machine name of the nth node of the cluster named
+// after profile. The control plane is always node 1 and keeps the profile name.
+func GetNodeName(profile string, n int) string {
+	if n <= 1 {
+		return profile
+	}
+	return fmt.Sprintf("%s-m%02d", profile, n)
+}
+
 func engineOptions(config MachineConfig) *engine.Options {
 	o := engine.Options{
 		Env:              config.DockerEnv,
@@ -180,8 +212,8 @@ func engineOptions(config MachineConfig) *engine.Options {
 	return &o
 }
 
-func createVirtualboxHost(config MachineConfig) drivers.Driver {
-	d := virtualbox.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+func createVirtualboxHost(name string, config MachineConfig) drivers.Driver {
+	d := virtualbox.NewDriver(name, constants.GetMinipath())
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.Memory = config.Memory
 	d.CPU = config.CPUs
@@ -194,6 +226,10 @@ func createVirtualboxHost(config MachineConfig) drivers.Driver {
 }
 
 func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
+	return createNamedHost(api, cfg.GetMachineName(), config)
+}
+
+func createNamedHost(api libmachine.API, name string, config MachineConfig) (*host.Host, error) {
 	var driver interface{}
 
 	if config.VMDriver != "none" {
@@ -204,9 +240,9 @@ func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 
 	switch config.VMDriver {
 	case "virtualbox":
-		driver = createVirtualboxHost(config)
+		driver = createVirtualboxHost(name, config)
 	case "vmwarefusion":
-		driver = createVMwareFusionHost(config)
+		driver = createVMwareFusionHost(name, config)
 	case "kvm":
 		if viper.GetBool(cfg.ShowDriverDeprecationNotification) {
 			fmt.Fprintln(os.Stderr, `WARNING: The kvm driver is now deprecated and support for it will be removed in a future release.
@@ -214,9 +250,9 @@ func createHost(api libmachine.API, config MachineConfig) (*host.Host, error) {
 				See https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#kvm2-driver for more information.
 				To disable this message, run [minikube config set WantShowDriverDeprecationNotification false]`)
 		}
-		driver = createKVMHost(config)
+		driver = createKVMHost(name, config)
 	case "kvm2":
-		driver = createKVM2Host(config)
+		driver = createKVM2Host(name, config)
 	case "xhyve":
 		if viper.GetBool(cfg.ShowDriverDeprecationNotification) {
 			fmt.Fprintln(os.Stderr, `WARNING: The xhyve driver is now deprecated and support for it will be removed in a future release.
@@ -224,13 +260,13 @@ Please consider switching to the hyperkit driver, which is intended to replace t
 See https://github.com/kubernetes/minikube/blob/master/docs/drivers.md#hyperkit-driver for more information.
 To disable this message, run [minikube config set WantShowDriverDeprecationNotification false]`)
 		}
-		driver = createXhyveHost(config)
+		driver = createXhyveHost(name, config)
 	case "hyperv":
-		driver = createHypervHost(config)
+		driver = createHypervHost(name, config)
 	case "none":
-		driver = createNoneHost(config)
+		driver = createNoneHost(name, config)
 	case "hyperkit":
-		driver = createHyperkitHost(config)
+		driver = createHyperkitHost(name, config)
 	default:
 		glog.Exitf("Unsupported driver: %s\n", config.VMDriver)
 	}
@@ -295,14 +331,18 @@ func MountHost(api libmachine.API, ip net.IP, path, port, mountVersion string, u
 			return errors.Wrap(err, "Error getting the host IP address to use from within the VM")
 		}
 	}
-	host.RunSSHCommand(GetMountCleanupCommand(path))
+	client, err := sshutil.GetClient(host.Driver)
+	if err != nil {
+		return errors.Wrap(err, "Error creating ssh client")
+	}
+	runner := bootstrapper.NewSSHRunner(client)
+	runner.Run(GetMountCleanupCommand(path))
 	mountCmd, err := GetMountCommand(ip, path, port, mountVersion, uid, gid, msize)
 	if err != nil {
 		return errors.Wrap(err, "Error getting mount command")
 	}
-	_, err = host.RunSSHCommand(mountCmd)
-	if err != nil {
-		return errors.Wrap(err, "running mount host command")
+	if out, err := runner.CombinedOutput(mountCmd); err != nil {
+		return errors.Wrapf(err, "running mount host command: %s", out)
 	}
 	return nil
 }
@@ -387,7 +427,7 @@ func CreateSSHShell(api libmachine.API, args []string) error {
 		return errors.Errorf("Error: Cannot run ssh command: Host %q is not running", cfg.GetMachineName())
 	}
 
-	client, err := host.CreateSSHClient()
+	client, err := sshutil.GetClient(host.Driver)
 	if err != nil {
 		return errors.Wrap(err, "Error creating ssh client")
 	}
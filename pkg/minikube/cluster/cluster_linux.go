@@ -23,7 +23,6 @@ import (
 
 	"github.com/docker/machine/libmachine/drivers"
 	"k8s.io/minikube/pkg/drivers/none"
-	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
@@ -42,10 +41,10 @@ type kvmDriver struct {
 	IOMode         string
 }
 
-func createKVMHost(config MachineConfig) *kvmDriver {
+func createKVMHost(name string, config MachineConfig) *kvmDriver {
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: cfg.GetMachineName(),
+			MachineName: name,
 			StorePath:   constants.GetMinipath(),
 			SSHUser:     "docker",
 		},
@@ -55,17 +54,17 @@ func createKVMHost(config MachineConfig) *kvmDriver {
 		PrivateNetwork: "docker-machines",
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
 		DiskSize:       config.DiskSize,
-		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), fmt.Sprintf("%s.rawdisk", cfg.GetMachineName())),
-		ISO:            filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), "boot2docker.iso"),
+		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
+		ISO:            filepath.Join(constants.GetMinipath(), "machines", name, "boot2docker.iso"),
 		CacheMode:      "default",
 		IOMode:         "threads",
 	}
 }
 
-func createKVM2Host(config MachineConfig) *kvmDriver {
+func createKVM2Host(name string, config MachineConfig) *kvmDriver {
 	return &kvmDriver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: cfg.GetMachineName(),
+			MachineName: name,
 			StorePath:   constants.GetMinipath(),
 			SSHUser:     "docker",
 		},
@@ -75,8 +74,8 @@ func createKVM2Host(config MachineConfig) *kvmDriver {
 		PrivateNetwork: "minikube-net",
 		Boot2DockerURL: config.Downloader.GetISOFileURI(config.MinikubeISO),
 		DiskSize:       config.DiskSize,
-		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), fmt.Sprintf("%s.rawdisk", cfg.GetMachineName())),
-		ISO:            filepath.Join(constants.GetMinipath(), "machines", cfg.GetMachineName(), "boot2docker.iso"),
+		DiskPath:       filepath.Join(constants.GetMinipath(), "machines", name, fmt.Sprintf("%s.rawdisk", name)),
+		ISO:            filepath.Join(constants.GetMinipath(), "machines", name, "boot2docker.iso"),
 		CacheMode:      "default",
 		IOMode:         "threads",
 	}
@@ -90,10 +89,10 @@ func detectVBoxManageCmd() string {
 	return cmd
 }
 
-func createNoneHost(config MachineConfig) *none.Driver {
+func createNoneHost(name string, config MachineConfig) *none.Driver {
 	return &none.Driver{
 		BaseDriver: &drivers.BaseDriver{
-			MachineName: cfg.GetMachineName(),
+			MachineName: name,
 			StorePath:   constants.GetMinipath(),
 		},
 	}
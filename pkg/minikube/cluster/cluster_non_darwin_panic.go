@@ -20,14 +20,14 @@ package cluster
 
 import "github.com/docker/machine/libmachine/drivers"
 
-func createVMwareFusionHost(config MachineConfig) drivers.Driver {
+func createVMwareFusionHost(name string, config MachineConfig) drivers.Driver {
 	panic("vmwarefusion not supported")
 }
 
-func createXhyveHost(config MachineConfig) drivers.Driver {
+func createXhyveHost(name string, config MachineConfig) drivers.Driver {
 	panic("xhyve not supported")
 }
 
-func createHyperkitHost(config MachineConfig) drivers.Driver {
+func createHyperkitHost(name string, config MachineConfig) drivers.Driver {
 	panic("hyperkit not supported")
 }
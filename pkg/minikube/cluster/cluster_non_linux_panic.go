@@ -20,14 +20,14 @@ package cluster
 
 import "github.com/docker/machine/libmachine/drivers"
 
-func createKVMHost(config MachineConfig) drivers.Driver {
+func createKVMHost(name string, config MachineConfig) drivers.Driver {
 	panic("kvm not supported")
 }
 
-func createKVM2Host(config MachineConfig) drivers.Driver {
+func createKVM2Host(name string, config MachineConfig) drivers.Driver {
 	panic("kvm2 not supported")
 }
 
-func createNoneHost(config MachineConfig) drivers.Driver {
+func createNoneHost(name string, config MachineConfig) drivers.Driver {
 	panic("no-vm not supported")
 }
@@ -20,6 +20,6 @@ package cluster
 
 import "github.com/docker/machine/libmachine/drivers"
 
-func createHypervHost(config MachineConfig) drivers.Driver {
+func createHypervHost(name string, config MachineConfig) drivers.Driver {
 	panic("hyperv not supported")
 }
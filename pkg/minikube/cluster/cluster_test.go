@@ -173,6 +173,50 @@ func TestStartHost(t *testing.T) {
 	}
 }
 
+func TestStartNamedHost(t *testing.T) {
+	api := tests.NewMockAPI()
+
+	md := &tests.MockDetector{Provisioner: &tests.MockProvisioner{}}
+	provision.SetDetector(md)
+
+	name := GetNodeName(config.GetMachineName(), 2)
+	h, err := StartNamedHost(api, name, defaultMachineConfig)
+	if err != nil {
+		t.Fatal("Error starting host.")
+	}
+	if h.Name != name {
+		t.Fatalf("Machine created with incorrect name: %s", h.Name)
+	}
+	if exists, _ := api.Exists(config.GetMachineName()); exists {
+		t.Fatal("Control plane machine should not have been created.")
+	}
+
+	if err := StopNamedHost(api, name); err != nil {
+		t.Fatalf("Unexpected error stopping host: %s", err)
+	}
+	if s, _ := GetNamedHostStatus(api, name); s != state.Stopped.String() {
+		t.Fatalf("Machine not stopped. Currently in state: %s", s)
+	}
+	if err := DeleteNamedHost(api, name); err != nil {
+		t.Fatalf("Unexpected error deleting host: %s", err)
+	}
+}
+
+func TestGetNodeName(t *testing.T) {
+	for _, tc := range []struct {
+		n        int
+		expected string
+	}{
+		{1, "minikube"},
+		{2, "minikube-m02"},
+		{12, "minikube-m12"},
+	} {
+		if actual := GetNodeName("minikube", tc.n); actual != tc.expected {
+			t.Errorf("GetNodeName(minikube, %d) = %s, expected %s", tc.n, actual, tc.expected)
+		}
+	}
+}
+
 func TestStartHostConfig(t *testing.T) {
 	api := tests.NewMockAPI()
 
@@ -27,12 +27,11 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"golang.org/x/sys/windows/registry"
-	cfg "k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
 
-func createHypervHost(config MachineConfig) drivers.Driver {
-	d := hyperv.NewDriver(cfg.GetMachineName(), constants.GetMinipath())
+func createHypervHost(name string, config MachineConfig) drivers.Driver {
+	d := hyperv.NewDriver(name, constants.GetMinipath())
 	d.Boot2DockerURL = config.Downloader.GetISOFileURI(config.MinikubeISO)
 	d.VSwitch = config.HypervVirtualSwitch
 	d.MemSize = config.Memory
@@ -43,8 +43,27 @@ type MachineConfig struct {
 	UUID                string // Only used by hyperkit to restore the mac address
 }
 
+// Node contains the parameters of a single machine in the cluster.
+type Node struct {
+	Name         string
+	IP           string
+	ControlPlane bool
+}
+
 // Config contains machine and k8s config
 type Config struct {
 	MachineConfig    MachineConfig
 	KubernetesConfig bootstrapper.KubernetesConfig
+	Nodes            []Node
+}
+
+// Workers returns the nodes of the cluster that are not part of the control plane.
+func (c Config) Workers() []Node {
+	var workers []Node
+	for _, n := range c.Nodes {
+		if !n.ControlPlane {
+			workers = append(workers, n)
+		}
+	}
+	return workers
 }
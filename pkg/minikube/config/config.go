@@ -32,6 +32,8 @@ const (
 	ReminderWaitPeriodInHours         = "ReminderWaitPeriodInHours"
 	WantReportError                   = "WantReportError"
 	WantReportErrorPrompt             = "WantReportErrorPrompt"
+	ReportErrorSink                   = "ReportErrorSink"
+	ReportErrorHeaders                = "ReportErrorHeaders"
 	WantKubectlDownloadMsg            = "WantKubectlDownloadMsg"
 	WantNoneDriverWarning             = "WantNoneDriverWarning"
 	MachineProfile                    = "profile"
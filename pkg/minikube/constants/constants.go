@@ -93,8 +93,9 @@ const (
 	MinimumDiskSizeMB   = 2000
 	DefaultVMDriver     = "virtualbox"
 	DefaultStatusFormat = "minikube: {{.MinikubeStatus}}\n" +
-		"cluster: {{.ClusterStatus}}\n" + "kubectl: {{.KubeconfigStatus}}\n"
-	DefaultAddonListFormat     = "- {{.AddonName}}: {{.AddonStatus}}\n"
+		"cluster: {{.ClusterStatus}}\n" + "apiserver: {{.APIServerStatus}}\n" +
+		"kubectl: {{.KubeconfigStatus}}\n"
+	DefaultAddonListFormat     = "- {{.AddonName}}: {{.AddonStatus}}{{if .AddonSource}} (from {{.AddonSource}}){{end}}\n"
 	DefaultConfigViewFormat    = "- {{.ConfigKey}}: {{.ConfigValue}}\n"
 	DefaultCacheListFormat     = "{{.CacheImage}}\n"
 	GithubMinikubeReleasesURL  = "https://storage.googleapis.com/minikube/releases.json"
@@ -138,6 +139,9 @@ const (
 	KubeletServiceFile     = "/lib/systemd/system/kubelet.service"
 	KubeletSystemdConfFile = "/etc/systemd/system/kubelet.service.d/10-kubeadm.conf"
 	KubeadmConfigFile      = "/var/lib/kubeadm.yaml"
+	KubeadmCACertFile      = "/etc/kubernetes/pki/ca.crt"
+	//TODO(r2d4): change to something else persisted
+	KubeadmEtcdDataDir = "/data"
 )
 
 const (
@@ -166,33 +170,17 @@ const IsMinikubeChildProcess = "IS_MINIKUBE_CHILD_PROCESS"
 const DriverNone = "none"
 const FileScheme = "file"
 
+// LocalkubeCachedImages are the images localkube runs, besides the images
+// of the default addons
 var LocalkubeCachedImages = []string{
-	// Dashboard
-	"k8s.gcr.io/kubernetes-dashboard-amd64:v1.8.1",
-
-	// DNS
-	"k8s.gcr.io/k8s-dns-kube-dns-amd64:1.14.5",
-	"k8s.gcr.io/k8s-dns-dnsmasq-nanny-amd64:1.14.5",
-	"k8s.gcr.io/k8s-dns-sidecar-amd64:1.14.5",
-
-	// Addon Manager
-	"gcr.io/google-containers/kube-addon-manager:v6.5",
-
 	// Pause
 	"k8s.gcr.io/pause-amd64:3.0",
-
-	//Storage Provisioner
-	"gcr.io/k8s-minikube/storage-provisioner:v1.8.0",
 }
 
+// GetKubeadmCachedImages returns the images kubeadm runs, besides the images
+// of the default addons
 func GetKubeadmCachedImages(version string) []string {
 	return []string{
-		// Dashboard
-		"k8s.gcr.io/kubernetes-dashboard-amd64:v1.8.1",
-
-		// Addon Manager
-		"gcr.io/google-containers/kube-addon-manager:v6.5",
-
 		// Pause
 		"k8s.gcr.io/pause-amd64:3.0",
 
@@ -208,10 +196,21 @@ func GetKubeadmCachedImages(version string) []string {
 		"k8s.gcr.io/kube-scheduler-amd64:" + version,
 		"k8s.gcr.io/kube-controller-manager-amd64:" + version,
 		"k8s.gcr.io/kube-apiserver-amd64:" + version,
-
-		//Storage Provisioner
-		"gcr.io/k8s-minikube/storage-provisioner:v1.8.0",
 	}
 }
 
 var ImageCacheDir = MakeMiniPath("cache", "images")
+
+// TunnelRegistryFile records the running tunnels, so that their routes can
+// be cleaned up after a crash
+var TunnelRegistryFile = MakeMiniPath("tunnels.json")
+
+const (
+	// DefaultIngressDomain is the domain resolved to the VM by minikube dns
+	DefaultIngressDomain = "minikube.test"
+	// DefaultDNSListenAddress is the address of the DNS responder of minikube dns
+	DefaultDNSListenAddress = "127.0.0.1:10053"
+)
+
+// UserAddonsDir holds the addons installed with minikube addons install
+var UserAddonsDir = MakeMiniPath("user-addons")
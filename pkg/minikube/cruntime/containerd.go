@@ -0,0 +1,94 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// containerdNamespace is the containerd namespace used by its CRI plugin
+const containerdNamespace = "k8s.io"
+
+// Containerd is the containerd runtime, which the kubelet talks to through
+// its builtin CRI plugin
+type Containerd struct{}
+
+// Name is the canonical name of the runtime
+func (r *Containerd) Name() string {
+	return "containerd"
+}
+
+// ServiceName is the systemd unit the kubelet depends on
+func (r *Containerd) ServiceName() string {
+	return "containerd.service"
+}
+
+// Enable starts containerd
+func (r *Containerd) Enable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl start containerd")
+}
+
+// Disable stops containerd
+func (r *Containerd) Disable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl stop containerd")
+}
+
+// SocketPath is the path of the CRI socket
+func (r *Containerd) SocketPath() string {
+	return "/run/containerd/containerd.sock"
+}
+
+// LoadImage imports an image archive into the namespace of the CRI plugin
+func (r *Containerd) LoadImage(cmd CommandRunner, path string) error {
+	if err := cmd.Run("sudo ctr -n=" + containerdNamespace + " images import " + path); err != nil {
+		return errors.Wrapf(err, "loading containerd image: %s", path)
+	}
+	return nil
+}
+
+// ListImages returns the images in the namespace of the CRI plugin
+func (r *Containerd) ListImages(cmd CommandRunner) ([]string, error) {
+	return listImages(cmd, "sudo ctr -n="+containerdNamespace+" images list --quiet")
+}
+
+// KubeletOptions returns the flags connecting the kubelet to containerd
+func (r *Containerd) KubeletOptions() map[string]string {
+	return remoteKubeletOptions(r.SocketPath())
+}
+
+// FindContainer returns the ID of the most recent container of the
+// Kubernetes container name
+func (r *Containerd) FindContainer(cmd CommandRunner, name string) (string, error) {
+	return findCRIContainer(cmd, r.SocketPath(), name)
+}
+
+// ContainerLogCmd returns the command printing the logs of a container
+func (r *Containerd) ContainerLogCmd(id string, since time.Duration, tail int, follow bool) string {
+	return criContainerLogCmd(r.SocketPath(), id, since, tail, follow)
+}
+
+// ContainerRunning returns whether a container is running
+func (r *Containerd) ContainerRunning(cmd CommandRunner, id string) (bool, error) {
+	return criContainerRunning(cmd, r.SocketPath(), id)
+}
+
+// StopContainer stops a container
+func (r *Containerd) StopContainer(cmd CommandRunner, id string) error {
+	return criStopContainer(cmd, r.SocketPath(), id)
+}
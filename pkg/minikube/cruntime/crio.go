@@ -0,0 +1,95 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// kpod manages images in the storage of cri-o, which the minikube ISO keeps
+// on the persistent disk
+const kpod = `sudo sh -c '. /var/run/minikube/env && kpod --root "$PERSISTENT_DIR/var/lib/containers" --storage-driver overlay2 %s'`
+
+// CRIO is the cri-o runtime
+type CRIO struct{}
+
+// Name is the canonical name of the runtime
+func (r *CRIO) Name() string {
+	return "cri-o"
+}
+
+// ServiceName is the systemd unit the kubelet depends on
+func (r *CRIO) ServiceName() string {
+	return "crio.service"
+}
+
+// Enable starts cri-o
+func (r *CRIO) Enable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl start crio")
+}
+
+// Disable stops cri-o
+func (r *CRIO) Disable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl stop crio")
+}
+
+// SocketPath is the path of the CRI socket
+func (r *CRIO) SocketPath() string {
+	return "/var/run/crio/crio.sock"
+}
+
+// LoadImage loads an image archive with kpod
+func (r *CRIO) LoadImage(cmd CommandRunner, path string) error {
+	if err := cmd.Run(fmt.Sprintf(kpod, "load --input "+path)); err != nil {
+		return errors.Wrapf(err, "loading cri-o image: %s", path)
+	}
+	return nil
+}
+
+// ListImages returns the images in the storage of cri-o
+func (r *CRIO) ListImages(cmd CommandRunner) ([]string, error) {
+	return listImages(cmd, fmt.Sprintf(kpod, `images --format "{{.Repository}}:{{.Tag}}"`))
+}
+
+// KubeletOptions returns the flags connecting the kubelet to cri-o
+func (r *CRIO) KubeletOptions() map[string]string {
+	return remoteKubeletOptions(r.SocketPath())
+}
+
+// FindContainer returns the ID of the most recent container of the
+// Kubernetes container name
+func (r *CRIO) FindContainer(cmd CommandRunner, name string) (string, error) {
+	return findCRIContainer(cmd, r.SocketPath(), name)
+}
+
+// ContainerLogCmd returns the command printing the logs of a container
+func (r *CRIO) ContainerLogCmd(id string, since time.Duration, tail int, follow bool) string {
+	return criContainerLogCmd(r.SocketPath(), id, since, tail, follow)
+}
+
+// ContainerRunning returns whether a container is running
+func (r *CRIO) ContainerRunning(cmd CommandRunner, id string) (bool, error) {
+	return criContainerRunning(cmd, r.SocketPath(), id)
+}
+
+// StopContainer stops a container
+func (r *CRIO) StopContainer(cmd CommandRunner, id string) error {
+	return criStopContainer(cmd, r.SocketPath(), id)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cruntime contains the container runtimes the kubelet can be
+// configured to use.
+package cruntime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CommandRunner runs commands on the machine hosting the runtime.
+// It is satisfied by bootstrapper.CommandRunner.
+type CommandRunner interface {
+	Run(cmd string) error
+	CombinedOutput(cmd string) (string, error)
+}
+
+// Manager configures a container runtime.
+type Manager interface {
+	// Name is the canonical name of the runtime
+	Name() string
+	// ServiceName is the systemd unit the kubelet depends on
+	ServiceName() string
+	// Enable starts the runtime. Use the Enable function to also stop the
+	// other runtimes.
+	Enable(CommandRunner) error
+	// Disable stops the runtime
+	Disable(CommandRunner) error
+	// SocketPath is the path of the CRI socket, empty for runtimes the
+	// kubelet talks to natively
+	SocketPath() string
+	// LoadImage loads the image archive at path, which is on the machine
+	LoadImage(cmd CommandRunner, path string) error
+	// ListImages returns the images known to the runtime
+	ListImages(CommandRunner) ([]string, error)
+	// KubeletOptions returns the kubelet flags selecting the runtime
+	KubeletOptions() map[string]string
+	// FindContainer returns the ID of the most recent container of the
+	// Kubernetes container name, empty if there is none
+	FindContainer(cmd CommandRunner, name string) (string, error)
+	// ContainerLogCmd returns the command printing the timestamped logs of
+	// the container id
+	ContainerLogCmd(id string, since time.Duration, tail int, follow bool) string
+	// ContainerRunning returns whether the container id is running
+	ContainerRunning(cmd CommandRunner, id string) (bool, error)
+	// StopContainer stops the container id
+	StopContainer(cmd CommandRunner, id string) error
+}
+
+// Names are the container runtimes that can be passed to --container-runtime
+var Names = []string{"docker", "cri-o", "containerd", "rkt"}
+
+// New returns the runtime with the given name. An empty name selects Docker.
+func New(name string) (Manager, error) {
+	switch name {
+	case "", "docker":
+		return &Docker{}, nil
+	case "crio", "cri-o":
+		return &CRIO{}, nil
+	case "containerd":
+		return &Containerd{}, nil
+	case "rkt":
+		return &Rkt{}, nil
+	default:
+		return nil, fmt.Errorf("unknown container runtime %q, must be one of: %s", name, strings.Join(Names, ", "))
+	}
+}
+
+// Detect returns the runtime whose service is active on the machine. Docker
+// is always running with the provisioner, so the other runtimes are checked
+// first.
+func Detect(cmd CommandRunner) (Manager, error) {
+	others := []Manager{&CRIO{}, &Containerd{}, &Rkt{}}
+	active, err := activeRuntimes(cmd, others)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) > 0 {
+		return active[0], nil
+	}
+	return &Docker{}, nil
+}
+
+// Enable enables r and disables the other runtimes active on the machine,
+// so that only the runtime the kubelet uses is left running.
+func Enable(cmd CommandRunner, r Manager) error {
+	var others []Manager
+	for _, name := range Names {
+		if other, _ := New(name); other.Name() != r.Name() {
+			others = append(others, other)
+		}
+	}
+	active, err := activeRuntimes(cmd, others)
+	if err != nil {
+		return errors.Wrap(err, "checking active runtimes")
+	}
+	for _, other := range active {
+		if err := other.Disable(cmd); err != nil {
+			return errors.Wrapf(err, "disabling %s", other.Name())
+		}
+	}
+	return r.Enable(cmd)
+}
+
+// activeRuntimes returns the runtimes whose service is active, in order.
+func activeRuntimes(cmd CommandRunner, runtimes []Manager) ([]Manager, error) {
+	var units []string
+	for _, r := range runtimes {
+		units = append(units, r.ServiceName())
+	}
+	out, err := cmd.CombinedOutput("sudo systemctl is-active " + strings.Join(units, " ") + " || true")
+	if err != nil {
+		return nil, err
+	}
+	var active []Manager
+	for i, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if i < len(runtimes) && strings.TrimSpace(line) == "active" {
+			active = append(active, runtimes[i])
+		}
+	}
+	return active, nil
+}
+
+// remoteKubeletOptions returns the kubelet flags for a CRI runtime listening
+// on socket.
+func remoteKubeletOptions(socket string) map[string]string {
+	return map[string]string{
+		"container-runtime":          "remote",
+		"container-runtime-endpoint": "unix://" + socket,
+		"image-service-endpoint":     "unix://" + socket,
+		"runtime-request-timeout":    "15m",
+	}
+}
+
+// listImages runs a command printing one image per line.
+func listImages(cmd CommandRunner, listCmd string) ([]string, error) {
+	out, err := cmd.CombinedOutput(listCmd)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			images = append(images, line)
+		}
+	}
+	return images, nil
+}
+
+// logFlags returns the flags of docker and crictl logs selecting the entries
+func logFlags(since time.Duration, tail int, follow bool) string {
+	flags := []string{"--timestamps"}
+	if follow {
+		flags = append(flags, "--follow")
+	}
+	if since > 0 {
+		flags = append(flags, fmt.Sprintf("--since=%ds", int(since.Seconds())))
+	}
+	if tail > 0 {
+		flags = append(flags, fmt.Sprintf("--tail=%d", tail))
+	}
+	return strings.Join(flags, " ")
+}
+
+// crictl returns the crictl command talking to the CRI socket. crictl
+// defaults to the dockershim socket otherwise.
+func crictl(socket string) string {
+	return "sudo crictl --runtime-endpoint unix://" + socket
+}
+
+// findCRIContainer returns the ID of the most recent container of the
+// Kubernetes container name, using crictl
+func findCRIContainer(cmd CommandRunner, socket, name string) (string, error) {
+	ids, err := listImages(cmd, crictl(socket)+" ps -a --quiet --name="+name)
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// criContainerLogCmd returns the command printing the logs of a container
+// using crictl
+func criContainerLogCmd(socket, id string, since time.Duration, tail int, follow bool) string {
+	return fmt.Sprintf("%s logs %s %s", crictl(socket), logFlags(since, tail, follow), id)
+}
+
+// criContainerRunning returns whether a container is running, using crictl
+func criContainerRunning(cmd CommandRunner, socket, id string) (bool, error) {
+	ids, err := listImages(cmd, crictl(socket)+" ps --quiet --state=running --id="+id)
+	return len(ids) > 0, err
+}
+
+// criStopContainer stops a container using crictl
+func criStopContainer(cmd CommandRunner, socket, id string) error {
+	return cmd.Run(crictl(socket) + " stop " + id)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+func TestNew(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expected  string
+		shouldErr bool
+	}{
+		{name: "", expected: "docker"},
+		{name: "docker", expected: "docker"},
+		{name: "crio", expected: "cri-o"},
+		{name: "cri-o", expected: "cri-o"},
+		{name: "containerd", expected: "containerd"},
+		{name: "rkt", expected: "rkt"},
+		{name: "remote", shouldErr: true},
+	}
+	for _, test := range tests {
+		r, err := New(test.name)
+		if err != nil {
+			if !test.shouldErr {
+				t.Errorf("Unexpected error for %q: %s", test.name, err)
+			}
+			continue
+		}
+		if test.shouldErr {
+			t.Errorf("Expected an error for %q", test.name)
+			continue
+		}
+		if r.Name() != test.expected {
+			t.Errorf("Expected runtime %s for %q, got %s", test.expected, test.name, r.Name())
+		}
+	}
+}
+
+func TestKubeletOptions(t *testing.T) {
+	var tests = []struct {
+		runtime  Manager
+		expected map[string]string
+	}{
+		{
+			runtime:  &Docker{},
+			expected: map[string]string{},
+		},
+		{
+			runtime: &CRIO{},
+			expected: map[string]string{
+				"container-runtime":          "remote",
+				"container-runtime-endpoint": "unix:///var/run/crio/crio.sock",
+				"image-service-endpoint":     "unix:///var/run/crio/crio.sock",
+				"runtime-request-timeout":    "15m",
+			},
+		},
+		{
+			runtime: &Containerd{},
+			expected: map[string]string{
+				"container-runtime":          "remote",
+				"container-runtime-endpoint": "unix:///run/containerd/containerd.sock",
+				"image-service-endpoint":     "unix:///run/containerd/containerd.sock",
+				"runtime-request-timeout":    "15m",
+			},
+		},
+		{
+			runtime:  &Rkt{},
+			expected: map[string]string{"container-runtime": "rkt"},
+		},
+	}
+	for _, test := range tests {
+		if opts := test.runtime.KubeletOptions(); !reflect.DeepEqual(opts, test.expected) {
+			t.Errorf("Unexpected kubelet options for %s. Expected %v, got %v", test.runtime.Name(), test.expected, opts)
+		}
+	}
+}
+
+func TestLoadImage(t *testing.T) {
+	var tests = []struct {
+		runtime Manager
+		cmd     string
+	}{
+		{runtime: &Docker{}, cmd: "docker load -i /tmp/pause_3.0"},
+		{runtime: &Containerd{}, cmd: "sudo ctr -n=k8s.io images import /tmp/pause_3.0"},
+	}
+	for _, test := range tests {
+		f := bootstrapper.NewFakeCommandRunner()
+		if err := test.runtime.LoadImage(f, "/tmp/pause_3.0"); err == nil {
+			t.Errorf("Expected an error loading with %s without the load command", test.runtime.Name())
+		}
+		f.SetCommandToOutput(map[string]string{test.cmd: ""})
+		if err := test.runtime.LoadImage(f, "/tmp/pause_3.0"); err != nil {
+			t.Errorf("Error loading image with %s: %s", test.runtime.Name(), err)
+		}
+	}
+}
+
+func TestListImages(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo ctr -n=k8s.io images list --quiet": "gcr.io/k8s-minikube/storage-provisioner:v1.8.1\n\nk8s.gcr.io/pause-amd64:3.0\n",
+	})
+	images, err := (&Containerd{}).ListImages(f)
+	if err != nil {
+		t.Fatalf("Error listing images: %s", err)
+	}
+	expected := []string{"gcr.io/k8s-minikube/storage-provisioner:v1.8.1", "k8s.gcr.io/pause-amd64:3.0"}
+	if !reflect.DeepEqual(images, expected) {
+		t.Fatalf("Expected images %v, got %v", expected, images)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cmd := "sudo systemctl is-active crio.service containerd.service rkt-api.service || true"
+	var tests = []struct {
+		output   string
+		expected string
+	}{
+		{output: "inactive\ninactive\ninactive\n", expected: "docker"},
+		{output: "active\ninactive\ninactive\n", expected: "cri-o"},
+		{output: "inactive\nactive\ninactive\n", expected: "containerd"},
+		{output: "inactive\ninactive\nactive\n", expected: "rkt"},
+	}
+	for _, test := range tests {
+		f := bootstrapper.NewFakeCommandRunner()
+		f.SetCommandToOutput(map[string]string{cmd: test.output})
+		r, err := Detect(f)
+		if err != nil {
+			t.Errorf("Unexpected error detecting runtime: %s", err)
+			continue
+		}
+		if r.Name() != test.expected {
+			t.Errorf("Expected runtime %s for %q, got %s", test.expected, test.output, r.Name())
+		}
+	}
+}
+
+func TestEnable(t *testing.T) {
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"sudo systemctl is-active docker.socket crio.service rkt-api.service || true": "active\ninactive\ninactive\n",
+	})
+	if err := Enable(f, &Containerd{}); err == nil {
+		t.Fatal("Expected an error enabling containerd without stopping docker")
+	}
+	f.SetCommandToOutput(map[string]string{
+		"sudo systemctl stop docker docker.socket": "",
+	})
+	if err := Enable(f, &Containerd{}); err == nil {
+		t.Fatal("Expected an error enabling containerd without starting it")
+	}
+	f.SetCommandToOutput(map[string]string{
+		"sudo systemctl start containerd": "",
+	})
+	if err := Enable(f, &Containerd{}); err != nil {
+		t.Fatalf("Error enabling containerd: %s", err)
+	}
+}
+
+func TestFindContainer(t *testing.T) {
+	var tests = []struct {
+		runtime Manager
+		cmd     string
+	}{
+		{runtime: &Docker{}, cmd: "docker ps -a --filter=name=k8s_etcd --format={{.ID}}"},
+		{runtime: &CRIO{}, cmd: "sudo crictl --runtime-endpoint unix:///var/run/crio/crio.sock ps -a --quiet --name=etcd"},
+		{runtime: &Containerd{}, cmd: "sudo crictl --runtime-endpoint unix:///run/containerd/containerd.sock ps -a --quiet --name=etcd"},
+	}
+	for _, test := range tests {
+		f := bootstrapper.NewFakeCommandRunner()
+		f.SetCommandToOutput(map[string]string{test.cmd: "e2\ne1\n"})
+		id, err := test.runtime.FindContainer(f, "etcd")
+		if err != nil {
+			t.Errorf("Error finding container with %s: %s", test.runtime.Name(), err)
+		}
+		if id != "e2" {
+			t.Errorf("Expected %s to find the most recent container e2, got %q", test.runtime.Name(), id)
+		}
+	}
+}
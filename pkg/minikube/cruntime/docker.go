@@ -0,0 +1,101 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Docker is the default runtime, which the kubelet talks to with dockershim.
+type Docker struct{}
+
+// Name is the canonical name of the runtime
+func (r *Docker) Name() string {
+	return "docker"
+}
+
+// ServiceName is the systemd unit the kubelet depends on
+func (r *Docker) ServiceName() string {
+	return "docker.socket"
+}
+
+// Enable starts the docker daemon, which the machine provisioner starts
+// too, unless another runtime was enabled since.
+func (r *Docker) Enable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl start docker")
+}
+
+// Disable stops the docker daemon
+func (r *Docker) Disable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl stop docker docker.socket")
+}
+
+// SocketPath is empty, the kubelet uses its builtin dockershim
+func (r *Docker) SocketPath() string {
+	return ""
+}
+
+// LoadImage loads an image archive with docker load
+func (r *Docker) LoadImage(cmd CommandRunner, path string) error {
+	if err := cmd.Run("docker load -i " + path); err != nil {
+		return errors.Wrapf(err, "loading docker image: %s", path)
+	}
+	return nil
+}
+
+// ListImages returns the images known to the docker daemon
+func (r *Docker) ListImages(cmd CommandRunner) ([]string, error) {
+	return listImages(cmd, `docker images --format "{{.Repository}}:{{.Tag}}"`)
+}
+
+// KubeletOptions returns no flags, docker is the kubelet default
+func (r *Docker) KubeletOptions() map[string]string {
+	return map[string]string{}
+}
+
+// FindContainer returns the ID of the most recent container of the
+// Kubernetes container name, as named by dockershim
+func (r *Docker) FindContainer(cmd CommandRunner, name string) (string, error) {
+	ids, err := listImages(cmd, "docker ps -a --filter=name=k8s_"+name+" --format={{.ID}}")
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// ContainerLogCmd returns the command printing the logs of a container
+func (r *Docker) ContainerLogCmd(id string, since time.Duration, tail int, follow bool) string {
+	return fmt.Sprintf("docker logs %s %s", logFlags(since, tail, follow), id)
+}
+
+// ContainerRunning returns whether a container is running
+func (r *Docker) ContainerRunning(cmd CommandRunner, id string) (bool, error) {
+	out, err := cmd.CombinedOutput("docker inspect --format={{.State.Running}} " + id)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
+
+// StopContainer stops a container
+func (r *Docker) StopContainer(cmd CommandRunner, id string) error {
+	return cmd.Run("docker stop " + id)
+}
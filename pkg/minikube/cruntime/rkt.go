@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cruntime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rkt is the rkt runtime, which the kubelet talks to with rktnetes
+type Rkt struct{}
+
+// Name is the canonical name of the runtime
+func (r *Rkt) Name() string {
+	return "rkt"
+}
+
+// ServiceName is the systemd unit the kubelet depends on
+func (r *Rkt) ServiceName() string {
+	return "rkt-api.service"
+}
+
+// Enable starts the rkt api service
+func (r *Rkt) Enable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl start rkt-api")
+}
+
+// Disable stops the rkt api service
+func (r *Rkt) Disable(cmd CommandRunner) error {
+	return cmd.Run("sudo systemctl stop rkt-api")
+}
+
+// SocketPath is empty, rkt is not a CRI runtime
+func (r *Rkt) SocketPath() string {
+	return ""
+}
+
+// LoadImage is not supported, rkt can not import docker archives
+func (r *Rkt) LoadImage(cmd CommandRunner, path string) error {
+	return fmt.Errorf("rkt does not support loading image archives: %s", path)
+}
+
+// ListImages returns the images in the rkt store
+func (r *Rkt) ListImages(cmd CommandRunner) ([]string, error) {
+	return listImages(cmd, "sudo rkt image list --no-legend --fields=name")
+}
+
+// KubeletOptions returns the flags selecting rkt
+func (r *Rkt) KubeletOptions() map[string]string {
+	return map[string]string{"container-runtime": "rkt"}
+}
+
+// FindContainer is not supported, rkt does not name containers like the CRI
+func (r *Rkt) FindContainer(cmd CommandRunner, name string) (string, error) {
+	return "", fmt.Errorf("rkt does not support finding the %s container", name)
+}
+
+// ContainerLogCmd is not supported, there are no containers to log as
+// FindContainer always fails
+func (r *Rkt) ContainerLogCmd(id string, since time.Duration, tail int, follow bool) string {
+	return ""
+}
+
+// ContainerRunning is not supported, as FindContainer always fails
+func (r *Rkt) ContainerRunning(cmd CommandRunner, id string) (bool, error) {
+	return false, fmt.Errorf("rkt does not support container status")
+}
+
+// StopContainer is not supported, as FindContainer always fails
+func (r *Rkt) StopContainer(cmd CommandRunner, id string) error {
+	return fmt.Errorf("rkt does not support stopping containers")
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diagnose collects the state of a cluster into an archive that can
+// be attached to bug reports.
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+)
+
+// errorsFile lists the files that could not be collected
+const errorsFile = "errors.txt"
+
+// Redacted replaces secrets in collected files
+const Redacted = "REDACTED"
+
+// Collector gathers files into a diagnostic bundle. Failing to collect a file
+// does not stop the collection, the error is recorded in the bundle instead.
+type Collector struct {
+	names []string
+	files map[string][]byte
+	errs  []string
+}
+
+// NewCollector returns an empty Collector
+func NewCollector() *Collector {
+	return &Collector{files: map[string][]byte{}}
+}
+
+// Add adds a file to the bundle, with the credentials of URLs redacted.
+func (c *Collector) Add(name string, data []byte) {
+	if _, ok := c.files[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.files[name] = RedactURLs(data)
+}
+
+// AddFunc adds the file returned by fn to the bundle. If fn fails, its error
+// is recorded, along with any partial output.
+func (c *Collector) AddFunc(name string, fn func() ([]byte, error)) {
+	data, err := fn()
+	if err != nil {
+		c.Fail(name, err)
+	}
+	if len(data) > 0 {
+		c.Add(name, data)
+	}
+}
+
+// AddCommand adds the output of a command run on the machine to the bundle.
+func (c *Collector) AddCommand(name string, cmd bootstrapper.CommandRunner, command string) {
+	c.AddFunc(name, func() ([]byte, error) {
+		out, err := cmd.CombinedOutput(command)
+		return []byte(out), err
+	})
+}
+
+// AddJSONFile adds a JSON file from the host to the bundle, with secrets
+// redacted.
+func (c *Collector) AddJSONFile(name, path string) {
+	c.AddFunc(name, func() ([]byte, error) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return RedactJSON(data)
+	})
+}
+
+// Fail records that a file could not be collected.
+func (c *Collector) Fail(name string, err error) {
+	glog.Warningf("Collecting %s: %v", name, err)
+	c.errs = append(c.errs, fmt.Sprintf("%s: %v", name, err))
+}
+
+// Errors returns the files that could not be collected, and why.
+func (c *Collector) Errors() []string {
+	return c.errs
+}
+
+// Write writes the bundle to a gzipped tarball at path. The files are in a
+// directory named after the bundle.
+func (c *Collector) Write(path string, created time.Time) error {
+	if len(c.errs) > 0 {
+		c.Add(errorsFile, []byte(strings.Join(c.errs, "\n")+"\n"))
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+	dir := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".gz"), ".tar")
+	if err := c.write(f, dir, created); err != nil {
+		f.Close()
+		os.Remove(path)
+		return errors.Wrap(err, "writing bundle")
+	}
+	return f.Close()
+}
+
+func (c *Collector) write(w io.Writer, dir string, created time.Time) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, name := range c.names {
+		data := c.files[name]
+		h := &tar.Header{Name: dir + "/" + name, Mode: 0644, Size: int64(len(data)), ModTime: created}
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// secretKey matches the keys of JSON values that are redacted. Header values,
+// such as those of ReportErrorHeaders, are redacted wholesale as they often
+// carry bearer tokens.
+var secretKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|apikey|api_key|private|authorization|cookie|headers)`)
+
+// urlCredentials matches the user info of URLs, such as proxy passwords
+var urlCredentials = regexp.MustCompile(`(://)[^/\s:@"']+:[^/\s@"']+@`)
+
+// RedactURLs replaces the credentials of the URLs in data.
+func RedactURLs(data []byte) []byte {
+	return urlCredentials.ReplaceAll(data, []byte("${1}"+Redacted+"@"))
+}
+
+// RedactJSON replaces the values of the keys of a JSON document that look
+// like secrets, and the credentials of URLs.
+func RedactJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Wrap(err, "parsing JSON")
+	}
+	out, err := json.MarshalIndent(redact(doc), "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return RedactURLs(out), nil
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if s, ok := val.(string); ok && s != "" && secretKey.MatchString(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redact(val)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+	}
+	return v
+}
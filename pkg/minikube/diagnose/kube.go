@@ -0,0 +1,100 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnose
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Pods returns the pods of all namespaces, like kubectl get pods --all-namespaces
+func Pods(client corev1.CoreV1Interface, now time.Time) ([]byte, error) {
+	pods, err := client.Pods("").List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing pods")
+	}
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\tNODE")
+	for _, p := range pods.Items {
+		ready, restarts := 0, int32(0)
+		status := string(p.Status.Phase)
+		for _, c := range p.Status.ContainerStatuses {
+			if c.Ready {
+				ready++
+			}
+			restarts += c.RestartCount
+			if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+				status = c.State.Waiting.Reason
+			} else if c.State.Terminated != nil && c.State.Terminated.Reason != "" {
+				status = c.State.Terminated.Reason
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t%d\t%s\t%s\n", p.Namespace, p.Name, ready, len(p.Spec.Containers),
+			status, restarts, age(now, p.CreationTimestamp.Time), p.Spec.NodeName)
+	}
+	w.Flush()
+	return b.Bytes(), nil
+}
+
+// Events returns the events of all namespaces, oldest first, like kubectl get
+// events --all-namespaces
+func Events(client corev1.CoreV1Interface, now time.Time) ([]byte, error) {
+	events, err := client.Events("").List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing events")
+	}
+	items := events.Items
+	sort.SliceStable(items, func(i, j int) bool { return items[i].LastTimestamp.Before(&items[j].LastTimestamp) })
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tLAST SEEN\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for _, e := range items {
+		object := fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", e.Namespace, age(now, e.LastTimestamp.Time),
+			e.Type, e.Reason, object, e.Count, e.Message)
+	}
+	w.Flush()
+	return b.Bytes(), nil
+}
+
+// age formats the time elapsed since t like kubectl
+func age(now, t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < 0:
+		return "0s"
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
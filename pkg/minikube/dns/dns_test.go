@@ -0,0 +1,244 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// freeAddr returns a free local address to listen on
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func TestServer(t *testing.T) {
+	// the cluster DNS answers every query for a service with 10.96.0.20
+	clusterAddr := freeAddr(t)
+	cluster := &dns.Server{Addr: clusterAddr, Net: "tcp", Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+		m.Answer = append(m.Answer, &dns.A{
+			Hdr: dns.RR_Header{Name: r.Question[0].Name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 5},
+			A:   net.ParseIP("10.96.0.20").To4(),
+		})
+		w.WriteMsg(m)
+	})}
+	started := make(chan struct{})
+	cluster.NotifyStartedFunc = func() { close(started) }
+	go cluster.ListenAndServe()
+	<-started
+	defer cluster.Shutdown()
+
+	s := &Server{
+		Domain:        "minikube.test",
+		HostIP:        func() (net.IP, error) { return net.ParseIP("192.168.99.100"), nil },
+		ClusterDomain: "svc.cluster.local",
+		ClusterDNS:    clusterAddr,
+		Dial:          net.Dial,
+		TTL:           30,
+	}
+	addr := freeAddr(t)
+	done := make(chan struct{})
+	errs := make(chan error)
+	go func() {
+		errs <- s.ListenAndServe(addr, done)
+	}()
+
+	// wait for the server to listen on both UDP and TCP
+	for _, net := range []string{"udp", "tcp"} {
+		c := &dns.Client{Net: net}
+		m := new(dns.Msg)
+		m.SetQuestion("minikube.test.", dns.TypeA)
+		for i := 0; i < 50; i++ {
+			if _, _, err := c.Exchange(m, addr); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	var tests = []struct {
+		name   string
+		qtype  uint16
+		rcode  int
+		answer string
+	}{
+		{"app.minikube.test.", dns.TypeA, dns.RcodeSuccess, "192.168.99.100"},
+		{"A.B.Minikube.Test.", dns.TypeA, dns.RcodeSuccess, "192.168.99.100"},
+		{"app.minikube.test.", dns.TypeAAAA, dns.RcodeSuccess, ""},
+		{"web.default.svc.cluster.local.", dns.TypeA, dns.RcodeSuccess, "10.96.0.20"},
+		{"kubernetes.io.", dns.TypeA, dns.RcodeRefused, ""},
+	}
+	for _, net := range []string{"udp", "tcp"} {
+		c := &dns.Client{Net: net}
+		for _, test := range tests {
+			m := new(dns.Msg)
+			m.SetQuestion(test.name, test.qtype)
+			r, _, err := c.Exchange(m, addr)
+			if err != nil {
+				t.Fatalf("%s %s: unexpected error: %s", net, test.name, err)
+			}
+			if r.Rcode != test.rcode {
+				t.Errorf("%s %s: rcode = %s, expected %s", net, test.name, dns.RcodeToString[r.Rcode], dns.RcodeToString[test.rcode])
+			}
+			answer := ""
+			if len(r.Answer) > 0 {
+				answer = r.Answer[0].(*dns.A).A.String()
+			}
+			if answer != test.answer {
+				t.Errorf("%s %s: answer = %q, expected %q", net, test.name, answer, test.answer)
+			}
+		}
+	}
+
+	close(done)
+	if err := <-errs; err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestServerIPv6(t *testing.T) {
+	s := &Server{
+		Domain: "minikube.test",
+		HostIP: func() (net.IP, error) { return net.ParseIP("fd00::100"), nil },
+		TTL:    30,
+	}
+	var tests = []struct {
+		qtype  uint16
+		answer string
+	}{
+		{dns.TypeA, ""},
+		{dns.TypeAAAA, "fd00::100"},
+		{dns.TypeANY, "fd00::100"},
+	}
+	for _, test := range tests {
+		m := new(dns.Msg)
+		m.SetQuestion("app.minikube.test.", test.qtype)
+		r, err := s.answer(m)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", dns.TypeToString[test.qtype], err)
+		}
+		answer := ""
+		if len(r.Answer) > 0 {
+			answer = r.Answer[0].(*dns.AAAA).AAAA.String()
+		}
+		if answer != test.answer {
+			t.Errorf("%s: answer = %q, expected %q", dns.TypeToString[test.qtype], answer, test.answer)
+		}
+	}
+}
+
+func TestServerFailures(t *testing.T) {
+	s := &Server{
+		Domain:        "minikube.test",
+		HostIP:        func() (net.IP, error) { return nil, errors.New("VM is stopped") },
+		ClusterDomain: "svc.cluster.local",
+		ClusterDNS:    "10.96.0.10:53",
+		Dial: func(string, string) (net.Conn, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	for _, name := range []string{"app.minikube.test.", "web.default.svc.cluster.local."} {
+		m := new(dns.Msg)
+		m.SetQuestion(name, dns.TypeA)
+		if _, err := s.answer(m); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestResolverConfig(t *testing.T) {
+	domains := []string{"minikube.test", "svc.cluster.local"}
+	var tests = []struct {
+		resolver string
+		expected string
+	}{
+		{ResolverSystemd, "[Resolve]\nDNS=127.0.0.1:10053\nDomains=~minikube.test ~svc.cluster.local\n"},
+		{ResolverNetworkManager, "server=/minikube.test/127.0.0.1#10053\nserver=/svc.cluster.local/127.0.0.1#10053\n"},
+	}
+	for _, test := range tests {
+		config, err := ResolverConfig(test.resolver, "127.0.0.1:10053", domains)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", test.resolver, err)
+		}
+		if !strings.HasSuffix(config, test.expected) {
+			t.Errorf("%s: config = %q, expected it to end with %q", test.resolver, config, test.expected)
+		}
+	}
+	if _, err := ResolverConfig("dnscrypt", "127.0.0.1:10053", domains); err == nil {
+		t.Errorf("Expected an error for an unknown resolver")
+	}
+}
+
+func TestInstallResolverConfig(t *testing.T) {
+	var commands []string
+	var written string
+	orig := runCommand
+	runCommand = func(stdin []byte, name string, args ...string) error {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		if stdin != nil {
+			written = string(stdin)
+		}
+		return nil
+	}
+	defer func() { runCommand = orig }()
+	version := "systemd 245 (245.4-4ubuntu3)\n+PAM +AUDIT\n"
+	origOutput := commandOutput
+	commandOutput = func(name string, args ...string) ([]byte, error) {
+		return []byte(version), nil
+	}
+	defer func() { commandOutput = origOutput }()
+
+	if err := InstallResolverConfig(ResolverSystemd, "minikube", "127.0.0.1:10053", []string{"minikube.test"}); err == nil {
+		t.Fatal("Expected an error with systemd 245")
+	}
+	if len(commands) != 0 {
+		t.Fatalf("Expected no commands with systemd 245, got %v", commands)
+	}
+	version = "systemd 246 (246.6-1)\n"
+	if err := InstallResolverConfig(ResolverSystemd, "minikube", "127.0.0.1:10053", []string{"minikube.test"}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := UninstallResolverConfig(ResolverSystemd, "minikube"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []string{
+		"sudo mkdir -p /etc/systemd/resolved.conf.d",
+		"sudo tee /etc/systemd/resolved.conf.d/minikube-minikube.conf",
+		"sudo systemctl restart systemd-resolved",
+		"sudo rm -f /etc/systemd/resolved.conf.d/minikube-minikube.conf",
+		"sudo systemctl restart systemd-resolved",
+	}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Errorf("Commands = %v, expected %v", commands, expected)
+	}
+	if !strings.Contains(written, "Domains=~minikube.test\n") {
+		t.Errorf("Unexpected config written: %q", written)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// The host resolvers which minikube dns install can configure
+const (
+	ResolverSystemd        = "systemd-resolved"
+	ResolverNetworkManager = "NetworkManager"
+)
+
+var resolverConfigDirs = map[string]string{
+	ResolverSystemd:        "/etc/systemd/resolved.conf.d",
+	ResolverNetworkManager: "/etc/NetworkManager/dnsmasq.d",
+}
+
+var resolverReloadCommands = map[string][]string{
+	ResolverSystemd:        {"sudo", "systemctl", "restart", "systemd-resolved"},
+	ResolverNetworkManager: {"sudo", "systemctl", "reload", "NetworkManager"},
+}
+
+// runCommand runs a command on the host with stdin as input. The command may
+// prompt for a sudo password, so its standard error goes to the terminal. Its
+// standard output is discarded.
+var runCommand = func(stdin []byte, name string, args ...string) error {
+	glog.Infof("Running %s %s", name, strings.Join(args, " "))
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	} else {
+		cmd.Stdin = os.Stdin
+	}
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// commandOutput runs a command on the host and returns its output
+var commandOutput = func(name string, args ...string) ([]byte, error) {
+	glog.Infof("Running %s %s", name, strings.Join(args, " "))
+	return exec.Command(name, args...).Output()
+}
+
+// minSystemdVersion is the first systemd whose resolved accepts a port in DNS=
+const minSystemdVersion = 246
+
+// checkSystemdVersion returns an error if systemd-resolved is too old to
+// send queries to a port other than 53
+func checkSystemdVersion() error {
+	out, err := commandOutput("systemctl", "--version")
+	if err != nil {
+		return errors.Wrap(err, "getting the systemd version")
+	}
+	// the first line is "systemd 246 (246.6-1)"
+	var version int
+	if _, err := fmt.Sscanf(string(out), "systemd %d", &version); err != nil {
+		return errors.Wrapf(err, "parsing the systemd version %q", strings.SplitN(string(out), "\n", 2)[0])
+	}
+	if version < minSystemdVersion {
+		return errors.Errorf("systemd-resolved needs systemd %d or newer to use a DNS port other than 53, found %d", minSystemdVersion, version)
+	}
+	return nil
+}
+
+// DetectResolver returns the resolver of the host, which is either
+// systemd-resolved, or NetworkManager running dnsmasq
+func DetectResolver() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errors.Errorf("configuring the resolver is not supported on %s", runtime.GOOS)
+	}
+	for _, r := range []string{ResolverSystemd, ResolverNetworkManager} {
+		if err := runCommand(nil, "systemctl", "--quiet", "is-active", r); err == nil {
+			return r, nil
+		}
+	}
+	return "", errors.New("neither systemd-resolved nor NetworkManager is running")
+}
+
+// ResolverConfigPath returns the file configuring resolver for profile
+func ResolverConfigPath(resolver, profile string) string {
+	return filepath.Join(resolverConfigDirs[resolver], fmt.Sprintf("minikube-%s.conf", profile))
+}
+
+// ResolverConfig returns the configuration sending the queries for domains
+// to the DNS responder listening on addr
+func ResolverConfig(resolver, addr string, domains []string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing listen address %s", addr)
+	}
+	var b bytes.Buffer
+	b.WriteString("# Written by minikube dns install, removed by minikube dns uninstall\n")
+	switch resolver {
+	case ResolverSystemd:
+		var routes []string
+		for _, d := range domains {
+			routes = append(routes, "~"+strings.TrimSuffix(d, "."))
+		}
+		fmt.Fprintf(&b, "[Resolve]\nDNS=%s\nDomains=%s\n", net.JoinHostPort(host, port), strings.Join(routes, " "))
+	case ResolverNetworkManager:
+		for _, d := range domains {
+			fmt.Fprintf(&b, "server=/%s/%s#%s\n", strings.TrimSuffix(d, "."), host, port)
+		}
+	default:
+		return "", errors.Errorf("unknown resolver %s", resolver)
+	}
+	return b.String(), nil
+}
+
+// InstallResolverConfig makes resolver send the queries for domains to the
+// DNS responder of profile listening on addr
+func InstallResolverConfig(resolver, profile, addr string, domains []string) error {
+	config, err := ResolverConfig(resolver, addr, domains)
+	if err != nil {
+		return err
+	}
+	if resolver == ResolverSystemd {
+		if err := checkSystemdVersion(); err != nil {
+			return err
+		}
+	}
+	path := ResolverConfigPath(resolver, profile)
+	if err := runCommand(nil, "sudo", "mkdir", "-p", filepath.Dir(path)); err != nil {
+		return errors.Wrapf(err, "making %s", filepath.Dir(path))
+	}
+	// tee gets the path as an argument, so that it is not parsed by a shell
+	if err := runCommand([]byte(config), "sudo", "tee", path); err != nil {
+		return errors.Wrapf(err, "writing %s", path)
+	}
+	return reloadResolver(resolver)
+}
+
+// UninstallResolverConfig removes the configuration written by
+// InstallResolverConfig
+func UninstallResolverConfig(resolver, profile string) error {
+	path := ResolverConfigPath(resolver, profile)
+	if err := runCommand(nil, "sudo", "rm", "-f", path); err != nil {
+		return errors.Wrapf(err, "removing %s", path)
+	}
+	return reloadResolver(resolver)
+}
+
+func reloadResolver(resolver string) error {
+	c := resolverReloadCommands[resolver]
+	if err := runCommand(nil, c[0], c[1:]...); err != nil {
+		return errors.Wrapf(err, "reloading %s", resolver)
+	}
+	return nil
+}
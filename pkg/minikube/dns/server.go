@@ -0,0 +1,195 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+)
+
+// forwardTimeout bounds a query forwarded to the cluster DNS
+const forwardTimeout = 5 * time.Second
+
+// Server answers DNS queries from the host for the names of a cluster:
+// every name in Domain resolves to the VM, so that the hosts of Ingresses
+// work without editing /etc/hosts, and queries for ClusterDomain are
+// forwarded to the cluster DNS.
+type Server struct {
+	// Domain is answered with the IP returned by HostIP, such as minikube.test
+	Domain string
+	// HostIP returns the IP of the VM
+	HostIP func() (net.IP, error)
+	// ClusterDomain is forwarded to ClusterDNS, such as svc.cluster.local
+	ClusterDomain string
+	// ClusterDNS is the host:port of the cluster DNS
+	ClusterDNS string
+	// Dial connects to ClusterDNS, such as through SSH to the VM
+	Dial func(network, addr string) (net.Conn, error)
+	// TTL of the answers for Domain, in seconds
+	TTL uint32
+}
+
+// ServeDNS implements dns.Handler
+func (s *Server) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m, err := s.answer(r)
+	if err != nil {
+		glog.Warningf("Error answering %v: %s", r.Question, err)
+		m = new(dns.Msg)
+		m.SetRcode(r, dns.RcodeServerFailure)
+	}
+	if err := w.WriteMsg(m); err != nil {
+		glog.Warningf("Error writing DNS response: %s", err)
+	}
+}
+
+func (s *Server) answer(r *dns.Msg) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	if len(r.Question) != 1 {
+		m.SetRcode(r, dns.RcodeFormatError)
+		return m, nil
+	}
+	q := r.Question[0]
+	name := strings.ToLower(q.Name)
+	switch {
+	case dns.IsSubDomain(dns.Fqdn(s.Domain), name):
+		m.SetReply(r)
+		m.Authoritative = true
+		if q.Qclass != dns.ClassINET || (q.Qtype != dns.TypeA && q.Qtype != dns.TypeAAAA && q.Qtype != dns.TypeANY) {
+			// the name exists, but only has an address record
+			return m, nil
+		}
+		ip, err := s.HostIP()
+		if err != nil {
+			return nil, errors.Wrap(err, "getting VM IP")
+		}
+		hdr := dns.RR_Header{Name: q.Name, Class: dns.ClassINET, Ttl: s.TTL}
+		if ip4 := ip.To4(); ip4 != nil {
+			if q.Qtype != dns.TypeAAAA {
+				hdr.Rrtype = dns.TypeA
+				m.Answer = append(m.Answer, &dns.A{Hdr: hdr, A: ip4})
+			}
+		} else if q.Qtype != dns.TypeA {
+			hdr.Rrtype = dns.TypeAAAA
+			m.Answer = append(m.Answer, &dns.AAAA{Hdr: hdr, AAAA: ip})
+		}
+		return m, nil
+	case s.ClusterDomain != "" && dns.IsSubDomain(dns.Fqdn(s.ClusterDomain), name):
+		return s.forward(r)
+	default:
+		m.SetRcode(r, dns.RcodeRefused)
+		return m, nil
+	}
+}
+
+// forward sends r to the cluster DNS over TCP, which, unlike UDP, can be
+// carried by an SSH connection to the VM
+func (s *Server) forward(r *dns.Msg) (*dns.Msg, error) {
+	conn, err := s.Dial("tcp", s.ClusterDNS)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to cluster DNS %s", s.ClusterDNS)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(forwardTimeout))
+
+	b, err := r.Pack()
+	if err != nil {
+		return nil, errors.Wrap(err, "packing query")
+	}
+	// DNS over TCP prefixes every message with its length
+	l := make([]byte, 2, len(b)+2)
+	binary.BigEndian.PutUint16(l, uint16(len(b)))
+	if _, err := conn.Write(append(l, b...)); err != nil {
+		return nil, errors.Wrap(err, "sending query to cluster DNS")
+	}
+	if _, err := io.ReadFull(conn, l); err != nil {
+		return nil, errors.Wrap(err, "reading cluster DNS response")
+	}
+	b = make([]byte, binary.BigEndian.Uint16(l))
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return nil, errors.Wrap(err, "reading cluster DNS response")
+	}
+	m := new(dns.Msg)
+	if err := m.Unpack(b); err != nil {
+		return nil, errors.Wrap(err, "unpacking cluster DNS response")
+	}
+	m.Id = r.Id
+	return m, nil
+}
+
+// ListenAndServe answers queries on addr, over UDP and TCP, until done is
+// closed
+func (s *Server) ListenAndServe(addr string, done <-chan struct{}) error {
+	servers := []*dns.Server{
+		{Addr: addr, Net: "udp", Handler: s},
+		{Addr: addr, Net: "tcp", Handler: s},
+	}
+	errs := make(chan error, len(servers))
+	var started sync.WaitGroup
+	for _, srv := range servers {
+		started.Add(1)
+		var once sync.Once
+		srv.NotifyStartedFunc = func() { once.Do(started.Done) }
+		go func(srv *dns.Server) {
+			err := srv.ListenAndServe()
+			// unblock the wait if the server failed to start
+			srv.NotifyStartedFunc()
+			if err != nil {
+				errs <- errors.Wrapf(err, "serving on %s/%s", addr, srv.Net)
+			}
+		}(srv)
+	}
+	started.Wait()
+
+	var err error
+	select {
+	case err = <-errs:
+	case <-done:
+	}
+	for _, srv := range servers {
+		srv.Shutdown()
+	}
+	return err
+}
+
+// CachedIP returns a func returning the IP returned by get, which is only
+// called again once ttl has passed, as getting the IP of a VM can be slow
+func CachedIP(get func() (net.IP, error), ttl time.Duration) func() (net.IP, error) {
+	var mu sync.Mutex
+	var ip net.IP
+	var expires time.Time
+	return func() (net.IP, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ip != nil && time.Now().Before(expires) {
+			return ip, nil
+		}
+		got, err := get()
+		if err != nil {
+			return nil, err
+		}
+		ip, expires = got, time.Now().Add(ttl)
+		return ip, nil
+	}
+}
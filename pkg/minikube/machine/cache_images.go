@@ -17,12 +17,17 @@ limitations under the License.
 package machine
 
 import (
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -30,6 +35,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/sshutil"
 
 	"github.com/containers/image/copy"
@@ -37,6 +43,7 @@ import (
 	"github.com/containers/image/docker/archive"
 	"github.com/containers/image/signature"
 	"github.com/containers/image/types"
+	units "github.com/docker/go-units"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 )
@@ -51,6 +58,7 @@ func CacheImagesForBootstrapper(version string, clusterBootstrapper string) erro
 	if err := CacheImages(images, constants.ImageCacheDir); err != nil {
 		return errors.Wrapf(err, "Caching images for %s", clusterBootstrapper)
 	}
+	RecordImageUse(constants.ImageCacheDir, images, version)
 
 	return nil
 }
@@ -65,8 +73,7 @@ func CacheImages(images []string, cacheDir string) error {
 	for _, image := range images {
 		image := image
 		g.Go(func() error {
-			dst := filepath.Join(cacheDir, image)
-			dst = sanitizeCacheDir(dst)
+			dst := CachedImagePath(cacheDir, image)
 			if err := CacheImage(image, dst); err != nil {
 				return errors.Wrapf(err, "caching image %s", dst)
 			}
@@ -76,31 +83,193 @@ func CacheImages(images []string, cacheDir string) error {
 	if err := g.Wait(); err != nil {
 		return errors.Wrap(err, "caching images")
 	}
+	RecordImageUse(cacheDir, images, "")
 	glog.Infoln("Successfully cached all images.")
 	return nil
 }
 
-func LoadImages(cmd bootstrapper.CommandRunner, images []string, cacheDir string) error {
-	var g errgroup.Group
+// maxParallelImageLoads bounds the number of images copied to and loaded
+// into the machine at the same time
+var maxParallelImageLoads = 4
+
+// ImageLoadError is the failure to load one image
+type ImageLoadError struct {
+	Image string
+	Err   error
+}
+
+// ImageLoadErrors are the images that failed to load, and why
+type ImageLoadErrors []ImageLoadError
+
+func (e ImageLoadErrors) Error() string {
+	lines := []string{fmt.Sprintf("%d images failed to load:", len(e))}
+	for _, ie := range e {
+		lines = append(lines, fmt.Sprintf("  %s: %v", ie.Image, ie.Err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadCachedImages loads the cached images the container runtime r does not
+// have yet while the cluster is set up, reporting progress on stdout. Images
+// failing to load are reported and left for the container runtime to pull.
+func LoadCachedImages(cmd bootstrapper.CommandRunner, r cruntime.Manager, images []string) {
+	images, present := missingImages(cmd, r, images)
+	RecordImageUse(constants.ImageCacheDir, present, "")
+	if len(images) == 0 {
+		fmt.Println("All cached images are already loaded.")
+		return
+	}
+	fmt.Printf("Loading %d cached images...\n", len(images))
+	if err := LoadImagesWithProgress(cmd, r, images, constants.ImageCacheDir, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: %v\nThe container runtime will pull them instead.\n", err)
+	}
+}
+
+// missingImages splits the images into those the container runtime r does
+// not have and those it has. All of them are missing when the images of r
+// cannot be listed.
+func missingImages(cmd bootstrapper.CommandRunner, r cruntime.Manager, images []string) (missing, present []string) {
+	listed, err := r.ListImages(cmd)
+	if err != nil {
+		glog.Warningf("Error listing the images of %s, loading all of them: %v", r.Name(), err)
+		return images, nil
+	}
+	have := map[string]bool{}
+	for _, image := range listed {
+		have[shortImageName(image)] = true
+	}
 	for _, image := range images {
-		image := image
-		g.Go(func() error {
-			src := filepath.Join(cacheDir, image)
-			src = sanitizeCacheDir(src)
-			if err := LoadFromCacheBlocking(cmd, src); err != nil {
-				return errors.Wrapf(err, "loading image %s", src)
+		if have[shortImageName(image)] {
+			present = append(present, image)
+		} else {
+			missing = append(missing, image)
+		}
+	}
+	return missing, present
+}
+
+// shortImageName returns the name of image as docker prints it, without the
+// default registry that other runtimes print.
+func shortImageName(image string) string {
+	image = strings.TrimPrefix(image, "docker.io/")
+	return strings.TrimPrefix(image, "library/")
+}
+
+// LoadImagesWithProgress loads the cached images into the container runtime
+// r, a few at a time, reporting progress to w. If images fail to load, the
+// others are still loaded and an ImageLoadErrors is returned.
+func LoadImagesWithProgress(cmd bootstrapper.CommandRunner, r cruntime.Manager, images []string, cacheDir string, w io.Writer) error {
+	start := time.Now()
+	p := &imageLoadProgress{w: w, total: len(images)}
+	jobs := make(chan string)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		loaded []string
+		failed ImageLoadErrors
+	)
+	for i := 0; i < maxParallelImageLoads && i < len(images); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for image := range jobs {
+				src := CachedImagePath(cacheDir, image)
+				err := loadFromCache(cmd, r, src, func(copied, size int64) {
+					p.copied(image, copied, size)
+				})
+				p.done(image, err)
+				mu.Lock()
+				if err != nil {
+					failed = append(failed, ImageLoadError{Image: image, Err: err})
+				} else {
+					loaded = append(loaded, image)
+				}
+				mu.Unlock()
 			}
-			return nil
-		})
+		}()
 	}
-	if err := g.Wait(); err != nil {
-		return errors.Wrap(err, "loading cached images")
+	for _, image := range images {
+		jobs <- image
+	}
+	close(jobs)
+	wg.Wait()
+
+	RecordImageUse(cacheDir, loaded, "")
+	p.printf("Loaded %d of %d images in %s.\n", len(loaded), len(images), time.Since(start).Round(time.Second))
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool { return failed[i].Image < failed[j].Image })
+		return failed
 	}
 	glog.Infoln("Successfully loaded all cached images.")
 	return nil
 }
 
-func CacheAndLoadImages(images []string) error {
+// imageLoadProgress reports the progress of concurrent image loads
+type imageLoadProgress struct {
+	mu       sync.Mutex
+	w        io.Writer
+	total    int
+	finished int
+	percent  map[string]int64
+}
+
+func (p *imageLoadProgress) printf(format string, a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.w, format, a...)
+}
+
+// copied reports the bytes of image copied to the machine so far, every
+// quarter of its size
+func (p *imageLoadProgress) copied(image string, copied, size int64) {
+	if size <= 0 {
+		return
+	}
+	percent := copied * 100 / size / 25 * 25
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.percent == nil {
+		p.percent = map[string]int64{}
+	}
+	if last, ok := p.percent[image]; ok && percent <= last {
+		return
+	}
+	p.percent[image] = percent
+	fmt.Fprintf(p.w, "  %s: copied %s of %s (%d%%)\n", image,
+		units.HumanSize(float64(copied)), units.HumanSize(float64(size)), percent)
+}
+
+func (p *imageLoadProgress) done(image string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finished++
+	if err != nil {
+		glog.Errorf("Loading image %s: %v", image, err)
+		fmt.Fprintf(p.w, "[%d/%d] Failed to load %s\n", p.finished, p.total, image)
+		return
+	}
+	fmt.Fprintf(p.w, "[%d/%d] Loaded %s\n", p.finished, p.total, image)
+}
+
+// progressFile reports the bytes read from a file being copied
+type progressFile struct {
+	assets.CopyableFile
+	read   int64
+	size   int64
+	report func(copied, size int64)
+}
+
+func (f *progressFile) Read(p []byte) (int, error) {
+	n, err := f.CopyableFile.Read(p)
+	f.read += int64(n)
+	f.report(f.read, f.size)
+	return n, err
+}
+
+// CacheAndLoadImages caches the images on the host, then loads them into
+// the container runtime r of the machine
+func CacheAndLoadImages(r cruntime.Manager, images []string) error {
+	fmt.Printf("Caching %d images...\n", len(images))
 	if err := CacheImages(images, constants.ImageCacheDir); err != nil {
 		return err
 	}
@@ -114,7 +283,7 @@ func CacheAndLoadImages(images []string) error {
 		return err
 	}
 
-	client, err := sshutil.NewSSHClient(h.Driver)
+	client, err := sshutil.GetClient(h.Driver)
 	if err != nil {
 		return err
 	}
@@ -123,7 +292,13 @@ func CacheAndLoadImages(images []string) error {
 		return err
 	}
 
-	return LoadImages(cmdRunner, images, constants.ImageCacheDir)
+	fmt.Printf("Loading %d cached images...\n", len(images))
+	return LoadImagesWithProgress(cmdRunner, r, images, constants.ImageCacheDir, os.Stdout)
+}
+
+// CachedImagePath returns the location of an image in the cache directory.
+func CachedImagePath(cacheDir, image string) string {
+	return sanitizeCacheDir(filepath.Join(cacheDir, image))
 }
 
 // # ParseReference cannot have a : in the directory path
@@ -190,7 +365,13 @@ func getWindowsVolumeNameCmd(d string) (string, error) {
 	return vname, nil
 }
 
-func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, src string) error {
+// LoadFromCacheBlocking waits for the image archive src to be cached, then
+// loads it into the container runtime r
+func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, r cruntime.Manager, src string) error {
+	return loadFromCache(cmd, r, src, nil)
+}
+
+func loadFromCache(cmd bootstrapper.CommandRunner, r cruntime.Manager, src string, report func(copied, size int64)) error {
 	glog.Infoln("Loading image from cache at ", src)
 	filename := filepath.Base(src)
 	for {
@@ -203,18 +384,20 @@ func LoadFromCacheBlocking(cmd bootstrapper.CommandRunner, src string) error {
 	if err != nil {
 		return errors.Wrapf(err, "creating copyable file asset: %s", filename)
 	}
-	if err := cmd.Copy(f); err != nil {
+	var file assets.CopyableFile = f
+	if report != nil {
+		file = &progressFile{CopyableFile: f, size: int64(f.GetLength()), report: report}
+	}
+	if err := cmd.Copy(file); err != nil {
 		return errors.Wrap(err, "transferring cached image")
 	}
 
-	dockerLoadCmd := "docker load -i " + dst
-
-	if err := cmd.Run(dockerLoadCmd); err != nil {
-		return errors.Wrapf(err, "loading docker image: %s", dst)
+	if err := r.LoadImage(cmd, dst); err != nil {
+		return err
 	}
 
 	if err := cmd.Run("sudo rm -rf " + dst); err != nil {
-		return errors.Wrap(err, "deleting temp docker image location")
+		return errors.Wrap(err, "deleting temp image location")
 	}
 
 	glog.Infof("Successfully loaded image %s from cache", src)
@@ -230,11 +413,11 @@ func DeleteFromImageCacheDir(images []string) error {
 			return err
 		}
 	}
-	return cleanImageCacheDir()
+	return cleanImageCacheDir(constants.ImageCacheDir)
 }
 
-func cleanImageCacheDir() error {
-	err := filepath.Walk(constants.ImageCacheDir, func(path string, info os.FileInfo, err error) error {
+func cleanImageCacheDir(dir string) error {
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		// If error is not nil, it's because the path was already deleted and doesn't exist
 		// Move on to next path
 		if err != nil {
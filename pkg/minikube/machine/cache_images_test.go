@@ -17,13 +17,18 @@ limitations under the License.
 package machine
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 )
 
 func TestGetSrcRef(t *testing.T) {
@@ -99,3 +104,63 @@ func TestHasWindowsDriveLetter(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadImagesWithProgress(t *testing.T) {
+	dir, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	images := []string{"a.io/one:v1", "a.io/two:v1", "a.io/broken:v1"}
+	for _, image := range images {
+		writeCachedImage(t, dir, image, 1000, time.Now())
+	}
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToOutput(map[string]string{
+		"docker load -i /tmp/one_v1": "",
+		"docker load -i /tmp/two_v1": "",
+		"sudo rm -rf /tmp/one_v1":    "",
+		"sudo rm -rf /tmp/two_v1":    "",
+	})
+
+	var out bytes.Buffer
+	err = LoadImagesWithProgress(f, &cruntime.Docker{}, images, dir, &out)
+	failed, ok := err.(ImageLoadErrors)
+	if !ok || len(failed) != 1 || failed[0].Image != "a.io/broken:v1" {
+		t.Fatalf("Expected a.io/broken:v1 to fail, got %v", err)
+	}
+	for _, line := range []string{
+		"a.io/one:v1: copied 1kB of 1kB (100%)",
+		"Loaded a.io/two:v1",
+		"Failed to load a.io/broken:v1",
+		"Loaded 2 of 3 images",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+	if _, err := f.GetFileToContents(CachedImagePath(dir, "a.io/one:v1")); err != nil {
+		t.Errorf("Expected one_v1 to be copied: %s", err)
+	}
+}
+
+func TestMissingImages(t *testing.T) {
+	images := []string{"k8s.gcr.io/pause-amd64:3.0", "busybox:latest", "a.io/one:v1"}
+	f := bootstrapper.NewFakeCommandRunner()
+	missing, present := missingImages(f, &cruntime.Containerd{}, images)
+	if !reflect.DeepEqual(missing, images) || len(present) != 0 {
+		t.Errorf("Expected all images to be missing when they cannot be listed, got %v", missing)
+	}
+
+	f.SetCommandToOutput(map[string]string{
+		"sudo ctr -n=k8s.io images list --quiet": "k8s.gcr.io/pause-amd64:3.0\ndocker.io/library/busybox:latest\n",
+	})
+	missing, present = missingImages(f, &cruntime.Containerd{}, images)
+	if expected := []string{"a.io/one:v1"}; !reflect.DeepEqual(missing, expected) {
+		t.Errorf("Expected missing images %v, got %v", expected, missing)
+	}
+	if expected := []string{"k8s.gcr.io/pause-amd64:3.0", "busybox:latest"}; !reflect.DeepEqual(present, expected) {
+		t.Errorf("Expected present images %v, got %v", expected, present)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// imageCacheIndexFile records what is known about the images in the cache
+// directory, next to the image tarballs
+const imageCacheIndexFile = "index.json"
+
+// CachedImage describes an image tarball in the image cache
+type CachedImage struct {
+	// Image is the name of the image. For tarballs cached before the index
+	// existed, it is derived from Path until the image is used again.
+	Image string `json:"image,omitempty"`
+	// Path is the location of the tarball relative to the cache directory
+	Path string `json:"path"`
+	// Size is the size of the tarball in bytes
+	Size int64 `json:"size"`
+	// LastUsed is the last time the image was cached or loaded
+	LastUsed time.Time `json:"lastUsed"`
+	// KubernetesVersions are the Kubernetes versions the image was cached for
+	KubernetesVersions []string `json:"kubernetesVersions,omitempty"`
+}
+
+// Name returns the image name, or its path when the name is not known
+func (i CachedImage) Name() string {
+	if i.Image != "" {
+		return i.Image
+	}
+	return i.Path
+}
+
+// ImageCache tracks the images cached in a directory
+type ImageCache struct {
+	dir    string
+	images map[string]*CachedImage
+}
+
+// PruneOptions selects the images removed by Prune
+type PruneOptions struct {
+	// OlderThan removes the images not used for this long, if non-zero
+	OlderThan time.Duration
+	// MaxSize removes the least recently used images until the cache is no
+	// larger than this many bytes, if non-zero
+	MaxSize int64
+	// Keep are images that are never removed
+	Keep []string
+	// KeepVersions are Kubernetes versions whose images are never removed
+	KeepVersions []string
+}
+
+// imageCacheMu serializes updates to the index, which are done concurrently
+// when images are cached and loaded at the same time
+var imageCacheMu sync.Mutex
+
+// now is replaced in tests
+var now = time.Now
+
+// LoadImageCache reads the index of the image cache in dir, and reconciles it
+// with the tarballs actually present: missing ones are forgotten, and ones
+// not in the index yet are added with their modification time as last use and
+// the image name their path was made from.
+func LoadImageCache(dir string) (*ImageCache, error) {
+	c := &ImageCache{dir: dir, images: map[string]*CachedImage{}}
+	data, err := ioutil.ReadFile(filepath.Join(dir, imageCacheIndexFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "reading image cache index")
+	}
+	if err == nil {
+		var images []*CachedImage
+		if err := json.Unmarshal(data, &images); err != nil {
+			glog.Warningf("Ignoring corrupt image cache index: %s", err)
+		}
+		for _, i := range images {
+			c.images[i.Path] = i
+		}
+	}
+
+	found := map[string]bool{}
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == imageCacheIndexFile || rel == imageCacheIndexFile+".tmp" {
+			return nil
+		}
+		found[rel] = true
+		i, ok := c.images[rel]
+		if !ok {
+			i = &CachedImage{Path: rel, LastUsed: info.ModTime()}
+			c.images[rel] = i
+		}
+		if i.Image == "" {
+			i.Image = imageFromCachePath(rel)
+		}
+		i.Size = info.Size()
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "walking image cache")
+	}
+	for path := range c.images {
+		if !found[path] {
+			delete(c.images, path)
+		}
+	}
+	return c, nil
+}
+
+// Save writes the index of the image cache
+func (c *ImageCache) Save() error {
+	data, err := json.MarshalIndent(c.Images(), "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "encoding image cache index")
+	}
+	if err := os.MkdirAll(c.dir, 0777); err != nil {
+		return errors.Wrap(err, "making image cache directory")
+	}
+	path := filepath.Join(c.dir, imageCacheIndexFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return errors.Wrap(err, "writing image cache index")
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Images returns the cached images, least recently used first
+func (c *ImageCache) Images() []CachedImage {
+	images := []CachedImage{}
+	for _, i := range c.images {
+		images = append(images, *i)
+	}
+	sort.Slice(images, func(a, b int) bool {
+		if images[a].LastUsed.Equal(images[b].LastUsed) {
+			return images[a].Path < images[b].Path
+		}
+		return images[a].LastUsed.Before(images[b].LastUsed)
+	})
+	return images
+}
+
+// Size returns the total size of the cached images in bytes
+func (c *ImageCache) Size() int64 {
+	var size int64
+	for _, i := range c.images {
+		size += i.Size
+	}
+	return size
+}
+
+// Touch records that image was just used, for kubernetesVersion if not empty.
+// Images that are not in the cache are ignored.
+func (c *ImageCache) Touch(image, kubernetesVersion string) {
+	rel, err := filepath.Rel(c.dir, CachedImagePath(c.dir, image))
+	if err != nil {
+		return
+	}
+	i, ok := c.images[rel]
+	if !ok {
+		return
+	}
+	i.Image = image
+	i.LastUsed = now()
+	if kubernetesVersion == "" {
+		return
+	}
+	for _, v := range i.KubernetesVersions {
+		if v == kubernetesVersion {
+			return
+		}
+	}
+	i.KubernetesVersions = append(i.KubernetesVersions, kubernetesVersion)
+	sort.Strings(i.KubernetesVersions)
+}
+
+// Prune removes the images selected by opts from the cache, and returns them
+func (c *ImageCache) Prune(opts PruneOptions) ([]CachedImage, error) {
+	size := c.Size()
+	var pruned []CachedImage
+	for _, i := range c.Images() {
+		if opts.keeps(i) {
+			continue
+		}
+		expired := opts.OlderThan > 0 && now().Sub(i.LastUsed) > opts.OlderThan
+		oversized := opts.MaxSize > 0 && size > opts.MaxSize
+		if !expired && !oversized {
+			continue
+		}
+		glog.Infof("Pruning image %s from cache", i.Name())
+		if err := os.Remove(filepath.Join(c.dir, i.Path)); err != nil && !os.IsNotExist(err) {
+			return pruned, errors.Wrapf(err, "removing %s", i.Path)
+		}
+		delete(c.images, i.Path)
+		size -= i.Size
+		pruned = append(pruned, i)
+	}
+	return pruned, cleanImageCacheDir(c.dir)
+}
+
+func (opts PruneOptions) keeps(i CachedImage) bool {
+	for _, image := range opts.Keep {
+		// names derived from the path have lost the colons of registry
+		// ports, so compare them the way they are written in the cache
+		if sanitizeCacheDir(image) == sanitizeCacheDir(i.Image) {
+			return true
+		}
+	}
+	for _, kv := range opts.KeepVersions {
+		for _, v := range i.KubernetesVersions {
+			if v == kv {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// imageFromCachePath returns the name of the image cached at path, relative to
+// the cache directory, reversing CachedImagePath. That replaced the colons of
+// the name with underscores, so the last underscore of the base name is taken
+// to be the tag separator.
+func imageFromCachePath(path string) string {
+	path = filepath.ToSlash(path)
+	dir, base := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, base = path[:i+1], path[i+1:]
+	}
+	if i := strings.LastIndex(base, "_"); i >= 0 {
+		base = base[:i] + ":" + base[i+1:]
+	}
+	return dir + base
+}
+
+// RecordImageUse marks the cached images as just used, for kubernetesVersion
+// if not empty. Failing to update the index does not affect the cache, so
+// errors are only logged.
+func RecordImageUse(dir string, images []string, kubernetesVersion string) {
+	imageCacheMu.Lock()
+	defer imageCacheMu.Unlock()
+	c, err := LoadImageCache(dir)
+	if err != nil {
+		glog.Warningf("Error loading image cache index: %s", err)
+		return
+	}
+	for _, image := range images {
+		c.Touch(image, kubernetesVersion)
+	}
+	if err := c.Save(); err != nil {
+		glog.Warningf("Error saving image cache index: %s", err)
+	}
+}
+
+// PruneImageCache removes the images selected by opts from the cache in dir,
+// and returns them
+func PruneImageCache(dir string, opts PruneOptions) ([]CachedImage, error) {
+	imageCacheMu.Lock()
+	defer imageCacheMu.Unlock()
+	c, err := LoadImageCache(dir)
+	if err != nil {
+		return nil, err
+	}
+	pruned, err := c.Prune(opts)
+	if saveErr := c.Save(); err == nil {
+		err = saveErr
+	}
+	return pruned, err
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeCachedImage(t *testing.T, dir, image string, size int, modTime time.Time) {
+	path := CachedImagePath(dir, image)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatalf("Error making image dir: %s", err)
+	}
+	if err := ioutil.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatalf("Error writing image: %s", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Error setting image times: %s", err)
+	}
+}
+
+func prunedNames(images []CachedImage) []string {
+	names := []string{}
+	for _, i := range images {
+		names = append(names, i.Name())
+	}
+	return names
+}
+
+func TestPruneImageCache(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	defer func() { now = time.Now }()
+
+	var tests = []struct {
+		description string
+		opts        PruneOptions
+		expected    []string
+	}{
+		{
+			description: "older than",
+			opts:        PruneOptions{OlderThan: 36 * time.Hour},
+			expected:    []string{"a.io/old:v1", "a.io/kube:v1"},
+		},
+		{
+			description: "max size",
+			opts:        PruneOptions{MaxSize: 350},
+			expected:    []string{"a.io/old:v1"},
+		},
+		{
+			description: "max size removes least recently used first",
+			opts:        PruneOptions{MaxSize: 250},
+			expected:    []string{"a.io/old:v1", "a.io/kube:v1"},
+		},
+		{
+			description: "max size keeps images",
+			opts:        PruneOptions{MaxSize: 350, Keep: []string{"a.io/old:v1"}},
+			expected:    []string{"a.io/kube:v1"},
+		},
+		{
+			description: "keep kubernetes version",
+			opts:        PruneOptions{OlderThan: time.Hour, KeepVersions: []string{"v1.10.0"}},
+			expected:    []string{"a.io/old:v1", "a.io/user:v1"},
+		},
+		{
+			description: "keep images cached before the index existed",
+			opts:        PruneOptions{OlderThan: time.Hour, Keep: []string{"a.io/user:v1", "b.io:5000/unused:v2"}},
+			expected:    []string{"a.io/old:v1", "a.io/kube:v1"},
+		},
+		{
+			description: "nothing to prune",
+			opts:        PruneOptions{MaxSize: 1000},
+			expected:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "images")
+			if err != nil {
+				t.Fatalf("Error making temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			// Cached before the index existed, so tracked by modification time
+			writeCachedImage(t, dir, "a.io/old:v1", 100, start)
+			writeCachedImage(t, dir, "a.io/kube:v1", 100, start)
+			writeCachedImage(t, dir, "a.io/user:v1", 100, start)
+			writeCachedImage(t, dir, "b.io:5000/unused:v2", 100, start.Add(72*time.Hour))
+			now = func() time.Time { return start.Add(12 * time.Hour) }
+			RecordImageUse(dir, []string{"a.io/old:v1"}, "v1.9.4")
+			now = func() time.Time { return start.Add(24 * time.Hour) }
+			RecordImageUse(dir, []string{"a.io/kube:v1"}, "v1.10.0")
+			now = func() time.Time { return start.Add(48 * time.Hour) }
+			RecordImageUse(dir, []string{"a.io/user:v1"}, "")
+			now = func() time.Time { return start.Add(72 * time.Hour) }
+
+			pruned, err := PruneImageCache(dir, test.opts)
+			if err != nil {
+				t.Fatalf("Error pruning: %s", err)
+			}
+			if names := prunedNames(pruned); !reflect.DeepEqual(names, test.expected) {
+				t.Fatalf("Expected %v to be pruned, got %v", test.expected, names)
+			}
+			for _, image := range test.expected {
+				if _, err := os.Stat(CachedImagePath(dir, image)); !os.IsNotExist(err) {
+					t.Errorf("Expected %s to be removed", image)
+				}
+			}
+			c, err := LoadImageCache(dir)
+			if err != nil {
+				t.Fatalf("Error loading cache: %s", err)
+			}
+			if len(c.Images()) != 4-len(test.expected) {
+				t.Errorf("Expected %d images left, got %v", 4-len(test.expected), c.Images())
+			}
+		})
+	}
+}
+
+func TestLoadImageCacheForgetsRemovedImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "images")
+	if err != nil {
+		t.Fatalf("Error making temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeCachedImage(t, dir, "a.io/kube:v1", 10, time.Now())
+	RecordImageUse(dir, []string{"a.io/kube:v1"}, "v1.10.0")
+	RecordImageUse(dir, []string{"a.io/kube:v1"}, "v1.9.4")
+
+	c, err := LoadImageCache(dir)
+	if err != nil {
+		t.Fatalf("Error loading cache: %s", err)
+	}
+	images := c.Images()
+	if len(images) != 1 || images[0].Image != "a.io/kube:v1" || images[0].Size != 10 {
+		t.Fatalf("Unexpected images: %v", images)
+	}
+	if expected := []string{"v1.10.0", "v1.9.4"}; !reflect.DeepEqual(images[0].KubernetesVersions, expected) {
+		t.Errorf("Expected versions %v, got %v", expected, images[0].KubernetesVersions)
+	}
+
+	if err := os.Remove(CachedImagePath(dir, "a.io/kube:v1")); err != nil {
+		t.Fatalf("Error removing image: %s", err)
+	}
+	c, err = LoadImageCache(dir)
+	if err != nil {
+		t.Fatalf("Error loading cache: %s", err)
+	}
+	if len(c.Images()) != 0 {
+		t.Errorf("Expected removed image to be forgotten, got %v", c.Images())
+	}
+}
+
+func TestImageFromCachePath(t *testing.T) {
+	for _, image := range []string{
+		"gcr.io/google_containers/k8s-dns-kube-dns-amd64:1.14.5",
+		"gcr.io/k8s-minikube/storage-provisioner:v1.8.1",
+		"a.io/some_image:v1",
+		"a.io/kube@sha256:abcdef",
+	} {
+		dir := filepath.Join("cache", "images")
+		rel, err := filepath.Rel(dir, CachedImagePath(dir, image))
+		if err != nil {
+			t.Fatalf("Error getting relative path: %s", err)
+		}
+		if got := imageFromCachePath(rel); got != image {
+			t.Errorf("imageFromCachePath(%q) = %q, expected %q", rel, got, image)
+		}
+	}
+}
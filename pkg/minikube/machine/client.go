@@ -156,7 +156,7 @@ func (api *LocalClient) Load(name string) (*host.Host, error) {
 
 func GetCommandRunner(h *host.Host) (bootstrapper.CommandRunner, error) {
 	if h.DriverName != constants.DriverNone {
-		client, err := sshutil.NewSSHClient(h.Driver)
+		client, err := sshutil.GetClient(h.Driver)
 		if err != nil {
 			return nil, errors.Wrap(err, "getting ssh client for bootstrapper")
 		}
@@ -0,0 +1,179 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PortForward forwards a local port to a port of a service
+type PortForward struct {
+	Namespace string
+	Service   string
+	PortName  string
+	LocalPort int
+	// RemoteAddr is the ClusterIP:port of the service port
+	RemoteAddr string
+}
+
+func (p PortForward) String() string {
+	name := p.Namespace + "/" + p.Service
+	if p.PortName != "" {
+		name += ":" + p.PortName
+	}
+	return fmt.Sprintf("127.0.0.1:%d -> %s (%s)", p.LocalPort, name, p.RemoteAddr)
+}
+
+// GetPortForwards returns the forwards of the TCP ports of a service. The
+// ports are forwarded from localPorts in order, and from the free ports
+// returned by freePort once localPorts runs out.
+func GetPortForwards(c corev1.CoreV1Interface, namespace, service string, localPorts []int, freePort func() (string, error)) ([]PortForward, error) {
+	svc, err := c.Services(namespace).Get(service, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "service '%s' could not be found running", service)
+	}
+	if svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == v1.ClusterIPNone {
+		return nil, errors.Errorf("service '%s' has no cluster IP to forward to", service)
+	}
+	var forwards []PortForward
+	for _, port := range svc.Spec.Ports {
+		if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+			glog.Warningf("Not forwarding %s port %d of %s, only TCP can be forwarded", port.Protocol, port.Port, service)
+			continue
+		}
+		local := 0
+		if len(forwards) < len(localPorts) {
+			local = localPorts[len(forwards)]
+		} else {
+			p, err := freePort()
+			if err != nil {
+				return nil, errors.Wrap(err, "getting a free local port")
+			}
+			if local, err = strconv.Atoi(p); err != nil {
+				return nil, errors.Wrapf(err, "parsing local port %s", p)
+			}
+		}
+		forwards = append(forwards, PortForward{
+			Namespace:  namespace,
+			Service:    service,
+			PortName:   port.Name,
+			LocalPort:  local,
+			RemoteAddr: net.JoinHostPort(svc.Spec.ClusterIP, strconv.Itoa(int(port.Port))),
+		})
+	}
+	if len(forwards) == 0 {
+		return nil, errors.Errorf("service '%s' has no TCP ports to forward", service)
+	}
+	if len(localPorts) > len(forwards) {
+		return nil, errors.Errorf("%d local ports given for the %d ports of service '%s'", len(localPorts), len(forwards), service)
+	}
+	return forwards, nil
+}
+
+// Dialer opens connections from inside the cluster, such as the SSH client
+// of the VM, which redials when its connection broke
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// Forwarder listens on the local ports of forwards
+type Forwarder struct {
+	forwards  []PortForward
+	listeners []net.Listener
+}
+
+// ListenPorts listens on the local ports of forwards. Either all of them are
+// bound, or none is and the error tells which one could not be.
+func ListenPorts(forwards []PortForward) (*Forwarder, error) {
+	f := &Forwarder{forwards: forwards}
+	for _, pf := range forwards {
+		l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(pf.LocalPort)))
+		if err != nil {
+			f.Close()
+			return nil, errors.Wrapf(err, "listening on local port %d", pf.LocalPort)
+		}
+		f.listeners = append(f.listeners, l)
+	}
+	return f, nil
+}
+
+// Forward forwards every connection to the local ports through d until done
+// is closed, then stops listening.
+func (f *Forwarder) Forward(d Dialer, done <-chan struct{}, out io.Writer) {
+	var wg sync.WaitGroup
+	for i, pf := range f.forwards {
+		wg.Add(1)
+		go func(l net.Listener, pf PortForward) {
+			defer wg.Done()
+			acceptAndForward(l, d, pf, out)
+		}(f.listeners[i], pf)
+	}
+	<-done
+	f.Close()
+	wg.Wait()
+}
+
+// Close stops listening on the local ports
+func (f *Forwarder) Close() {
+	for _, l := range f.listeners {
+		l.Close()
+	}
+}
+
+func acceptAndForward(l net.Listener, d Dialer, f PortForward, out io.Writer) {
+	for {
+		local, err := l.Accept()
+		if err != nil {
+			// the listener was closed
+			return
+		}
+		go func() {
+			defer local.Close()
+			remote, err := d.Dial("tcp", f.RemoteAddr)
+			if err != nil {
+				fmt.Fprintf(out, "Error forwarding to %s: %s\n", f.RemoteAddr, err)
+				return
+			}
+			defer remote.Close()
+			copyBoth(local, remote)
+		}()
+	}
+}
+
+// copyBoth copies between a and b until either side is done
+func copyBoth(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
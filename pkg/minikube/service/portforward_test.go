@@ -0,0 +1,172 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func TestGetPortForwards(t *testing.T) {
+	client := &MockCoreClient{servicesMap: map[string]corev1.ServiceInterface{
+		"default": &MockServiceInterface{ServiceList: &v1.ServiceList{Items: []v1.Service{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: v1.ServiceSpec{
+					ClusterIP: "10.96.0.10",
+					Ports: []v1.ServicePort{
+						{Name: "http", Port: 80},
+						{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP},
+						{Name: "https", Port: 443, Protocol: v1.ProtocolTCP},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "headless", Namespace: "default"},
+				Spec: v1.ServiceSpec{
+					ClusterIP: v1.ClusterIPNone,
+					Ports:     []v1.ServicePort{{Port: 80}},
+				},
+			},
+		}}},
+	}}
+	freePort := func() (string, error) { return "40000", nil }
+
+	forwards, err := GetPortForwards(client, "default", "web", []int{8080}, freePort)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []PortForward{
+		{Namespace: "default", Service: "web", PortName: "http", LocalPort: 8080, RemoteAddr: "10.96.0.10:80"},
+		{Namespace: "default", Service: "web", PortName: "https", LocalPort: 40000, RemoteAddr: "10.96.0.10:443"},
+	}
+	if !reflect.DeepEqual(forwards, expected) {
+		t.Errorf("Forwards = %v, expected %v", forwards, expected)
+	}
+
+	if _, err := GetPortForwards(client, "default", "web", []int{8080, 8443, 8053}, freePort); err == nil {
+		t.Errorf("Expected an error with more local ports than service ports")
+	}
+	if _, err := GetPortForwards(client, "default", "headless", nil, freePort); err == nil {
+		t.Errorf("Expected an error for a service without cluster IP")
+	}
+}
+
+// fakeDialer dials addr, ignoring the address it is asked for
+type fakeDialer struct {
+	addr string
+}
+
+func (d *fakeDialer) Dial(network, _ string) (net.Conn, error) {
+	return net.Dial(network, d.addr)
+}
+
+func TestForwardPorts(t *testing.T) {
+	// an echo server stands in for the service in the cluster
+	server, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer server.Close()
+	go func() {
+		for {
+			conn, err := server.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	localPort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	forwards := []PortForward{{Namespace: "default", Service: "web", LocalPort: localPort, RemoteAddr: "10.96.0.10:80"}}
+	forwarder, err := ListenPorts(forwards)
+	if err != nil {
+		t.Fatalf("Error listening on the local ports: %s", err)
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		forwarder.Forward(&fakeDialer{addr: server.Addr().String()}, done, ioutil.Discard)
+		close(stopped)
+	}()
+
+	for i := 0; i < 2; i++ {
+		// the port is bound before Forward is called, so there is no need to retry
+		conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
+		if err != nil {
+			t.Fatalf("Error connecting to the forwarded port: %s", err)
+		}
+		fmt.Fprintf(conn, "ping %d\n", i)
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			t.Fatalf("Error reading from the forwarded port: %s", err)
+		}
+		if line != fmt.Sprintf("ping %d\n", i) {
+			t.Errorf("Read %q through the forwarded port", line)
+		}
+		conn.Close()
+	}
+
+	close(done)
+	<-stopped
+	if _, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))); err == nil {
+		t.Errorf("Expected the local port to be closed once done")
+	}
+}
+
+func TestListenPortsInUse(t *testing.T) {
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer taken.Close()
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	freePort := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	forwards := []PortForward{
+		{Namespace: "default", Service: "web", LocalPort: freePort, RemoteAddr: "10.96.0.10:80"},
+		{Namespace: "default", Service: "web", LocalPort: taken.Addr().(*net.TCPAddr).Port, RemoteAddr: "10.96.0.10:443"},
+	}
+	if _, err := ListenPorts(forwards); err == nil {
+		t.Fatalf("Expected an error for a local port in use")
+	}
+	// the port bound before the failure is released
+	l, err = net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(freePort)))
+	if err != nil {
+		t.Errorf("Expected local port %d to be released: %s", freePort, err)
+	} else {
+		l.Close()
+	}
+}
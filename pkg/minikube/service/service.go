@@ -40,6 +40,7 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/util"
 )
 
@@ -260,6 +261,68 @@ func WaitAndMaybeOpenService(api libmachine.API, namespace string, service strin
 	return nil
 }
 
+// WaitAndForwardService waits for a service to be ready, then forwards its
+// ports from the local ports through SSH to the VM until done is closed. The
+// URLs of the forwarded ports are printed or opened like the node port URLs
+// of WaitAndMaybeOpenService, once every local port is bound.
+func WaitAndForwardService(api libmachine.API, namespace string, service string, localPorts []int, freePort func() (string, error),
+	urlTemplate *template.Template, urlMode bool, https bool, wait int, interval int, done <-chan struct{}) error {
+	if err := util.RetryAfter(wait, func() error { return CheckService(namespace, service) }, time.Duration(interval)*time.Second); err != nil {
+		return errors.Wrapf(err, "Could not find finalized endpoint being pointed to by %s", service)
+	}
+
+	host, err := cluster.CheckIfApiExistsAndLoad(api)
+	if err != nil {
+		return errors.Wrap(err, "Error checking if api exist and loading it")
+	}
+	client, err := K8s.GetCoreClient()
+	if err != nil {
+		return err
+	}
+	forwards, err := GetPortForwards(client, namespace, service, localPorts, freePort)
+	if err != nil {
+		return err
+	}
+	sshClient, err := sshutil.GetClient(host.Driver)
+	if err != nil {
+		return errors.Wrap(err, "Error getting ssh client")
+	}
+	forwarder, err := ListenPorts(forwards)
+	if err != nil {
+		return err
+	}
+
+	var urls []string
+	for _, f := range forwards {
+		var doc bytes.Buffer
+		if err := urlTemplate.Execute(&doc, struct {
+			IP   string
+			Port int
+		}{"127.0.0.1", f.LocalPort}); err != nil {
+			forwarder.Close()
+			return err
+		}
+		url := doc.String()
+		if https {
+			url = strings.Replace(url, "http", "https", 1)
+		}
+		urls = append(urls, url)
+	}
+	// every port is bound, so the URLs can be used right away
+	for i, url := range urls {
+		fmt.Fprintf(os.Stderr, "Forwarding %s\n", forwards[i])
+		if urlMode || !strings.HasPrefix(url, "http") {
+			fmt.Fprintln(os.Stdout, url)
+		} else {
+			fmt.Fprintln(os.Stderr, "Opening kubernetes service "+namespace+"/"+service+" in default browser...")
+			browser.OpenURL(url)
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Press Ctrl-C to stop forwarding.")
+	forwarder.Forward(sshClient, done, os.Stderr)
+	return nil
+}
+
 func GetServiceListByLabel(namespace string, key string, value string) (*v1.ServiceList, error) {
 	client, err := K8s.GetCoreClient()
 	if err != nil {
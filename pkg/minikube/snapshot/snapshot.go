@@ -0,0 +1,374 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	metadataFile = "snapshot.json"
+	configFile   = "config.json"
+	etcdArchive  = "etcd.tar.gz"
+	certsArchive = "certs.tar.gz"
+	hostCertsDir = "certs"
+
+	// vmStagingDir is where archives are uploaded to before being extracted
+	vmStagingDir = "/tmp"
+
+	// restoreSuffix is appended to the host files of a snapshot while it is
+	// restored
+	restoreSuffix = ".restore"
+)
+
+// hostCerts are the files in the minikube directory that identify the cluster.
+var hostCerts = []string{
+	"ca.crt", "ca.key",
+	"apiserver.crt", "apiserver.key",
+	"proxy-client-ca.crt", "proxy-client-ca.key",
+	"proxy-client.crt", "proxy-client.key",
+	"client.crt", "client.key",
+}
+
+// layout describes where a bootstrapper keeps its state in the VM and how to
+// quiesce it while that state is read or replaced.
+type layout struct {
+	etcdDataDir string
+	// service is the systemd unit running the cluster components
+	service string
+	// saveContainers are stopped with service while etcd is archived, as
+	// they keep running without it
+	saveContainers []string
+	// restoreContainers hold cluster state and are stopped before a restore
+	restoreContainers []string
+	// restoreCleanCmd removes the configuration the bootstrapper generates
+	// again when the cluster is started
+	restoreCleanCmd string
+}
+
+var layouts = map[string]layout{
+	bootstrapper.BootstrapperTypeKubeadm: {
+		etcdDataDir:       constants.KubeadmEtcdDataDir,
+		service:           "kubelet",
+		saveContainers:    []string{"etcd"},
+		restoreContainers: []string{"etcd", "kube-apiserver", "kube-controller-manager", "kube-scheduler"},
+		restoreCleanCmd:   "sudo rm -f /etc/kubernetes/*.conf /etc/kubernetes/manifests/etcd.yaml /etc/kubernetes/manifests/kube-*.yaml",
+	},
+	bootstrapper.BootstrapperTypeLocalkube: {
+		etcdDataDir: path.Join(util.DefaultLocalkubeDirectory, "etcd"),
+		service:     "localkube",
+	},
+}
+
+// stop stops the service of l, then the containers of the container runtime
+// r with the given Kubernetes container names.
+func (l layout) stop(cmd bootstrapper.CommandRunner, r cruntime.Manager, containers []string) error {
+	if err := cmd.Run("sudo systemctl stop " + l.service); err != nil {
+		return errors.Wrapf(err, "stopping %s", l.service)
+	}
+	for _, name := range containers {
+		id, err := r.FindContainer(cmd, name)
+		if err != nil {
+			return errors.Wrapf(err, "finding %s container", name)
+		}
+		if id == "" {
+			continue
+		}
+		if err := r.StopContainer(cmd, id); err != nil {
+			return errors.Wrapf(err, "stopping %s container", name)
+		}
+	}
+	return nil
+}
+
+func (l layout) start(cmd bootstrapper.CommandRunner) error {
+	return cmd.Run("sudo systemctl start " + l.service)
+}
+
+// Snapshot describes a saved copy of a cluster's state.
+type Snapshot struct {
+	Name              string
+	Profile           string
+	Created           time.Time
+	Bootstrapper      string
+	KubernetesVersion string
+}
+
+// Dir returns the directory holding the named snapshot of a profile.
+func Dir(profile, name string) string {
+	return constants.MakeMiniPath("snapshots", profile, name)
+}
+
+func getLayout(bootstrapperName string) (layout, error) {
+	l, ok := layouts[bootstrapperName]
+	if !ok {
+		return l, fmt.Errorf("snapshots are not supported by the %s bootstrapper", bootstrapperName)
+	}
+	return l, nil
+}
+
+// Save stores the etcd data directory and certificates of the running cluster,
+// together with the profile configuration, as the named snapshot. The cluster
+// components, run by the container runtime r, are stopped while etcd is
+// archived and started again afterwards.
+func Save(cmd bootstrapper.CommandRunner, r cruntime.Manager, s Snapshot) error {
+	l, err := getLayout(s.Bootstrapper)
+	if err != nil {
+		return err
+	}
+	dir := Dir(s.Profile, s.Name)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("snapshot %s already exists", s.Name)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, hostCertsDir), 0700); err != nil {
+		return errors.Wrap(err, "creating snapshot directory")
+	}
+
+	if err := save(cmd, r, l, dir, s); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	return nil
+}
+
+func save(cmd bootstrapper.CommandRunner, r cruntime.Manager, l layout, dir string, s Snapshot) (err error) {
+	defer func() {
+		// started even if stopping failed half way
+		if startErr := l.start(cmd); startErr != nil && err == nil {
+			err = errors.Wrap(startErr, "starting cluster components")
+		}
+	}()
+	if err := l.stop(cmd, r, l.saveContainers); err != nil {
+		return errors.Wrap(err, "stopping cluster components")
+	}
+
+	if err := archiveDir(cmd, l.etcdDataDir, filepath.Join(dir, etcdArchive)); err != nil {
+		return errors.Wrap(err, "archiving etcd data")
+	}
+	if err := archiveDir(cmd, util.DefaultCertPath, filepath.Join(dir, certsArchive)); err != nil {
+		return errors.Wrap(err, "archiving certificates")
+	}
+	for _, c := range hostCerts {
+		if err := copyFile(constants.MakeMiniPath(c), filepath.Join(dir, hostCertsDir, c)); err != nil {
+			return errors.Wrapf(err, "copying %s", c)
+		}
+	}
+	if err := copyFile(constants.GetProfileFile(s.Profile), filepath.Join(dir, configFile)); err != nil {
+		return errors.Wrap(err, "copying profile config")
+	}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "encoding snapshot metadata")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, metadataFile), data, 0600)
+}
+
+// Restore replaces the etcd data directory, certificates and profile
+// configuration of the running cluster with those of the named snapshot.
+// The host files are only replaced once the VM is restored. Components are
+// left stopped or without their configuration, so the caller should set up
+// certificates for the current VM and restart the cluster. r is the container
+// runtime running the cluster components.
+func Restore(cmd bootstrapper.CommandRunner, r cruntime.Manager, profile, name, bootstrapperName string) (*Snapshot, error) {
+	s, err := Load(profile, name)
+	if err != nil {
+		return nil, err
+	}
+	if s.Bootstrapper != bootstrapperName {
+		return nil, fmt.Errorf("snapshot %s was taken with the %s bootstrapper, but the cluster uses %s", name, s.Bootstrapper, bootstrapperName)
+	}
+	l, err := getLayout(bootstrapperName)
+	if err != nil {
+		return nil, err
+	}
+	dir := Dir(profile, name)
+
+	// The host files are staged next to the ones they replace, and only
+	// moved in place once the VM is restored, so that a failure leaves them
+	// untouched
+	hostFiles := map[string]string{
+		filepath.Join(dir, configFile): constants.GetProfileFile(profile),
+	}
+	for _, c := range hostCerts {
+		hostFiles[filepath.Join(dir, hostCertsDir, c)] = constants.MakeMiniPath(c)
+	}
+	staged := map[string]string{}
+	defer func() {
+		for tmp := range staged {
+			os.Remove(tmp)
+		}
+	}()
+	for src, dst := range hostFiles {
+		tmp := dst + restoreSuffix
+		if err := copyFile(src, tmp); err != nil {
+			return nil, errors.Wrapf(err, "staging %s", filepath.Base(dst))
+		}
+		staged[tmp] = dst
+	}
+
+	var archives []assets.CopyableFile
+	for _, a := range []string{etcdArchive, certsArchive} {
+		f, err := assets.NewFileAsset(filepath.Join(dir, a), vmStagingDir, "snapshot-"+a, "0600")
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening %s", a)
+		}
+		if err := cmd.Copy(f); err != nil {
+			return nil, errors.Wrapf(err, "uploading %s", a)
+		}
+		archives = append(archives, f)
+	}
+	defer func() {
+		for _, f := range archives {
+			if err := cmd.Remove(f); err != nil {
+				glog.Warningf("Error removing %s: %s", f.GetAssetName(), err)
+			}
+		}
+	}()
+
+	if err := l.stop(cmd, r, l.restoreContainers); err != nil {
+		return nil, errors.Wrap(err, "stopping cluster components")
+	}
+	if l.restoreCleanCmd != "" {
+		if err := cmd.Run(l.restoreCleanCmd); err != nil {
+			return nil, errors.Wrap(err, "removing cluster configuration")
+		}
+	}
+	if err := extractDir(cmd, path.Join(vmStagingDir, "snapshot-"+etcdArchive), l.etcdDataDir); err != nil {
+		return nil, errors.Wrap(err, "restoring etcd data")
+	}
+	if err := extractDir(cmd, path.Join(vmStagingDir, "snapshot-"+certsArchive), util.DefaultCertPath); err != nil {
+		return nil, errors.Wrap(err, "restoring certificates")
+	}
+	for tmp, dst := range staged {
+		if err := os.Rename(tmp, dst); err != nil {
+			return nil, errors.Wrapf(err, "restoring %s", filepath.Base(dst))
+		}
+		delete(staged, tmp)
+	}
+	if err := l.start(cmd); err != nil {
+		return nil, errors.Wrap(err, "starting cluster components")
+	}
+	return s, nil
+}
+
+// Load returns the metadata of the named snapshot.
+func Load(profile, name string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(filepath.Join(Dir(profile, name), metadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %s does not exist", name)
+		}
+		return nil, errors.Wrapf(err, "reading snapshot %s", name)
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrapf(err, "decoding snapshot %s", name)
+	}
+	return &s, nil
+}
+
+// List returns the snapshots of a profile, oldest first.
+func List(profile string) ([]Snapshot, error) {
+	files, err := ioutil.ReadDir(constants.MakeMiniPath("snapshots", profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading snapshot directory")
+	}
+	var snapshots []Snapshot
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		s, err := Load(profile, f.Name())
+		if err != nil {
+			glog.Warningf("Skipping snapshot %s: %s", f.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, *s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// archiveDir streams a gzipped tarball of a directory in the VM to dst. dst
+// is removed if the tarball is incomplete.
+func archiveDir(cmd bootstrapper.CommandRunner, dir, dst string) error {
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	// stderr is kept in the result, and in the error when tar fails
+	_, err = cmd.RunCmd(bootstrapper.RunRequest{
+		Args:   []string{"sudo", "tar", "-C", dir, "-czf", "-", "."},
+		Stdout: f,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// extractDir replaces the contents of a directory in the VM with a tarball.
+func extractDir(cmd bootstrapper.CommandRunner, archive, dir string) error {
+	return cmd.Run(fmt.Sprintf("sudo rm -rf %[2]s && sudo mkdir -p %[2]s && sudo tar -C %[2]s -xzf %[1]s", archive, dir))
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/tests"
+	"k8s.io/minikube/pkg/util"
+)
+
+func setupMinikubeDir(t *testing.T, profile string) string {
+	tempDir := tests.MakeTempDir()
+	for _, c := range hostCerts {
+		if err := ioutil.WriteFile(constants.MakeMiniPath(c), []byte(c), 0600); err != nil {
+			t.Fatalf("Error writing %s: %s", c, err)
+		}
+	}
+	profileFile := constants.GetProfileFile(profile)
+	if err := os.MkdirAll(filepath.Dir(profileFile), 0700); err != nil {
+		t.Fatalf("Error creating profile dir: %s", err)
+	}
+	if err := ioutil.WriteFile(profileFile, []byte("{}"), 0600); err != nil {
+		t.Fatalf("Error writing profile config: %s", err)
+	}
+	return tempDir
+}
+
+func newSnapshotRunner(l layout) *bootstrapper.FakeCommandRunner {
+	f := bootstrapper.NewFakeCommandRunner()
+	cmds := map[string]string{
+		"sudo systemctl stop " + l.service:                              "",
+		"sudo systemctl start " + l.service:                             "",
+		"sudo tar -C " + l.etcdDataDir + " -czf - .":                    "etcd",
+		"sudo tar -C " + util.DefaultCertPath + " -czf - .":             "certs",
+		extractCmd("/tmp/snapshot-"+etcdArchive, l.etcdDataDir):         "",
+		extractCmd("/tmp/snapshot-"+certsArchive, util.DefaultCertPath): "",
+	}
+	if l.restoreCleanCmd != "" {
+		cmds[l.restoreCleanCmd] = ""
+	}
+	// only etcd is running
+	for _, name := range l.restoreContainers {
+		cmds["docker ps -a --filter=name=k8s_"+name+" --format={{.ID}}"] = ""
+	}
+	cmds["docker ps -a --filter=name=k8s_etcd --format={{.ID}}"] = "e7cd\n"
+	cmds["docker stop e7cd"] = ""
+	f.SetCommandToOutput(cmds)
+	return f
+}
+
+func extractCmd(archive, dir string) string {
+	return fmt.Sprintf("sudo rm -rf %[2]s && sudo mkdir -p %[2]s && sudo tar -C %[2]s -xzf %[1]s", archive, dir)
+}
+
+func TestSaveAndRestore(t *testing.T) {
+	for name, l := range layouts {
+		t.Run(name, func(t *testing.T) {
+			tempDir := setupMinikubeDir(t, "minikube")
+			defer os.RemoveAll(tempDir)
+
+			f := newSnapshotRunner(l)
+			s := Snapshot{
+				Name:              "before-upgrade",
+				Profile:           "minikube",
+				Created:           time.Now(),
+				Bootstrapper:      name,
+				KubernetesVersion: "v1.9.0",
+			}
+			if err := Save(f, &cruntime.Docker{}, s); err != nil {
+				t.Fatalf("Error saving snapshot: %s", err)
+			}
+			contents, err := ioutil.ReadFile(filepath.Join(Dir("minikube", s.Name), etcdArchive))
+			if err != nil {
+				t.Fatalf("Error reading etcd archive: %s", err)
+			}
+			if string(contents) != "etcd" {
+				t.Fatalf("Unexpected etcd archive contents: %s", contents)
+			}
+			if err := Save(f, &cruntime.Docker{}, s); err == nil {
+				t.Fatalf("Expected an error saving an existing snapshot")
+			}
+
+			if err := ioutil.WriteFile(constants.MakeMiniPath("ca.crt"), []byte("other"), 0600); err != nil {
+				t.Fatalf("Error writing ca.crt: %s", err)
+			}
+			if _, err := Restore(f, &cruntime.Docker{}, "minikube", s.Name, name); err != nil {
+				t.Fatalf("Error restoring snapshot: %s", err)
+			}
+			contents, err = ioutil.ReadFile(constants.MakeMiniPath("ca.crt"))
+			if err != nil {
+				t.Fatalf("Error reading ca.crt: %s", err)
+			}
+			if string(contents) != "ca.crt" {
+				t.Fatalf("ca.crt was not restored, got: %s", contents)
+			}
+			if _, err := f.GetFileToContents("snapshot-" + etcdArchive); err == nil {
+				t.Fatalf("Uploaded archives were not removed")
+			}
+		})
+	}
+}
+
+func TestRestoreWrongBootstrapper(t *testing.T) {
+	tempDir := setupMinikubeDir(t, "minikube")
+	defer os.RemoveAll(tempDir)
+
+	l := layouts[bootstrapper.BootstrapperTypeKubeadm]
+	f := newSnapshotRunner(l)
+	s := Snapshot{Name: "snap", Profile: "minikube", Bootstrapper: bootstrapper.BootstrapperTypeKubeadm}
+	if err := Save(f, &cruntime.Docker{}, s); err != nil {
+		t.Fatalf("Error saving snapshot: %s", err)
+	}
+	if _, err := Restore(f, &cruntime.Docker{}, "minikube", "snap", bootstrapper.BootstrapperTypeLocalkube); err == nil {
+		t.Fatalf("Expected an error restoring a snapshot from another bootstrapper")
+	}
+}
+
+func TestRestoreFailureKeepsHostFiles(t *testing.T) {
+	tempDir := setupMinikubeDir(t, "minikube")
+	defer os.RemoveAll(tempDir)
+
+	l := layouts[bootstrapper.BootstrapperTypeKubeadm]
+	f := newSnapshotRunner(l)
+	s := Snapshot{Name: "snap", Profile: "minikube", Bootstrapper: bootstrapper.BootstrapperTypeKubeadm}
+	if err := Save(f, &cruntime.Docker{}, s); err != nil {
+		t.Fatalf("Error saving snapshot: %s", err)
+	}
+	if err := ioutil.WriteFile(constants.MakeMiniPath("ca.crt"), []byte("current"), 0600); err != nil {
+		t.Fatalf("Error writing ca.crt: %s", err)
+	}
+
+	// Extracting the etcd data fails
+	failing := bootstrapper.NewFakeCommandRunner()
+	failing.SetCommandToOutput(map[string]string{
+		"sudo systemctl stop kubelet":                                             "",
+		l.restoreCleanCmd:                                                         "",
+		"docker ps -a --filter=name=k8s_etcd --format={{.ID}}":                    "",
+		"docker ps -a --filter=name=k8s_kube-apiserver --format={{.ID}}":          "",
+		"docker ps -a --filter=name=k8s_kube-controller-manager --format={{.ID}}": "",
+		"docker ps -a --filter=name=k8s_kube-scheduler --format={{.ID}}":          "",
+	})
+	if _, err := Restore(failing, &cruntime.Docker{}, "minikube", "snap", bootstrapper.BootstrapperTypeKubeadm); err == nil {
+		t.Fatalf("Expected an error restoring the snapshot")
+	}
+	contents, err := ioutil.ReadFile(constants.MakeMiniPath("ca.crt"))
+	if err != nil {
+		t.Fatalf("Error reading ca.crt: %s", err)
+	}
+	if string(contents) != "current" {
+		t.Fatalf("ca.crt was replaced by a failed restore, got: %s", contents)
+	}
+	if _, err := os.Stat(constants.MakeMiniPath("ca.crt" + restoreSuffix)); !os.IsNotExist(err) {
+		t.Fatalf("Staged ca.crt was not removed")
+	}
+}
+
+func TestSaveStopsRuntimeEtcd(t *testing.T) {
+	tempDir := setupMinikubeDir(t, "minikube")
+	defer os.RemoveAll(tempDir)
+
+	l := layouts[bootstrapper.BootstrapperTypeKubeadm]
+	crictl := "sudo crictl --runtime-endpoint unix:///run/containerd/containerd.sock"
+	f := newSnapshotRunner(l)
+	f.SetCommandToOutput(map[string]string{
+		crictl + " ps -a --quiet --name=etcd": "e7cd\n",
+	})
+	s := Snapshot{Name: "snap", Profile: "minikube", Bootstrapper: bootstrapper.BootstrapperTypeKubeadm}
+	if err := Save(f, &cruntime.Containerd{}, s); err == nil {
+		t.Fatalf("Expected an error saving without stopping the etcd container")
+	}
+	if _, err := os.Stat(Dir("minikube", "snap")); !os.IsNotExist(err) {
+		t.Fatalf("Failed snapshot was not removed")
+	}
+	f.SetCommandToOutput(map[string]string{crictl + " stop e7cd": ""})
+	if err := Save(f, &cruntime.Containerd{}, s); err != nil {
+		t.Fatalf("Error saving snapshot: %s", err)
+	}
+	// the snapshot holds the CA keys, it is only readable by the user
+	for _, name := range []string{etcdArchive, certsArchive, filepath.Join(hostCertsDir, "ca.key")} {
+		fi, err := os.Stat(filepath.Join(Dir("minikube", "snap"), name))
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", name, err)
+		}
+		if fi.Mode().Perm() != 0600 {
+			t.Errorf("%s has mode %v, expected 0600", name, fi.Mode().Perm())
+		}
+	}
+}
+
+func TestArchiveDirFailure(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+
+	f := bootstrapper.NewFakeCommandRunner()
+	f.SetCommandToExitCode(map[string]int{"sudo tar -C /data -czf - .": 2})
+	dst := filepath.Join(tempDir, etcdArchive)
+	if err := archiveDir(f, "/data", dst); err == nil {
+		t.Fatalf("Expected an error when tar fails")
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Fatalf("Partial archive was not removed")
+	}
+}
+
+func TestList(t *testing.T) {
+	tempDir := setupMinikubeDir(t, "minikube")
+	defer os.RemoveAll(tempDir)
+
+	snapshots, err := List("minikube")
+	if err != nil {
+		t.Fatalf("Error listing snapshots: %s", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("Expected no snapshots, got: %v", snapshots)
+	}
+
+	f := newSnapshotRunner(layouts[bootstrapper.BootstrapperTypeLocalkube])
+	now := time.Now()
+	for i, name := range []string{"second", "first"} {
+		s := Snapshot{
+			Name:         name,
+			Profile:      "minikube",
+			Created:      now.Add(-time.Duration(i) * time.Hour),
+			Bootstrapper: bootstrapper.BootstrapperTypeLocalkube,
+		}
+		if err := Save(f, &cruntime.Docker{}, s); err != nil {
+			t.Fatalf("Error saving snapshot: %s", err)
+		}
+	}
+
+	snapshots, err = List("minikube")
+	if err != nil {
+		t.Fatalf("Error listing snapshots: %s", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Name != "first" || snapshots[1].Name != "second" {
+		t.Fatalf("Unexpected snapshots: %v", snapshots)
+	}
+}
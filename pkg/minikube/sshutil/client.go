@@ -0,0 +1,311 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshutil
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	machinessh "github.com/docker/machine/libmachine/ssh"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var (
+	// KeepAliveInterval is how often a pooled connection is checked
+	KeepAliveInterval = 10 * time.Second
+	// KeepAliveTimeout is how long a connection can take to answer a
+	// keepalive before it is considered broken
+	KeepAliveTimeout = 15 * time.Second
+	// DialTimeout bounds the connection and the SSH handshake of a dial
+	DialTimeout = 20 * time.Second
+	// DialRetries is how many times a network failure is retried
+	DialRetries = 3
+	// dialRetryDelay is the delay before the first retry, doubled for each
+	// of the following ones
+	dialRetryDelay = time.Second
+)
+
+// AuthError is returned when the machine refuses the credentials, which
+// retrying does not help with.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("ssh authentication failed: %s", e.Err)
+}
+
+// Client is an SSH connection to a machine, shared by the callers of
+// GetClient. It sends keepalives so that a connection broken by a suspended
+// or restarted VM is noticed, and redials when it is used again.
+type Client struct {
+	addr   string
+	config *ssh.ClientConfig
+
+	mu   sync.Mutex
+	conn *ssh.Client
+}
+
+var (
+	poolMu sync.Mutex
+	pool   = map[string]*Client{}
+)
+
+// GetClient returns the client to the machine of the driver, dialing it if
+// there is none yet.
+func GetClient(d drivers.Driver) (*Client, error) {
+	h, err := newSSHHost(d)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating new ssh host from driver")
+	}
+	config, err := newClientConfig(h)
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(h.IP, strconv.Itoa(h.Port))
+	key := fmt.Sprintf("%s@%s %s", h.Username, addr, h.SSHKeyPath)
+
+	poolMu.Lock()
+	c, ok := pool[key]
+	if !ok {
+		c = &Client{addr: addr, config: config}
+		pool[key] = c
+	}
+	poolMu.Unlock()
+
+	if _, err := c.client(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// client returns the connection of c, dialing it if needed.
+func (c *Client) client() (*ssh.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	conn, err := dial(c.addr, c.config)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	go c.keepAlive(conn)
+	return conn, nil
+}
+
+// broken closes conn, so that the next use of c dials a new connection.
+func (c *Client) broken(conn *ssh.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	conn.Close()
+}
+
+func (c *Client) keepAlive(conn *ssh.Client) {
+	closed := make(chan struct{})
+	go func() {
+		conn.Wait()
+		close(closed)
+	}()
+	t := time.NewTicker(KeepAliveInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-closed:
+			c.broken(conn)
+			return
+		case <-t.C:
+			if err := ping(conn, KeepAliveTimeout); err != nil {
+				glog.Infof("ssh connection to %s is broken: %s", c.addr, err)
+				c.broken(conn)
+				return
+			}
+		}
+	}
+}
+
+// ping sends a keepalive request. Servers answer requests they do not know
+// with a failure, which is fine: only the lack of an answer matters.
+func ping(conn *ssh.Client, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := conn.SendRequest("keepalive@openssh.com", true, nil)
+		errs <- err
+	}()
+	select {
+	case err := <-errs:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("no keepalive answer in %s", timeout)
+	}
+}
+
+// NewSession opens a session, redialing once if the connection broke.
+func (c *Client) NewSession() (*ssh.Session, error) {
+	conn, err := c.client()
+	if err != nil {
+		return nil, err
+	}
+	sess, err := conn.NewSession()
+	if err == nil {
+		return sess, nil
+	}
+	glog.Infof("Error opening ssh session, redialing: %s", err)
+	c.broken(conn)
+	if conn, err = c.client(); err != nil {
+		return nil, err
+	}
+	return conn.NewSession()
+}
+
+// Dial connects to addr from the machine, redialing once if the connection
+// broke.
+func (c *Client) Dial(n, addr string) (net.Conn, error) {
+	conn, err := c.client()
+	if err != nil {
+		return nil, err
+	}
+	nc, err := conn.Dial(n, addr)
+	if err == nil {
+		return nc, nil
+	}
+	if _, ok := err.(*ssh.OpenChannelError); ok {
+		// the machine refused the connection, the ssh connection is fine
+		return nil, err
+	}
+	glog.Infof("Error dialing %s through ssh, redialing: %s", addr, err)
+	c.broken(conn)
+	if conn, err = c.client(); err != nil {
+		return nil, err
+	}
+	return conn.Dial(n, addr)
+}
+
+// Close closes the connection of c. It is dialed again when c is used.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	conn := c.conn
+	c.conn = nil
+	c.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+// Shell runs the command of args on the machine, or an interactive shell
+// when there are none, with the terminal of the process.
+func (c *Client) Shell(args ...string) error {
+	sess, err := c.NewSession()
+	if err != nil {
+		return errors.Wrap(err, "opening ssh session")
+	}
+	defer sess.Close()
+	sess.Stdin = os.Stdin
+	sess.Stdout = os.Stdout
+	sess.Stderr = os.Stderr
+
+	width, height := 80, 24
+	fd := int(os.Stdin.Fd())
+	if terminal.IsTerminal(fd) {
+		oldState, err := terminal.MakeRaw(fd)
+		if err != nil {
+			return errors.Wrap(err, "setting terminal to raw mode")
+		}
+		defer terminal.Restore(fd, oldState)
+		if w, h, err := terminal.GetSize(fd); err == nil {
+			width, height = w, h
+		}
+	}
+	if err := sess.RequestPty("xterm", height, width, ssh.TerminalModes{ssh.ECHO: 1}); err != nil {
+		return errors.Wrap(err, "requesting pty")
+	}
+	if len(args) > 0 {
+		return sess.Run(strings.Join(args, " "))
+	}
+	if err := sess.Shell(); err != nil {
+		return errors.Wrap(err, "starting shell")
+	}
+	return sess.Wait()
+}
+
+func newClientConfig(h *sshHost) (*ssh.ClientConfig, error) {
+	auth := &machinessh.Auth{}
+	if h.SSHKeyPath != "" {
+		auth.Keys = []string{h.SSHKeyPath}
+	}
+	config, err := machinessh.NewNativeConfig(h.Username, auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error creating new native config from ssh using: %s, %s", h.Username, auth)
+	}
+	return &config, nil
+}
+
+// dial connects to addr, retrying network failures but not authentication
+// failures.
+func dial(addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	delay := dialRetryDelay
+	var err error
+	for attempt := 0; ; attempt++ {
+		var client *ssh.Client
+		if client, err = dialOnce(addr, config); err == nil {
+			return client, nil
+		}
+		if _, ok := err.(*AuthError); ok || attempt >= DialRetries {
+			return nil, err
+		}
+		glog.Infof("Error dialing %s, retrying in %s: %s", addr, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func dialOnce(addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, DialTimeout)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error dialing tcp via ssh client")
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetKeepAlive(true)
+		tcp.SetKeepAlivePeriod(KeepAliveInterval)
+	}
+	// The deadline bounds the handshake, and is cleared once it is done
+	conn.SetDeadline(time.Now().Add(DialTimeout))
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		if strings.Contains(err.Error(), "unable to authenticate") {
+			return nil, &AuthError{err}
+		}
+		return nil, errors.Wrap(err, "Error during ssh handshake")
+	}
+	conn.SetDeadline(time.Time{})
+	return ssh.NewClient(c, chans, reqs), nil
+}
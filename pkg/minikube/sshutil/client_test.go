@@ -0,0 +1,143 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sshutil
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+func startServer(t *testing.T, configure func(*tests.SSHServer)) *tests.MockDriver {
+	s, err := tests.NewSSHServer()
+	if err != nil {
+		t.Fatalf("Error creating ssh server: %s", err)
+	}
+	if configure != nil {
+		configure(s)
+	}
+	port, err := s.Start()
+	if err != nil {
+		t.Fatalf("Error starting ssh server: %s", err)
+	}
+	return &tests.MockDriver{
+		Port:       port,
+		BaseDriver: drivers.BaseDriver{IPAddress: "127.0.0.1"},
+	}
+}
+
+func TestGetClientIsShared(t *testing.T) {
+	d := startServer(t, nil)
+	c1, err := GetClient(d)
+	if err != nil {
+		t.Fatalf("Error getting client: %s", err)
+	}
+	c2, err := GetClient(d)
+	if err != nil {
+		t.Fatalf("Error getting client: %s", err)
+	}
+	if c1 != c2 {
+		t.Errorf("Expected the client to be shared")
+	}
+}
+
+func TestClientRedials(t *testing.T) {
+	c, err := GetClient(startServer(t, nil))
+	if err != nil {
+		t.Fatalf("Error getting client: %s", err)
+	}
+	first, _ := c.client()
+	// A connection broken under the client is replaced on the next use
+	first.Close()
+	sess, err := c.NewSession()
+	if err != nil {
+		t.Fatalf("Error opening session on a broken connection: %s", err)
+	}
+	sess.Close()
+	if second, _ := c.client(); second == first {
+		t.Errorf("Expected a new connection")
+	}
+
+	c.Close()
+	sess, err = c.NewSession()
+	if err != nil {
+		t.Fatalf("Error opening session after Close: %s", err)
+	}
+	sess.Close()
+}
+
+func TestDialAuthFailureIsNotRetried(t *testing.T) {
+	d := startServer(t, func(s *tests.SSHServer) {
+		s.Config.NoClientAuth = false
+		s.Config.PasswordCallback = func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) {
+			return nil, errors.New("denied")
+		}
+	})
+	defer func(delay time.Duration) { dialRetryDelay = delay }(dialRetryDelay)
+	dialRetryDelay = time.Minute
+
+	_, err := NewSSHClient(d)
+	if _, ok := err.(*AuthError); !ok {
+		t.Fatalf("Expected an AuthError, got %v", err)
+	}
+}
+
+func TestDialNetworkFailureIsRetried(t *testing.T) {
+	// A listener that never answers the handshake
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error listening: %s", err)
+	}
+	defer l.Close()
+	var accepted int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepted, 1)
+			defer conn.Close()
+		}
+	}()
+	defer func(timeout, delay time.Duration, retries int) {
+		DialTimeout, dialRetryDelay, DialRetries = timeout, delay, retries
+	}(DialTimeout, dialRetryDelay, DialRetries)
+	DialTimeout, dialRetryDelay, DialRetries = 100*time.Millisecond, time.Millisecond, 2
+
+	start := time.Now()
+	_, err = dial(l.Addr().String(), &ssh.ClientConfig{HostKeyCallback: ssh.InsecureIgnoreHostKey()})
+	if err == nil {
+		t.Fatalf("Expected an error dialing a server that does not answer")
+	}
+	if _, ok := err.(*AuthError); ok {
+		t.Errorf("Expected a network error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Dial did not time out, took %s", elapsed)
+	}
+	if n := atomic.LoadInt32(&accepted); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+}
@@ -21,32 +21,24 @@ import (
 	"strconv"
 
 	"github.com/docker/machine/libmachine/drivers"
-	machinessh "github.com/docker/machine/libmachine/ssh"
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/ssh"
 )
 
-// NewSSHClient returns an SSH client object for running commands.
+// NewSSHClient returns an SSH client object for running commands, on a
+// connection of its own. Use GetClient to share one with the rest of the
+// process.
 func NewSSHClient(d drivers.Driver) (*ssh.Client, error) {
 	h, err := newSSHHost(d)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error creating new ssh host from driver")
 
 	}
-	auth := &machinessh.Auth{}
-	if h.SSHKeyPath != "" {
-		auth.Keys = []string{h.SSHKeyPath}
-	}
-	config, err := machinessh.NewNativeConfig(h.Username, auth)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Error creating new native config from ssh using: %s, %s", h.Username, auth)
-	}
-
-	client, err := ssh.Dial("tcp", net.JoinHostPort(h.IP, strconv.Itoa(h.Port)), &config)
+	config, err := newClientConfig(h)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error dialing tcp via ssh client")
+		return nil, err
 	}
-	return client, nil
+	return dial(net.JoinHostPort(h.IP, strconv.Itoa(h.Port)), config)
 }
 
 type sshHost struct {
@@ -108,11 +108,6 @@ func (s *SSHServer) Start() (int, error) {
 
 					for req := range requests {
 						glog.Infoln("Got Req: ", req.Type)
-						// Store anything that comes in over stdin.
-						go func() {
-							io.Copy(s.Transfers, channel)
-							channel.Close()
-						}()
 						switch req.Type {
 						case "exec":
 							req.Reply(true, nil)
@@ -136,6 +131,12 @@ func (s *SSHServer) Start() (int, error) {
 
 							channel.SendRequest("exit-status", false, []byte{0, 0, 0, 0})
 						}
+						// Store anything that comes in over stdin. This starts after the
+						// request is handled, as the channel is closed once stdin is.
+						go func() {
+							io.Copy(s.Transfers, channel)
+							channel.Close()
+						}()
 					}
 				}
 			}()
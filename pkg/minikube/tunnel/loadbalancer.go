@@ -0,0 +1,90 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PatchLoadBalancers sets the ingress of every LoadBalancer service to its
+// cluster IP, which the host can reach through the tunnel route. It returns
+// the services whose ingress changed, as namespace/name.
+func PatchLoadBalancers(client corev1.CoreV1Interface) ([]string, error) {
+	return updateLoadBalancers(client, func(svc v1.Service) []v1.LoadBalancerIngress {
+		return []v1.LoadBalancerIngress{{IP: svc.Spec.ClusterIP}}
+	})
+}
+
+// ResetLoadBalancers removes the ingress set by PatchLoadBalancers, so that
+// the services are pending again once the tunnel is gone
+func ResetLoadBalancers(client corev1.CoreV1Interface) ([]string, error) {
+	return updateLoadBalancers(client, func(svc v1.Service) []v1.LoadBalancerIngress {
+		if !hasClusterIPIngress(svc) {
+			return svc.Status.LoadBalancer.Ingress
+		}
+		return nil
+	})
+}
+
+func hasClusterIPIngress(svc v1.Service) bool {
+	ingress := svc.Status.LoadBalancer.Ingress
+	return len(ingress) == 1 && ingress[0].IP == svc.Spec.ClusterIP && ingress[0].Hostname == ""
+}
+
+func updateLoadBalancers(client corev1.CoreV1Interface, ingressFor func(v1.Service) []v1.LoadBalancerIngress) ([]string, error) {
+	services := client.Services(metav1.NamespaceAll)
+	svcs, err := services.List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing services")
+	}
+	var changed []string
+	var errs []string
+	for _, svc := range svcs.Items {
+		if svc.Spec.Type != v1.ServiceTypeLoadBalancer || svc.Spec.ClusterIP == "" || svc.Spec.ClusterIP == v1.ClusterIPNone {
+			continue
+		}
+		ingress := ingressFor(svc)
+		if equalIngress(ingress, svc.Status.LoadBalancer.Ingress) {
+			continue
+		}
+		svc.Status.LoadBalancer.Ingress = ingress
+		if _, err := client.Services(svc.Namespace).UpdateStatus(&svc); err != nil {
+			errs = append(errs, errors.Wrapf(err, "updating %s/%s", svc.Namespace, svc.Name).Error())
+			continue
+		}
+		changed = append(changed, svc.Namespace+"/"+svc.Name)
+	}
+	if len(errs) > 0 {
+		return changed, errors.Errorf("updating load balancer ingress: %v", errs)
+	}
+	return changed, nil
+}
+
+func equalIngress(a, b []v1.LoadBalancerIngress) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Entry records a running tunnel, so that its route can be cleaned up if
+// the tunnel dies without removing it
+type Entry struct {
+	Machine  string `json:"machine"`
+	Pid      int    `json:"pid"`
+	DestCIDR string `json:"destCIDR"`
+	Gateway  string `json:"gateway"`
+}
+
+// Route returns the route installed by the tunnel of e
+func (e Entry) Route() (*Route, error) {
+	return NewRoute(e.DestCIDR, net.ParseIP(e.Gateway))
+}
+
+// Registry is the file recording the running tunnels
+type Registry struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewRegistry returns the registry stored in path
+func NewRegistry(path string) *Registry {
+	return &Registry{path: path}
+}
+
+// List returns the entries of the registry
+func (r *Registry) List() ([]Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load()
+}
+
+// Register records e, replacing any entry for the same machine
+func (r *Registry) Register(e Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries, err := r.load()
+	if err != nil {
+		return err
+	}
+	entries = append(without(entries, e.Machine), e)
+	return r.save(entries)
+}
+
+// Remove deletes the entry of machine
+func (r *Registry) Remove(machine string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries, err := r.load()
+	if err != nil {
+		return err
+	}
+	return r.save(without(entries, machine))
+}
+
+func without(entries []Entry, machine string) []Entry {
+	var kept []Entry
+	for _, e := range entries {
+		if e.Machine != machine {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func (r *Registry) load() ([]Entry, error) {
+	b, err := ioutil.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading tunnel registry")
+	}
+	var entries []Entry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, errors.Wrapf(err, "parsing tunnel registry %s", r.path)
+	}
+	return entries, nil
+}
+
+func (r *Registry) save(entries []Entry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "removing tunnel registry")
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "encoding tunnel registry")
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return errors.Wrap(err, "making tunnel registry directory")
+	}
+	tmp := r.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return errors.Wrap(err, "writing tunnel registry")
+	}
+	return os.Rename(tmp, r.path)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// Route sends the traffic for DestCIDR through Gateway
+type Route struct {
+	DestCIDR *net.IPNet
+	Gateway  net.IP
+}
+
+// NewRoute returns the route to cidr through gateway
+func NewRoute(cidr string, gateway net.IP) (*Route, error) {
+	_, dest, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing CIDR %s", cidr)
+	}
+	if gateway == nil {
+		return nil, errors.New("no gateway for route")
+	}
+	return &Route{DestCIDR: dest, Gateway: gateway}, nil
+}
+
+// Equal returns whether r and o route the same CIDR through the same gateway
+func (r *Route) Equal(o *Route) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return r.DestCIDR.String() == o.DestCIDR.String() && r.Gateway.Equal(o.Gateway)
+}
+
+func (r *Route) String() string {
+	return fmt.Sprintf("%s via %s", r.DestCIDR, r.Gateway)
+}
+
+// runCommand runs a command on the host. The command may prompt for a sudo
+// password, so it is attached to the terminal.
+var runCommand = func(name string, args ...string) ([]byte, error) {
+	glog.Infof("Running %s %s", name, strings.Join(args, " "))
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	return cmd.CombinedOutput()
+}
+
+// addRouteCommand returns the command adding r to the routing table of goos
+func addRouteCommand(goos string, r *Route) []string {
+	switch goos {
+	case "windows":
+		return []string{"route", "ADD", r.DestCIDR.IP.String(), "MASK", net.IP(r.DestCIDR.Mask).String(), r.Gateway.String()}
+	case "darwin":
+		return []string{"sudo", "route", "-n", "add", r.DestCIDR.String(), r.Gateway.String()}
+	default:
+		return []string{"sudo", "ip", "route", "add", r.DestCIDR.String(), "via", r.Gateway.String()}
+	}
+}
+
+// deleteRouteCommand returns the command deleting r from the routing table of goos
+func deleteRouteCommand(goos string, r *Route) []string {
+	switch goos {
+	case "windows":
+		return []string{"route", "DELETE", r.DestCIDR.IP.String()}
+	case "darwin":
+		return []string{"sudo", "route", "-n", "delete", r.DestCIDR.String()}
+	default:
+		return []string{"sudo", "ip", "route", "delete", r.DestCIDR.String()}
+	}
+}
+
+// routeExists returns whether the output of a failed route add says that
+// there already is a route for the destination
+func routeExists(out string) bool {
+	return strings.Contains(out, "File exists") || strings.Contains(out, "already exists")
+}
+
+// AddRoute adds r to the routing table of the host. A route for the same
+// destination, left behind by a tunnel that crashed or through an old IP of
+// the VM, is replaced.
+func AddRoute(r *Route) error {
+	add := addRouteCommand(runtime.GOOS, r)
+	out, err := runCommand(add[0], add[1:]...)
+	if err == nil {
+		return nil
+	}
+	if !routeExists(string(out)) {
+		return errors.Wrapf(err, "adding route %s: %s", r, out)
+	}
+	glog.Infof("Replacing existing route for %s", r.DestCIDR)
+	if err := DeleteRoute(r); err != nil {
+		return err
+	}
+	if out, err := runCommand(add[0], add[1:]...); err != nil {
+		return errors.Wrapf(err, "adding route %s: %s", r, out)
+	}
+	return nil
+}
+
+// DeleteRoute removes r from the routing table of the host
+func DeleteRoute(r *Route) error {
+	del := deleteRouteCommand(runtime.GOOS, r)
+	if out, err := runCommand(del[0], del[1:]...); err != nil {
+		return errors.Wrapf(err, "deleting route %s: %s", r, out)
+	}
+	return nil
+}
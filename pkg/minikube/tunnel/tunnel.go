@@ -0,0 +1,209 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/minikube/pkg/minikube/cluster"
+)
+
+// Tunnel routes the service CIDR of a cluster from the host to its VM, and
+// gives its LoadBalancer services an ingress IP reachable through the route
+type Tunnel struct {
+	machine   string
+	cidr      string
+	api       libmachine.API
+	registry  *Registry
+	getClient func() (corev1.CoreV1Interface, error)
+	out       io.Writer
+
+	route *Route
+}
+
+// NewTunnel returns the tunnel routing cidr to the VM of machine
+func NewTunnel(api libmachine.API, machine, cidr string, registry *Registry, getClient func() (corev1.CoreV1Interface, error), out io.Writer) *Tunnel {
+	return &Tunnel{
+		machine:   machine,
+		cidr:      cidr,
+		api:       api,
+		registry:  registry,
+		getClient: getClient,
+		out:       out,
+	}
+}
+
+// Run keeps the tunnel up every interval until done is closed, then removes
+// its route and the ingress of the LoadBalancer services
+func (t *Tunnel) Run(done <-chan struct{}, interval time.Duration) error {
+	if err := t.checkNotRunning(); err != nil {
+		return err
+	}
+	defer t.cleanup()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := t.update(); err != nil {
+			fmt.Fprintf(t.out, "Error updating tunnel: %s\n", err)
+		}
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkNotRunning fails if another live tunnel is running for the machine
+func (t *Tunnel) checkNotRunning() error {
+	entries, err := t.registry.List()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Machine == t.machine && e.Pid != os.Getpid() && processAlive(e.Pid) {
+			return errors.Errorf("another tunnel is already running for %s (pid %d)", t.machine, e.Pid)
+		}
+	}
+	return nil
+}
+
+func (t *Tunnel) update() error {
+	s, err := cluster.GetNamedHostStatus(t.api, t.machine)
+	if err != nil {
+		return err
+	}
+	if s != state.Running.String() {
+		if t.route != nil {
+			fmt.Fprintf(t.out, "%s is %s, removing route %s\n", t.machine, s, t.route)
+			t.removeRoute()
+		}
+		return nil
+	}
+
+	h, err := t.api.Load(t.machine)
+	if err != nil {
+		return errors.Wrapf(err, "loading %s", t.machine)
+	}
+	ip, err := h.Driver.GetIP()
+	if err != nil {
+		return errors.Wrap(err, "getting VM IP")
+	}
+	route, err := NewRoute(t.cidr, net.ParseIP(ip))
+	if err != nil {
+		return err
+	}
+	if !route.Equal(t.route) {
+		if t.route != nil {
+			t.removeRoute()
+		}
+		if err := t.registry.Register(Entry{Machine: t.machine, Pid: os.Getpid(), DestCIDR: t.cidr, Gateway: ip}); err != nil {
+			return err
+		}
+		if err := AddRoute(route); err != nil {
+			t.registry.Remove(t.machine)
+			return err
+		}
+		t.route = route
+		fmt.Fprintf(t.out, "Added route %s\n", route)
+	}
+
+	client, err := t.getClient()
+	if err != nil {
+		return errors.Wrap(err, "getting kubernetes client")
+	}
+	patched, err := PatchLoadBalancers(client)
+	for _, svc := range patched {
+		fmt.Fprintf(t.out, "Set the ingress IP of LoadBalancer service %s\n", svc)
+	}
+	return err
+}
+
+func (t *Tunnel) removeRoute() {
+	if err := DeleteRoute(t.route); err != nil {
+		fmt.Fprintf(t.out, "Error removing route: %s\n", err)
+	}
+	if err := t.registry.Remove(t.machine); err != nil {
+		glog.Errorf("Error updating tunnel registry: %s", err)
+	}
+	t.route = nil
+}
+
+func (t *Tunnel) cleanup() {
+	if t.route == nil {
+		return
+	}
+	if client, err := t.getClient(); err != nil {
+		glog.Errorf("Error getting kubernetes client: %s", err)
+	} else if _, err := ResetLoadBalancers(client); err != nil {
+		glog.Errorf("Error resetting LoadBalancer services: %s", err)
+	}
+	fmt.Fprintf(t.out, "Removing route %s\n", t.route)
+	t.removeRoute()
+}
+
+// CleanupStale removes the routes of the registered tunnels whose process
+// is gone, such as tunnels that crashed or were killed, and returns them
+func CleanupStale(registry *Registry) ([]Entry, error) {
+	entries, err := registry.List()
+	if err != nil {
+		return nil, err
+	}
+	var cleaned []Entry
+	for _, e := range entries {
+		if processAlive(e.Pid) {
+			continue
+		}
+		r, err := e.Route()
+		if err != nil {
+			return cleaned, err
+		}
+		if err := DeleteRoute(r); err != nil {
+			glog.Warningf("Error removing stale route %s: %s", r, err)
+		}
+		if err := registry.Remove(e.Machine); err != nil {
+			return cleaned, err
+		}
+		cleaned = append(cleaned, e)
+	}
+	return cleaned, nil
+}
+
+// processAlive returns whether the process pid is running
+var processAlive = func(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// FindProcess fails on windows when there is no such process
+		return true
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	goruntime "runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/machine/libmachine/drivers"
+	"github.com/docker/machine/libmachine/host"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/minikube/pkg/minikube/tests"
+)
+
+// fakeRunner records the commands run, and fails those in fail with their output
+type fakeRunner struct {
+	commands []string
+	fail     map[string]string
+}
+
+func (f *fakeRunner) run(name string, args ...string) ([]byte, error) {
+	c := strings.Join(append([]string{name}, args...), " ")
+	f.commands = append(f.commands, c)
+	if out, ok := f.fail[c]; ok {
+		delete(f.fail, c)
+		return []byte(out), errors.New("exit status 2")
+	}
+	return nil, nil
+}
+
+func useFakeRunner(f *fakeRunner) func() {
+	orig := runCommand
+	runCommand = f.run
+	return func() { runCommand = orig }
+}
+
+func goos() string {
+	return goruntime.GOOS
+}
+
+func TestRouteCommands(t *testing.T) {
+	r, err := NewRoute("10.96.0.0/12", net.ParseIP("192.168.99.100"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var tests = []struct {
+		goos        string
+		add, delete string
+	}{
+		{"linux", "sudo ip route add 10.96.0.0/12 via 192.168.99.100", "sudo ip route delete 10.96.0.0/12"},
+		{"darwin", "sudo route -n add 10.96.0.0/12 192.168.99.100", "sudo route -n delete 10.96.0.0/12"},
+		{"windows", "route ADD 10.96.0.0 MASK 255.240.0.0 192.168.99.100", "route DELETE 10.96.0.0"},
+	}
+	for _, test := range tests {
+		if got := strings.Join(addRouteCommand(test.goos, r), " "); got != test.add {
+			t.Errorf("%s: add route command = %q, expected %q", test.goos, got, test.add)
+		}
+		if got := strings.Join(deleteRouteCommand(test.goos, r), " "); got != test.delete {
+			t.Errorf("%s: delete route command = %q, expected %q", test.goos, got, test.delete)
+		}
+	}
+}
+
+func TestAddRouteReplacesExisting(t *testing.T) {
+	r, _ := NewRoute("10.96.0.0/12", net.ParseIP("192.168.99.100"))
+	add := strings.Join(addRouteCommand(goos(), r), " ")
+	f := &fakeRunner{fail: map[string]string{add: "RTNETLINK answers: File exists"}}
+	defer useFakeRunner(f)()
+
+	if err := AddRoute(r); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []string{add, strings.Join(deleteRouteCommand(goos(), r), " "), add}
+	if !reflect.DeepEqual(f.commands, expected) {
+		t.Errorf("Commands = %v, expected %v", f.commands, expected)
+	}
+
+	f = &fakeRunner{fail: map[string]string{add: "Network is unreachable"}}
+	useFakeRunner(f)
+	if err := AddRoute(r); err == nil {
+		t.Errorf("Expected an error when the route can not be added")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	reg := NewRegistry(filepath.Join(tempDir, "tunnels.json"))
+
+	a := Entry{Machine: "minikube", Pid: 1, DestCIDR: "10.96.0.0/12", Gateway: "192.168.99.100"}
+	b := Entry{Machine: "other", Pid: 2, DestCIDR: "10.96.0.0/12", Gateway: "192.168.99.101"}
+	for _, e := range []Entry{a, b, a} {
+		if err := reg.Register(e); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	entries, err := reg.List()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(entries, []Entry{b, a}) {
+		t.Errorf("Entries = %v, expected %v", entries, []Entry{b, a})
+	}
+
+	reg.Remove("other")
+	reg.Remove("minikube")
+	if _, err := os.Stat(filepath.Join(tempDir, "tunnels.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected the empty registry to be removed, got %v", err)
+	}
+}
+
+func TestCleanupStale(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	reg := NewRegistry(filepath.Join(tempDir, "tunnels.json"))
+	f := &fakeRunner{}
+	defer useFakeRunner(f)()
+	origAlive := processAlive
+	processAlive = func(pid int) bool { return pid == 1 }
+	defer func() { processAlive = origAlive }()
+
+	live := Entry{Machine: "minikube", Pid: 1, DestCIDR: "10.96.0.0/12", Gateway: "192.168.99.100"}
+	dead := Entry{Machine: "other", Pid: 2, DestCIDR: "10.96.0.0/12", Gateway: "192.168.99.101"}
+	reg.Register(live)
+	reg.Register(dead)
+
+	cleaned, err := CleanupStale(reg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(cleaned, []Entry{dead}) {
+		t.Errorf("Cleaned = %v, expected %v", cleaned, []Entry{dead})
+	}
+	r, _ := dead.Route()
+	if expected := []string{strings.Join(deleteRouteCommand(goos(), r), " ")}; !reflect.DeepEqual(f.commands, expected) {
+		t.Errorf("Commands = %v, expected %v", f.commands, expected)
+	}
+	entries, _ := reg.List()
+	if !reflect.DeepEqual(entries, []Entry{live}) {
+		t.Errorf("Entries = %v, expected %v", entries, []Entry{live})
+	}
+}
+
+func loadBalancer(name, clusterIP string, ingress ...string) v1.Service {
+	svc := v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: clusterIP},
+	}
+	for _, ip := range ingress {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, v1.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+// fakeServices returns a client listing services, and the services it updated
+func fakeServices(services ...v1.Service) (corev1.CoreV1Interface, *[]v1.Service) {
+	client := &fake.FakeCoreV1{Fake: &core.Fake{}}
+	var updated []v1.Service
+	client.AddReactor("list", "services", func(core.Action) (bool, runtime.Object, error) {
+		return true, &v1.ServiceList{Items: services}, nil
+	})
+	client.AddReactor("update", "services", func(action core.Action) (bool, runtime.Object, error) {
+		svc := action.(core.UpdateAction).GetObject().(*v1.Service)
+		updated = append(updated, *svc)
+		return true, svc, nil
+	})
+	return client, &updated
+}
+
+func TestPatchLoadBalancers(t *testing.T) {
+	nodePort := loadBalancer("nodeport", "10.96.0.20")
+	nodePort.Spec.Type = v1.ServiceTypeNodePort
+	client, updated := fakeServices(
+		loadBalancer("pending", "10.96.0.10"),
+		loadBalancer("patched", "10.96.0.11", "10.96.0.11"),
+		loadBalancer("headless", v1.ClusterIPNone),
+		nodePort,
+	)
+
+	changed, err := PatchLoadBalancers(client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(changed, []string{"default/pending"}) {
+		t.Errorf("Changed = %v, expected [default/pending]", changed)
+	}
+	if len(*updated) != 1 || !hasClusterIPIngress((*updated)[0]) {
+		t.Errorf("Expected the cluster IP as ingress of pending, got %v", *updated)
+	}
+
+	client, updated = fakeServices(
+		loadBalancer("patched", "10.96.0.11", "10.96.0.11"),
+		loadBalancer("external", "10.96.0.12", "203.0.113.7"),
+	)
+	changed, err = ResetLoadBalancers(client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(changed, []string{"default/patched"}) {
+		t.Errorf("Changed = %v, expected [default/patched]", changed)
+	}
+	if len(*updated) != 1 || len((*updated)[0].Status.LoadBalancer.Ingress) != 0 {
+		t.Errorf("Expected the ingress of patched to be removed, got %v", *updated)
+	}
+}
+
+func TestTunnelRun(t *testing.T) {
+	tempDir := tests.MakeTempDir()
+	defer os.RemoveAll(tempDir)
+	reg := NewRegistry(filepath.Join(tempDir, "tunnels.json"))
+	f := &fakeRunner{}
+	defer useFakeRunner(f)()
+
+	api := tests.NewMockAPI()
+	api.Hosts["minikube"] = &host.Host{
+		Name: "minikube",
+		Driver: &tests.MockDriver{
+			CurrentState: state.Running,
+			BaseDriver:   drivers.BaseDriver{IPAddress: "192.168.99.100"},
+		},
+	}
+	client, updated := fakeServices(loadBalancer("web", "10.96.0.10"))
+	getClient := func() (corev1.CoreV1Interface, error) { return client, nil }
+
+	var out bytes.Buffer
+	done := make(chan struct{})
+	close(done)
+	tun := NewTunnel(api, "minikube", "10.96.0.0/12", reg, getClient, &out)
+	if err := tun.Run(done, time.Second); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	r, _ := NewRoute("10.96.0.0/12", net.ParseIP("192.168.99.100"))
+	expected := []string{
+		strings.Join(addRouteCommand(goos(), r), " "),
+		strings.Join(deleteRouteCommand(goos(), r), " "),
+	}
+	if !reflect.DeepEqual(f.commands, expected) {
+		t.Errorf("Commands = %v, expected %v", f.commands, expected)
+	}
+	if len(*updated) == 0 || !hasClusterIPIngress((*updated)[0]) {
+		t.Errorf("Expected the ingress of web to be set, got %v", *updated)
+	}
+	if entries, _ := reg.List(); len(entries) != 0 {
+		t.Errorf("Expected the tunnel to be unregistered, got %v", entries)
+	}
+	if !strings.Contains(out.String(), "Set the ingress IP of LoadBalancer service default/web") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	// a live tunnel for the same machine blocks a second one
+	reg.Register(Entry{Machine: "minikube", Pid: os.Getppid(), DestCIDR: "10.96.0.0/12", Gateway: "192.168.99.100"})
+	if err := tun.Run(done, time.Second); err == nil {
+		t.Errorf("Expected an error with another tunnel running")
+	}
+}
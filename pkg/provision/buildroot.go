@@ -273,19 +273,21 @@ func configureAuth(p *BuildrootProvisioner) error {
 		authOptions.ServerKeyPath:  authOptions.ServerKeyRemotePath,
 	}
 
-	sshClient, err := sshutil.NewSSHClient(driver)
+	sshClient, err := sshutil.GetClient(driver)
 	if err != nil {
 		return errors.Wrap(err, "provisioning: error getting ssh client")
 	}
 	sshRunner := bootstrapper.NewSSHRunner(sshClient)
+	var files []assets.CopyableFile
 	for src, dst := range remoteCerts {
 		f, err := assets.NewFileAsset(src, path.Dir(dst), filepath.Base(dst), "0640")
 		if err != nil {
 			return errors.Wrapf(err, "error copying %s to %s", src, dst)
 		}
-		if err := sshRunner.Copy(f); err != nil {
-			return errors.Wrapf(err, "transferring file to machine %v", f)
-		}
+		files = append(files, f)
+	}
+	if err := sshRunner.CopyAll(files); err != nil {
+		return errors.Wrap(err, "transferring certs to machine")
 	}
 
 	dockerCfg, err := p.GenerateDockerOptions(engine.DefaultPort)
@@ -39,8 +39,13 @@ func GenerateCACert(certPath, keyPath string, name string) error {
 		return errors.Wrap(err, "Error generating rsa key")
 	}
 
+	serial, err := newSerialNumber()
+	if err != nil {
+		return err
+	}
+
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(1),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName: name,
 		},
@@ -77,17 +82,18 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 	if err != nil {
 		return errors.Wrap(err, "Error reading file: signerKeyPath")
 	}
-	decodedSignerKey, _ := pem.Decode(signerKeyBytes)
-	if decodedSignerKey == nil {
-		return errors.New("Unable to decode key.")
+	signerKey, err := parseRSAPrivateKey(signerKeyBytes)
+	if err != nil {
+		return err
 	}
-	signerKey, err := x509.ParsePKCS1PrivateKey(decodedSignerKey.Bytes)
+
+	serial, err := newSerialNumber()
 	if err != nil {
-		return errors.Wrap(err, "Error parsing prive key: decodedSignerKey.Bytes")
+		return err
 	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(2),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			CommonName:   cn,
 			Organization: []string{"system:masters"},
@@ -108,7 +114,97 @@ func GenerateSignedCert(certPath, keyPath, cn string, ips []net.IP, alternateDNS
 		return errors.Wrap(err, "Error loading or generating private key: keyPath")
 	}
 
-	return writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey)
+	if err := writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey); err != nil {
+		return err
+	}
+
+	// Clients only trust the root CA when the signer is an intermediate CA,
+	// so the cert has to be served along with the chain of the signer
+	if bytes.Equal(signerCert.RawSubject, signerCert.RawIssuer) {
+		return nil
+	}
+	certFile, err := os.OpenFile(certPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "Error opening certificate to append the chain")
+	}
+	defer certFile.Close()
+	if _, err := certFile.Write(signerCertBytes); err != nil {
+		return errors.Wrap(err, "Error appending the signer chain to the certificate")
+	}
+	return nil
+}
+
+// ValidateCA checks that the first certificate of the PEM file certPath is a
+// currently valid CA allowed to sign certificates, and that the PEM file
+// keyPath holds its RSA private key.
+func ValidateCA(certPath, keyPath string) error {
+	certBytes, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading CA certificate")
+	}
+	decoded, _ := pem.Decode(certBytes)
+	if decoded == nil || decoded.Type != "CERTIFICATE" {
+		return errors.Errorf("%s is not a PEM encoded certificate", certPath)
+	}
+	cert, err := x509.ParseCertificate(decoded.Bytes)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing CA certificate %s", certPath)
+	}
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return errors.Errorf("%s is not a CA certificate", certPath)
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return errors.Errorf("%s is not allowed to sign certificates", certPath)
+	}
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errors.Errorf("%s is only valid from %s to %s", certPath, cert.NotBefore, cert.NotAfter)
+	}
+
+	keyBytes, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return errors.Wrap(err, "Error reading CA key")
+	}
+	key, err := parseRSAPrivateKey(keyBytes)
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing CA key %s", keyPath)
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok || pub.N.Cmp(key.N) != 0 || pub.E != key.E {
+		return errors.Errorf("%s is not the key of %s", keyPath, certPath)
+	}
+	return nil
+}
+
+// parseRSAPrivateKey parses the first PEM block of keyBytes as a PKCS#1 or
+// PKCS#8 encoded RSA private key
+func parseRSAPrivateKey(keyBytes []byte) (*rsa.PrivateKey, error) {
+	decoded, _ := pem.Decode(keyBytes)
+	if decoded == nil {
+		return nil, errors.New("Unable to decode key.")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(decoded.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(decoded.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing private key")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("Unsupported private key type %T, only RSA keys are supported", key)
+	}
+	return rsaKey, nil
+}
+
+// newSerialNumber returns a random certificate serial number, so that rotated
+// certificates are never confused with the ones they replace
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error generating serial number")
+	}
+	return serial, nil
 }
 
 func loadOrGeneratePrivateKey(keyPath string) (*rsa.PrivateKey, error) {
@@ -161,3 +257,66 @@ func writeCertsAndKeys(template *x509.Certificate, certPath string, signeeKey *r
 
 	return nil
 }
+
+// CertInfo describes a certificate read from disk
+type CertInfo struct {
+	Path        string
+	Subject     string
+	Issuer      string
+	DNSNames    []string
+	IPAddresses []net.IP
+	NotBefore   time.Time
+	NotAfter    time.Time
+	IsCA        bool
+}
+
+// SANs returns the DNS names and IP addresses the certificate is valid for
+func (c *CertInfo) SANs() []string {
+	sans := append([]string{}, c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// ExpiresWithin returns whether the certificate has expired at now, or will
+// within d of it
+func (c *CertInfo) ExpiresWithin(d time.Duration, now time.Time) bool {
+	return !now.Add(d).Before(c.NotAfter)
+}
+
+// ReadCertInfo reads the first PEM encoded certificate of the file at path
+func ReadCertInfo(path string) (*CertInfo, error) {
+	certBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading certificate")
+	}
+	return ParseCertInfo(path, certBytes)
+}
+
+// ParseCertInfo parses the first PEM encoded certificate of certBytes, read
+// from path. Other PEM blocks before it, such as the key of the kubelet
+// client certificate, are skipped.
+func ParseCertInfo(path string, certBytes []byte) (*CertInfo, error) {
+	decoded, rest := pem.Decode(certBytes)
+	for decoded != nil && decoded.Type != "CERTIFICATE" {
+		decoded, rest = pem.Decode(rest)
+	}
+	if decoded == nil {
+		return nil, errors.Errorf("%s is not a PEM encoded certificate", path)
+	}
+	cert, err := x509.ParseCertificate(decoded.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing certificate %s", path)
+	}
+	return &CertInfo{
+		Path:        path,
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		DNSNames:    cert.DNSNames,
+		IPAddresses: cert.IPAddresses,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		IsCA:        cert.IsCA,
+	}, nil
+}
@@ -17,13 +17,19 @@ limitations under the License.
 package util
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -148,3 +154,214 @@ func TestGenerateSignedCert(t *testing.T) {
 		})
 	}
 }
+
+func TestReadCertInfo(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	caCertPath := filepath.Join(tmpDir, "ca.crt")
+	caKeyPath := filepath.Join(tmpDir, "ca.key")
+	if err := GenerateCACert(caCertPath, caKeyPath, "minikubeCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	certPath := filepath.Join(tmpDir, "apiserver.crt")
+	keyPath := filepath.Join(tmpDir, "apiserver.key")
+	ips := []net.IP{net.ParseIP("192.168.99.100")}
+	if err := GenerateSignedCert(certPath, keyPath, "minikube", ips, []string{"localhost"}, caCertPath, caKeyPath); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+
+	info, err := ReadCertInfo(certPath)
+	if err != nil {
+		t.Fatalf("ReadCertInfo() error = %v", err)
+	}
+	if info.Subject != "CN=minikube,O=system:masters" {
+		t.Errorf("Subject = %q", info.Subject)
+	}
+	if info.Issuer != "CN=minikubeCA" {
+		t.Errorf("Issuer = %q", info.Issuer)
+	}
+	if info.IsCA {
+		t.Errorf("Leaf cert reported as a CA")
+	}
+	if sans := strings.Join(info.SANs(), ","); sans != "localhost,192.168.99.100" {
+		t.Errorf("SANs() = %q", sans)
+	}
+	if info.ExpiresWithin(24*time.Hour, time.Now()) {
+		t.Errorf("Fresh cert expires within a day: %s", info.NotAfter)
+	}
+	if !info.ExpiresWithin(366*24*time.Hour, time.Now()) {
+		t.Errorf("Cert valid for more than a year: %s", info.NotAfter)
+	}
+
+	ca, err := ReadCertInfo(caCertPath)
+	if err != nil {
+		t.Fatalf("ReadCertInfo() error = %v", err)
+	}
+	if !ca.IsCA {
+		t.Errorf("CA cert not reported as a CA")
+	}
+
+	if _, err := ReadCertInfo(keyPath); err == nil {
+		t.Errorf("ReadCertInfo() of a key should have returned an error")
+	}
+}
+
+// generateIntermediateCACert writes a CA cert and key signed by the CA at
+// signerCertPath and signerKeyPath, followed by the signer in the cert file
+func generateIntermediateCACert(t *testing.T, certPath, keyPath, signerCertPath, signerKeyPath string) {
+	signerCertBytes, err := ioutil.ReadFile(signerCertPath)
+	if err != nil {
+		t.Fatalf("Error reading signer cert: %v", err)
+	}
+	decoded, _ := pem.Decode(signerCertBytes)
+	signerCert, err := x509.ParseCertificate(decoded.Bytes)
+	if err != nil {
+		t.Fatalf("Error parsing signer cert: %v", err)
+	}
+	signerKeyBytes, err := ioutil.ReadFile(signerKeyPath)
+	if err != nil {
+		t.Fatalf("Error reading signer key: %v", err)
+	}
+	signerKey, err := parseRSAPrivateKey(signerKeyBytes)
+	if err != nil {
+		t.Fatalf("Error parsing signer key: %v", err)
+	}
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(3),
+		Subject:               pkix.Name{CommonName: "intermediateCA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	if err := writeCertsAndKeys(&template, certPath, priv, keyPath, signerCert, signerKey); err != nil {
+		t.Fatalf("Error writing intermediate CA: %v", err)
+	}
+	chain, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		t.Fatalf("Error reading intermediate CA: %v", err)
+	}
+	if err := ioutil.WriteFile(certPath, append(chain, signerCertBytes...), 0644); err != nil {
+		t.Fatalf("Error writing CA chain: %v", err)
+	}
+}
+
+func TestValidateCA(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := func(name string) string { return filepath.Join(tmpDir, name) }
+	if err := GenerateCACert(path("root.crt"), path("root.key"), "rootCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	if err := GenerateCACert(path("other.crt"), path("other.key"), "otherCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	generateIntermediateCACert(t, path("intermediate.crt"), path("intermediate.key"), path("root.crt"), path("root.key"))
+	if err := GenerateSignedCert(path("leaf.crt"), path("leaf.key"), "minikube", nil, nil, path("root.crt"), path("root.key")); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+
+	// PKCS#8 encoded keys are accepted too
+	rootKey, err := parseRSAPrivateKey(mustReadFile(t, path("root.key")))
+	if err != nil {
+		t.Fatalf("Error parsing root key: %v", err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(rootKey)
+	if err != nil {
+		t.Fatalf("Error encoding root key: %v", err)
+	}
+	if err := ioutil.WriteFile(path("root-pkcs8.key"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0600); err != nil {
+		t.Fatalf("Error writing root key: %v", err)
+	}
+
+	var tests = []struct {
+		description string
+		cert        string
+		key         string
+		err         bool
+	}{
+		{description: "root CA", cert: "root.crt", key: "root.key"},
+		{description: "PKCS#8 key", cert: "root.crt", key: "root-pkcs8.key"},
+		{description: "intermediate CA", cert: "intermediate.crt", key: "intermediate.key"},
+		{description: "key of another CA", cert: "root.crt", key: "other.key", err: true},
+		{description: "leaf cert", cert: "leaf.crt", key: "leaf.key", err: true},
+		{description: "key as cert", cert: "root.key", key: "root.key", err: true},
+		{description: "missing key", cert: "root.crt", key: "missing.key", err: true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			err := ValidateCA(path(test.cert), path(test.key))
+			if err != nil && !test.err {
+				t.Errorf("ValidateCA() error = %v", err)
+			}
+			if err == nil && test.err {
+				t.Errorf("ValidateCA() should have returned error, but didn't")
+			}
+		})
+	}
+}
+
+func TestGenerateSignedCertWithIntermediateCA(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("Error generating tmpdir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := func(name string) string { return filepath.Join(tmpDir, name) }
+	if err := GenerateCACert(path("root.crt"), path("root.key"), "rootCA"); err != nil {
+		t.Fatalf("GenerateCACert() error = %v", err)
+	}
+	generateIntermediateCACert(t, path("intermediate.crt"), path("intermediate.key"), path("root.crt"), path("root.key"))
+	if err := GenerateSignedCert(path("leaf.crt"), path("leaf.key"), "minikube", nil, []string{"minikube"}, path("intermediate.crt"), path("intermediate.key")); err != nil {
+		t.Fatalf("GenerateSignedCert() error = %v", err)
+	}
+
+	// The leaf cert is followed by the chain up to the root
+	var certs []*x509.Certificate
+	rest := mustReadFile(t, path("leaf.crt"))
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("Error parsing certificate: %v", err)
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) != 3 {
+		t.Fatalf("Expected the leaf cert and a chain of 2, got %d certs", len(certs))
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(certs[2])
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(certs[1])
+	if _, err := certs[0].Verify(x509.VerifyOptions{DNSName: "minikube", Roots: roots, Intermediates: intermediates}); err != nil {
+		t.Errorf("Leaf cert does not chain to the root CA: %v", err)
+	}
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Error reading %s: %v", path, err)
+	}
+	return data
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	// Register the hashes that checksums can be published with
+	_ "crypto/sha1"
+	_ "crypto/sha512"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	pb "gopkg.in/cheggaaa/pb.v1"
+)
+
+// ChecksumSuffix is appended to the path of a cached file to name the
+// sidecar file holding its SHA-256 checksum
+const ChecksumSuffix = ".sha256"
+
+// DownloadOptions configures DownloadToCache
+type DownloadOptions struct {
+	// ChecksumURL is the location of the published checksum of the file
+	ChecksumURL string
+	// ChecksumHash is the hash function of the published checksum
+	ChecksumHash crypto.Hash
+	// ShowProgress displays a progress bar on stdout
+	ShowProgress bool
+}
+
+// partialSuffix is appended to the path of a cached file to name the
+// partial download, until it is complete
+const partialSuffix = ".download"
+
+// validatorSuffix is appended to the path of a partial download to name the
+// file holding the ETag or Last-Modified date it was downloaded with
+const validatorSuffix = ".validator"
+
+// ChecksumFile returns the path of the checksum sidecar of a cached file.
+func ChecksumFile(path string) string {
+	return path + ChecksumSuffix
+}
+
+// IsCached returns whether the file at path was completely downloaded.
+// Downloads are only moved to path once complete, so files cached before
+// checksum sidecars existed count too.
+func IsCached(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// EnsureChecksum writes the checksum sidecar of the cached file at path if
+// it has none, as for files cached before sidecars existed.
+func EnsureChecksum(path string) error {
+	if _, err := os.Stat(ChecksumFile(path)); !os.IsNotExist(err) {
+		return err
+	}
+	glog.Infof("Writing missing checksum of %s", path)
+	return WriteChecksum(path)
+}
+
+// WriteChecksum records the SHA-256 checksum of the file at path in its sidecar.
+func WriteChecksum(path string) error {
+	sum, err := hashFile(path, sha256.New())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(ChecksumFile(path), []byte(sum+"\n"), 0644)
+}
+
+// VerifyChecksum hashes the file at path and compares it to its sidecar.
+func VerifyChecksum(path string) error {
+	return VerifyChecksumFile(path, ChecksumFile(path))
+}
+
+// VerifyChecksumFile hashes the file at path and compares it to the SHA-256
+// checksum in the file at checksumPath.
+func VerifyChecksumFile(path, checksumPath string) error {
+	expected, err := ioutil.ReadFile(checksumPath)
+	if err != nil {
+		return errors.Wrap(err, "reading checksum")
+	}
+	actual, err := hashFile(path, sha256.New())
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(expected)) != actual {
+		return fmt.Errorf("checksum of %s is %s, expected %s", path, actual, strings.TrimSpace(string(expected)))
+	}
+	return nil
+}
+
+// EvictCached removes a cached file and its checksum sidecar.
+func EvictCached(path string) error {
+	for _, p := range []string{path, ChecksumFile(path)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownloadToCache downloads url to path, unless it is already cached.
+// A partial download left by an interrupted attempt is resumed with an HTTP
+// range request, if the server still serves the same file. The result is
+// checked against the published checksum if there is one, and its checksum
+// sidecar is written.
+func DownloadToCache(url, path string, opts DownloadOptions) error {
+	if IsCached(path) {
+		return EnsureChecksum(path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrapf(err, "mkdir %s", filepath.Dir(path))
+	}
+	partial := path + partialSuffix
+	if err := resumeDownload(url, partial, opts.ShowProgress); err != nil {
+		return err
+	}
+
+	if opts.ChecksumURL != "" {
+		if err := verifyPublishedChecksum(partial, opts.ChecksumURL, opts.ChecksumHash); err != nil {
+			// Start over next time rather than resuming a corrupt download
+			removePartial(partial)
+			return err
+		}
+	}
+	if err := os.Rename(partial, path); err != nil {
+		return errors.Wrapf(err, "renaming %s", partial)
+	}
+	if err := os.Remove(partial + validatorSuffix); err != nil && !os.IsNotExist(err) {
+		glog.Warningf("Error removing %s: %s", partial+validatorSuffix, err)
+	}
+	return WriteChecksum(path)
+}
+
+// removePartial removes a partial download and its validator.
+func removePartial(partial string) {
+	for _, p := range []string{partial, partial + validatorSuffix} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			glog.Warningf("Error removing %s: %s", p, err)
+		}
+	}
+}
+
+// resumeDownload appends the part of url missing from the partial download
+// at path to it. The download is only resumed if the server still has the
+// file that was partially downloaded, as told by the validator it was
+// downloaded with. It is started over otherwise.
+func resumeDownload(url, path string, showProgress bool) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrapf(err, "seeking %s", path)
+	}
+	validator, err := ioutil.ReadFile(path + validatorSuffix)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "reading validator of %s", path)
+	}
+	if offset > 0 && len(validator) == 0 {
+		glog.Infof("Restarting download of %s, the partial download cannot be validated", url)
+		if offset, err = truncate(f); err != nil {
+			return errors.Wrapf(err, "truncating %s", path)
+		}
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return errors.Wrapf(err, "creating request for %s", url)
+	}
+	if offset > 0 {
+		glog.Infof("Resuming download of %s at byte %d", url, offset)
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.Header.Set("If-Range", string(validator))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "downloading %s", url)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range, or the file changed: start over
+		if offset > 0 {
+			if offset, err = truncate(f); err != nil {
+				return errors.Wrapf(err, "truncating %s", path)
+			}
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial download is complete if it has the size of the file
+		if offset > 0 && resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return f.Close()
+		}
+		if offset > 0 {
+			glog.Infof("Restarting download of %s, the partial download is larger than the file", url)
+			f.Close()
+			removePartial(path)
+			return resumeDownload(url, path, showProgress)
+		}
+		fallthrough
+	default:
+		return errors.Errorf("downloading %s. Got HTTP Error: %s", url, resp.Status)
+	}
+
+	if err := writeValidator(path, resp.Header); err != nil {
+		return err
+	}
+	var body io.Reader = resp.Body
+	if showProgress && resp.ContentLength > 0 {
+		bar := pb.New64(offset + resp.ContentLength).SetUnits(pb.U_BYTES).SetMaxWidth(80)
+		bar.Set64(offset)
+		bar.Start()
+		defer bar.Finish()
+		body = bar.NewProxyReader(body)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		return errors.Wrapf(err, "downloading %s", url)
+	}
+	return f.Close()
+}
+
+// truncate empties the partial download f, and returns the new offset.
+func truncate(f *os.File) (int64, error) {
+	if err := f.Truncate(0); err != nil {
+		return 0, err
+	}
+	return f.Seek(0, io.SeekStart)
+}
+
+// writeValidator records the strong ETag or the Last-Modified date of the
+// response next to the partial download at path, so that it can be resumed
+// with an If-Range request. Weak ETags cannot be used for ranges.
+func writeValidator(path string, header http.Header) error {
+	validator := header.Get("ETag")
+	if strings.HasPrefix(validator, "W/") {
+		validator = ""
+	}
+	if validator == "" {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		if err := os.Remove(path + validatorSuffix); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "removing validator of %s", path)
+		}
+		return nil
+	}
+	if err := ioutil.WriteFile(path+validatorSuffix, []byte(validator), 0644); err != nil {
+		return errors.Wrapf(err, "writing validator of %s", path)
+	}
+	return nil
+}
+
+// verifyPublishedChecksum compares the file at path with the checksum
+// published at checksumURL.
+func verifyPublishedChecksum(path, checksumURL string, h crypto.Hash) error {
+	published, err := ParseSHAFromURL(checksumURL)
+	if err != nil {
+		return err
+	}
+	// Checksum files may be in the "checksum  filename" format of sha256sum
+	fields := strings.Fields(published)
+	if len(fields) == 0 {
+		return fmt.Errorf("empty checksum at %s", checksumURL)
+	}
+	if !h.Available() {
+		return fmt.Errorf("unsupported checksum hash for %s", checksumURL)
+	}
+	actual, err := hashFile(path, h.New())
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(fields[0], actual) {
+		return fmt.Errorf("checksum of %s is %s, but %s publishes %s", path, actual, checksumURL, fields[0])
+	}
+	return nil
+}
+
+func hashFile(path string, h hash.Hash) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "hashing %s", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CacheEntry is the result of verifying a cached file
+type CacheEntry struct {
+	Path string
+	Err  error
+}
+
+// VerifyCacheDir hashes every file under dir that has a checksum sidecar
+// and compares it to the sidecar.
+func VerifyCacheDir(dir string) ([]CacheEntry, error) {
+	var entries []CacheEntry
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ChecksumSuffix) {
+			return nil
+		}
+		file := strings.TrimSuffix(path, ChecksumSuffix)
+		entries = append(entries, CacheEntry{Path: file, Err: VerifyChecksum(file)})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return entries, err
+}
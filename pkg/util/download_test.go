@@ -0,0 +1,280 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testDownload = []byte("the contents of a cached artifact")
+
+// testETag is the ETag the download server serves testDownload with
+const testETag = `"v2"`
+
+func newDownloadServer(t *testing.T, ranges *[]string) *httptest.Server {
+	sum := sha256.Sum256(testDownload)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/artifact":
+			*ranges = append(*ranges, r.Header.Get("Range"))
+			w.Header().Set("ETag", testETag)
+			http.ServeContent(w, r, "artifact", time.Time{}, bytes.NewReader(testDownload))
+		case "/artifact.sha256":
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  artifact\n"))
+		case "/bad.sha256":
+			w.Write([]byte(strings.Repeat("0", 64)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestDownloadToCache(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	var ranges []string
+	server := newDownloadServer(t, &ranges)
+	defer server.Close()
+
+	path := filepath.Join(tempDir, "cache", "artifact")
+	opts := DownloadOptions{ChecksumURL: server.URL + "/artifact.sha256", ChecksumHash: crypto.SHA256}
+	if err := DownloadToCache(server.URL+"/artifact", path, opts); err != nil {
+		t.Fatalf("Error downloading: %s", err)
+	}
+	if !IsCached(path) {
+		t.Fatalf("Expected %s to be cached", path)
+	}
+	if err := VerifyChecksum(path); err != nil {
+		t.Fatalf("Error verifying checksum: %s", err)
+	}
+
+	// Cached files are not downloaded again
+	if err := DownloadToCache(server.URL+"/artifact", path, opts); err != nil {
+		t.Fatalf("Error downloading: %s", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "" {
+		t.Fatalf("Unexpected requests: %v", ranges)
+	}
+}
+
+func TestDownloadToCacheResume(t *testing.T) {
+	var tests = []struct {
+		description string
+		partial     []byte
+		validator   string
+		checksumURL string
+		ranges      []string
+	}{
+		{
+			description: "same file",
+			partial:     testDownload[:10],
+			validator:   testETag,
+			checksumURL: "/artifact.sha256",
+			ranges:      []string{"bytes=10-"},
+		},
+		{
+			description: "changed file",
+			partial:     []byte("an older a"),
+			validator:   `"v1"`,
+			checksumURL: "/artifact.sha256",
+			ranges:      []string{"bytes=10-"},
+		},
+		{
+			description: "no validator",
+			partial:     []byte("an older a"),
+			ranges:      []string{""},
+		},
+		{
+			description: "complete",
+			partial:     testDownload,
+			validator:   testETag,
+			ranges:      []string{fmt.Sprintf("bytes=%d-", len(testDownload))},
+		},
+		{
+			description: "larger than the file",
+			partial:     append(append([]byte{}, testDownload...), "trailing"...),
+			validator:   testETag,
+			ranges:      []string{fmt.Sprintf("bytes=%d-", len(testDownload)+8), ""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "download")
+			if err != nil {
+				t.Fatalf("Error creating temp dir: %s", err)
+			}
+			defer os.RemoveAll(tempDir)
+			var ranges []string
+			server := newDownloadServer(t, &ranges)
+			defer server.Close()
+
+			// An interrupted download leaves a partial file next to path
+			path := filepath.Join(tempDir, "artifact")
+			if err := ioutil.WriteFile(path+partialSuffix, test.partial, 0644); err != nil {
+				t.Fatalf("Error writing partial download: %s", err)
+			}
+			if test.validator != "" {
+				if err := ioutil.WriteFile(path+partialSuffix+validatorSuffix, []byte(test.validator), 0644); err != nil {
+					t.Fatalf("Error writing validator: %s", err)
+				}
+			}
+			if IsCached(path) {
+				t.Fatalf("Expected a partial download not to be cached")
+			}
+			opts := DownloadOptions{}
+			if test.checksumURL != "" {
+				opts = DownloadOptions{ChecksumURL: server.URL + test.checksumURL, ChecksumHash: crypto.SHA256}
+			}
+			if err := DownloadToCache(server.URL+"/artifact", path, opts); err != nil {
+				t.Fatalf("Error resuming download: %s", err)
+			}
+			if !reflect.DeepEqual(ranges, test.ranges) {
+				t.Fatalf("Expected requests with ranges %q, got: %q", test.ranges, ranges)
+			}
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Error reading download: %s", err)
+			}
+			if !bytes.Equal(contents, testDownload) {
+				t.Fatalf("Unexpected contents: %s", contents)
+			}
+			if err := VerifyChecksum(path); err != nil {
+				t.Fatalf("Error verifying checksum: %s", err)
+			}
+			for _, p := range []string{path + partialSuffix, path + partialSuffix + validatorSuffix} {
+				if _, err := os.Stat(p); !os.IsNotExist(err) {
+					t.Fatalf("Expected %s to be removed", p)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadToCacheWithoutChecksum(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	var ranges []string
+	server := newDownloadServer(t, &ranges)
+	defer server.Close()
+
+	// Files cached by older versions have no checksum sidecar
+	path := filepath.Join(tempDir, "artifact")
+	if err := ioutil.WriteFile(path, testDownload, 0644); err != nil {
+		t.Fatalf("Error writing cached file: %s", err)
+	}
+	if !IsCached(path) {
+		t.Fatalf("Expected %s to be cached", path)
+	}
+	if err := DownloadToCache(server.URL+"/artifact", path, DownloadOptions{}); err != nil {
+		t.Fatalf("Error downloading: %s", err)
+	}
+	if len(ranges) != 0 {
+		t.Fatalf("Expected no download, got: %v", ranges)
+	}
+	if err := VerifyChecksum(path); err != nil {
+		t.Fatalf("Expected the checksum to be written: %s", err)
+	}
+}
+
+func TestDownloadToCacheChecksumMismatch(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+	var ranges []string
+	server := newDownloadServer(t, &ranges)
+	defer server.Close()
+
+	path := filepath.Join(tempDir, "artifact")
+	opts := DownloadOptions{ChecksumURL: server.URL + "/bad.sha256", ChecksumHash: crypto.SHA256}
+	if err := DownloadToCache(server.URL+"/artifact", path, opts); err == nil {
+		t.Fatalf("Expected a checksum error")
+	}
+	for _, p := range []string{path, path + partialSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Fatalf("Expected the corrupt download to be removed")
+		}
+	}
+}
+
+func TestVerifyCacheDir(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("Error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	good := filepath.Join(tempDir, "iso", "good.iso")
+	bad := filepath.Join(tempDir, "v1.9.4", "kubeadm")
+	partial := filepath.Join(tempDir, "v1.9.4", "kubelet")
+	for _, p := range []string{good, bad, partial} {
+		if err := os.MkdirAll(filepath.Dir(p), 0777); err != nil {
+			t.Fatalf("Error creating dir: %s", err)
+		}
+		if err := ioutil.WriteFile(p, testDownload, 0644); err != nil {
+			t.Fatalf("Error writing %s: %s", p, err)
+		}
+	}
+	for _, p := range []string{good, bad} {
+		if err := WriteChecksum(p); err != nil {
+			t.Fatalf("Error writing checksum: %s", err)
+		}
+	}
+	if err := ioutil.WriteFile(bad, []byte("corrupted"), 0644); err != nil {
+		t.Fatalf("Error corrupting %s: %s", bad, err)
+	}
+
+	entries, err := VerifyCacheDir(tempDir)
+	if err != nil {
+		t.Fatalf("Error verifying cache: %s", err)
+	}
+	results := map[string]bool{}
+	for _, e := range entries {
+		results[e.Path] = e.Err == nil
+	}
+	expected := map[string]bool{good: true, bad: false}
+	if len(results) != len(expected) || results[good] != true || results[bad] != false {
+		t.Fatalf("Unexpected verification results: %v", results)
+	}
+
+	if err := EvictCached(bad); err != nil {
+		t.Fatalf("Error evicting %s: %s", bad, err)
+	}
+	if _, err := os.Stat(ChecksumFile(bad)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the checksum of %s to be removed", bad)
+	}
+}
@@ -20,11 +20,9 @@ import (
 	"crypto"
 	"fmt"
 	"net/url"
-	"os"
 	"path/filepath"
 
 	"github.com/golang/glog"
-	download "github.com/jimmidyson/go-download"
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/constants"
 )
@@ -57,23 +55,16 @@ func (f DefaultDownloader) CacheMinikubeISOFromURL(isoURL string) error {
 		return nil
 	}
 
-	options := download.FileOptions{
-		Mkdirs: download.MkdirAll,
-		Options: download.Options{
-			ProgressBars: &download.ProgressBarOptions{
-				MaxWidth: 80,
-			},
-		},
-	}
+	options := DownloadOptions{ShowProgress: true}
 
-	// Validate the ISO if it was the default URL, before writing it to disk.
+	// Validate the ISO if it was the default URL
 	if isoURL == constants.DefaultIsoUrl {
-		options.Checksum = constants.DefaultIsoShaUrl
+		options.ChecksumURL = constants.DefaultIsoShaUrl
 		options.ChecksumHash = crypto.SHA256
 	}
 
 	fmt.Println("Downloading Minikube ISO")
-	if err := download.ToFile(isoURL, f.GetISOCacheFilepath(isoURL), options); err != nil {
+	if err := DownloadToCache(isoURL, f.GetISOCacheFilepath(isoURL), options); err != nil {
 		return errors.Wrap(err, "Error downloading Minikube ISO")
 	}
 
@@ -100,9 +91,7 @@ func (f DefaultDownloader) GetISOCacheFilepath(isoURL string) string {
 	return filepath.Join(constants.GetMinipath(), "cache", "iso", filepath.Base(isoURL))
 }
 
+// IsMinikubeISOCached returns whether the ISO was completely downloaded.
 func (f DefaultDownloader) IsMinikubeISOCached(isoURL string) bool {
-	if _, err := os.Stat(f.GetISOCacheFilepath(isoURL)); os.IsNotExist(err) {
-		return false
-	}
-	return true
+	return IsCached(f.GetISOCacheFilepath(isoURL))
 }
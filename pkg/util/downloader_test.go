@@ -103,7 +103,18 @@ func TestIsMinikubeISOCached(t *testing.T) {
 		t.Fatalf("Expected IsMinikubeISOCached with input %s to return %t but instead got: %t", testFileURI, expected, out)
 	}
 
-	ioutil.WriteFile(filepath.Join(constants.GetMinipath(), "cache", "iso", "minikube-test.iso"), []byte(testISOString), os.FileMode(int(0644)))
+	isoPath := filepath.Join(constants.GetMinipath(), "cache", "iso", "minikube-test.iso")
+
+	// A partial download is not cached
+	if err := ioutil.WriteFile(isoPath+partialSuffix, []byte(testISOString), 0644); err != nil {
+		t.Fatalf("Error writing partial download: %s", err)
+	}
+	if out := dler.IsMinikubeISOCached(testFileURI); out != expected {
+		t.Fatalf("Expected IsMinikubeISOCached with input %s to return %t but instead got: %t", testFileURI, expected, out)
+	}
+
+	// ISOs cached by older versions have no checksum
+	ioutil.WriteFile(isoPath, []byte(testISOString), os.FileMode(int(0644)))
 
 	expected = true
 	if out := dler.IsMinikubeISOCached(testFileURI); out != expected {
@@ -18,6 +18,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -26,6 +27,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -33,6 +35,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
 	commonutil "k8s.io/minikube/pkg/util"
 )
 
@@ -56,6 +59,49 @@ func (m *MinikubeRunner) Copy(f assets.CopyableFile) error {
 	return cmd.Run()
 }
 
+// RunCmd runs the command of the request in the VM with minikube ssh. The
+// exit code is the one of minikube ssh.
+func (m *MinikubeRunner) RunCmd(req bootstrapper.RunRequest) (*bootstrapper.RunResult, error) {
+	rr := &bootstrapper.RunResult{Args: req.Args}
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+	path, _ := filepath.Abs(m.BinaryPath)
+	cmd := exec.CommandContext(ctx, path, "ssh", "--", req.ShellCommand())
+	cmd.Stdin = req.Stdin
+	cmd.Stdout, cmd.Stderr = req.Stdout, req.Stderr
+	if cmd.Stdout == nil {
+		cmd.Stdout = &rr.Stdout
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = &rr.Stderr
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	rr.Duration = time.Since(start)
+	if ctx.Err() != nil {
+		rr.ExitCode = -1
+		return rr, ctx.Err()
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			rr.ExitCode = status.ExitStatus()
+			return rr, &bootstrapper.ExitError{Args: req.Args, ExitCode: rr.ExitCode, Stderr: rr.Stderr.String()}
+		}
+	}
+	if err != nil {
+		rr.ExitCode = -1
+	}
+	return rr, err
+}
+
 func (m *MinikubeRunner) CombinedOutput(cmd string) (string, error) {
 	return m.SSH(cmd)
 }